@@ -0,0 +1,82 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// aqiCategory ist eine Stufe des europäischen Luftqualitätsindex (EEA)
+type aqiCategory struct {
+	label      string
+	emoji      string
+	pm25, pm10 float64 // Obergrenze in µg/m³
+}
+
+// aqiCategories nach den Schwellen der Europäischen Umweltagentur, aufsteigend
+var aqiCategories = []aqiCategory{
+	{"gut", "🔵", 10, 20},
+	{"mittelmäßig", "🟢", 20, 40},
+	{"mäßig", "🟡", 25, 50},
+	{"schlecht", "🟠", 50, 100},
+	{"sehr schlecht", "🔴", 75, 150},
+	{"extrem schlecht", "🟣", math.Inf(1), math.Inf(1)},
+}
+
+// getAirQuality ermittelt Tagesmittel und Maximum von PM2.5 und PM10 (µg/m³).
+// Ohne Feinstaubsensor bzw. ohne Werte sind die zugehörigen Werte NaN.
+func getAirQuality(db *sql.DB, config Config, start, end int64) (pm25Mean, pm25Max, pm10Mean, pm10Max float64, err error) {
+	pm25Mean, pm25Max, err = columnMeanMax(db, config.PM25Column, start, end)
+	if err != nil {
+		return
+	}
+	pm10Mean, pm10Max, err = columnMeanMax(db, config.PM10Column, start, end)
+	return
+}
+
+// columnMeanMax liefert AVG und MAX einer optionalen archive-Spalte, NaN wenn sie fehlt oder leer ist
+func columnMeanMax(db *sql.DB, name string, start, end int64) (float64, float64, error) {
+	column := optionalColumn(db, name)
+	if column == "" {
+		return math.NaN(), math.NaN(), nil
+	}
+	var mean, max sql.NullFloat64
+	q := `SELECT AVG(` + column + `), MAX(` + column + `) FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	if err := db.QueryRow(q, start, end).Scan(&mean, &max); err != nil {
+		return math.NaN(), math.NaN(), err
+	}
+	if !mean.Valid || !max.Valid {
+		return math.NaN(), math.NaN(), nil
+	}
+	return mean.Float64, max.Float64, nil
+}
+
+// airQualityCategory bestimmt die Indexstufe aus den Tagesmitteln; maßgeblich ist der schlechtere Schadstoff
+func airQualityCategory(s dayStats) (aqiCategory, bool) {
+	if math.IsNaN(s.pm25Mean) && math.IsNaN(s.pm10Mean) {
+		return aqiCategory{}, false
+	}
+	for _, c := range aqiCategories {
+		if (math.IsNaN(s.pm25Mean) || s.pm25Mean <= c.pm25) && (math.IsNaN(s.pm10Mean) || s.pm10Mean <= c.pm10) {
+			return c, true
+		}
+	}
+	return aqiCategories[len(aqiCategories)-1], true
+}
+
+// formatAirQuality erzeugt die Zeile zur Luftqualität; ohne Feinstaubsensor bleibt sie leer
+func formatAirQuality(s dayStats, config Config) string {
+	category, ok := airQualityCategory(s)
+	if !ok {
+		return ""
+	}
+	var parts []string
+	if !math.IsNaN(s.pm25Mean) {
+		parts = append(parts, fmt.Sprintf("PM2.5 Ø %.1f µg/m³, max %.1f", s.pm25Mean, s.pm25Max))
+	}
+	if !math.IsNaN(s.pm10Mean) {
+		parts = append(parts, fmt.Sprintf("PM10 Ø %.1f µg/m³, max %.1f", s.pm10Mean, s.pm10Max))
+	}
+	return category.emoji + tr(config, " Luftqualität: %s (%s)", tr(config, category.label), strings.Join(parts, "; "))
+}
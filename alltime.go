@@ -0,0 +1,217 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// allTimeRecord beschreibt einen Stationsrekord über die gesamte Aufzeichnung und, für Temperatur und Niederschlag,
+// die Rekorde für denselben Kalendertag und Kalendermonat
+type allTimeRecord struct {
+	label     string // z.B. "heißester Tag"
+	adjective string // für Kalenderrekorde, z.B. "wärmster" (17. Januar bzw. Januartag)
+	quantity  string // Messgröße für die Anzeigeeinheit
+	decimals  int    // Nachkommastellen (metrisch)
+	format    string // Formatierung des Wertes mit Einheit (%s)
+	higher    bool   // true: größter Wert ist Rekord, false: kleinster
+	value     func(s dayStats) float64
+	series    dailyValues // Tageswerte nach daily_source und rain_day_start_hour, nil: Sonnenscheindauer aus dem Zustandsspeicher
+	annotate  string      // Tageswert, den die Diagramme als Rekord beschriften
+}
+
+// allTimeRecords sind die geprüften Stationsrekorde
+var allTimeRecords = []allTimeRecord{
+	{"heißester Tag", "wärmster", quantityTemperature, 1, "%s", true, func(s dayStats) float64 { return s.tMax }, dailyMaxima, annotateTMax},
+	{"kältester Tag", "kältester", quantityTemperature, 1, "%s", false, func(s dayStats) float64 { return s.tMin }, dailyMinima, annotateTMin},
+	{"nassester Tag", "nassester", quantityRain, 1, "%s", true, func(s dayStats) float64 { return s.rainSum }, dailyRainSums, annotateRain},
+	{"sonnigster Tag", "", quantitySunshine, 1, "%s Sonnenschein", true, func(s dayStats) float64 { return s.sunMinutes / 60 }, nil, annotateSun},
+}
+
+// formatValue formatiert einen Wert mit Einheit in der Sprache der Posts
+func (r allTimeRecord) formatValue(config Config, v float64) string {
+	return tr(config, r.format, unitOf(config, r.quantity).format(v, r.decimals))
+}
+
+// beats prüft, ob v den bisherigen Rekord prev übertrifft
+func (r allTimeRecord) beats(v, prev float64) bool {
+	if r.higher {
+		return v > prev
+	}
+	return v < prev
+}
+
+// best liefert den Rekordwert der Tage [from, to] (inklusive) mit Datum, bei gleichen Werten den frühesten Tag;
+// ok ist false ohne Werte. Lange Zeiträume werden blockweise gelesen.
+func (r allTimeRecord) best(db *sql.DB, config Config, from, to time.Time) (time.Time, float64, bool, error) {
+	var best dailyValue
+	ok := false
+	err := eachDailyValue(db, config, r.series, from, to, func(v dailyValue) {
+		if !ok || r.beats(v.value, best.value) {
+			best, ok = v, true
+		}
+	})
+	if err != nil || !ok {
+		return time.Time{}, 0, false, err
+	}
+	return best.day, best.value, true, nil
+}
+
+// previous liefert den bisherigen Rekord vor dem Tag before mit Datum; ok ist false ohne Vergleichswert
+func (r allTimeRecord) previous(db, store *sql.DB, config Config, before time.Time) (time.Time, float64, bool, error) {
+	if r.series == nil {
+		return storeSunRecord(store, config, before)
+	}
+	first, ok := firstArchiveDay(db, before.Location())
+	if !ok {
+		return time.Time{}, 0, false, nil
+	}
+	return r.best(db, config, first, before.AddDate(0, 0, -1))
+}
+
+// storeSunRecord liefert die bisher längste Sonnenscheindauer eines Tages (h) aus daily_stats. Die Sonnenscheindauer wird
+// erst seit einer neueren Version gespeichert; solange sie kürzer als record_min_history_days vorliegt, gibt es keinen Rekord.
+func storeSunRecord(store *sql.DB, config Config, before time.Time) (time.Time, float64, bool, error) {
+	if store == nil {
+		return time.Time{}, 0, false, nil
+	}
+	var first sql.NullString
+	if err := store.QueryRow(`SELECT MIN(day) FROM daily_stats WHERE sun_minutes IS NOT NULL;`).Scan(&first); err != nil {
+		return time.Time{}, 0, false, err
+	}
+	if !first.Valid || before.AddDate(0, 0, -config.RecordMinHistoryDays).Format("2006-01-02") < first.String {
+		return time.Time{}, 0, false, nil
+	}
+	var day string
+	var v float64
+	err := store.QueryRow(`SELECT day, sun_minutes FROM daily_stats WHERE day < ? AND sun_minutes IS NOT NULL
+		ORDER BY sun_minutes DESC LIMIT 1;`, before.Format("2006-01-02")).Scan(&day, &v)
+	if err == sql.ErrNoRows {
+		return time.Time{}, 0, false, nil
+	}
+	if err != nil {
+		return time.Time{}, 0, false, err
+	}
+	return mustParseDay(day), v / 60, true, nil
+}
+
+// calendarRecord prüft, ob v den Rekord für denselben Kalendermonat (month) bzw. Kalendertag in allen Jahren seit
+// firstYear übertrifft. Geliefert werden der bisherige Rekord mit Datum und die Zahl der Jahre mit Daten (inklusive day).
+func (r allTimeRecord) calendarRecord(db *sql.DB, config Config, day time.Time, firstYear int, month bool, v float64) (time.Time, float64, int, bool, error) {
+	loc := day.Location()
+	var prevDay time.Time
+	prev := math.NaN()
+	years := 1
+	for y := firstYear; y <= day.Year(); y++ {
+		var from, to time.Time
+		if month {
+			from = time.Date(y, day.Month(), 1, 0, 0, 0, 0, loc)
+			to = from.AddDate(0, 1, -1)
+			if y == day.Year() {
+				to = day.AddDate(0, 0, -1) // frühere Tage des laufenden Monats
+			}
+		} else {
+			if y == day.Year() {
+				break
+			}
+			from = time.Date(y, day.Month(), day.Day(), 0, 0, 0, 0, loc)
+			if from.Day() != day.Day() {
+				continue // 29. Februar
+			}
+			to = from
+		}
+		d, best, ok, err := r.best(db, config, from, to)
+		if err != nil {
+			return prevDay, prev, years, false, err
+		}
+		if !ok {
+			continue
+		}
+		if y < day.Year() {
+			years++
+		}
+		if math.IsNaN(prev) || r.beats(best, prev) {
+			prevDay, prev = d, best
+		}
+	}
+	return prevDay, prev, years, !math.IsNaN(prev) && r.beats(v, prev), nil
+}
+
+// formatCalendarRecord erzeugt den Satz zum Rekord für den Kalendermonat oder, falls keiner, für den Kalendertag,
+// z.B. "🏅 Wärmster 17. Januar seit Messbeginn (4 Jahre Daten): 12.3 °C, bisher 10.1 °C am 17.01.2024", und die
+// Beschriftung für die Diagramme, z.B. "Wärmster 17. Januar"
+func (r allTimeRecord) formatCalendarRecord(db *sql.DB, config Config, day time.Time, firstYear int, v float64) (string, string) {
+	if r.adjective == "" {
+		return "", ""
+	}
+	for _, month := range []bool{true, false} {
+		minYears := config.CalendarDayRecordMinYears
+		if month {
+			minYears = config.CalendarMonthRecordMinYears
+		}
+		if minYears <= 0 {
+			continue
+		}
+		prevDay, prev, years, ok, err := r.calendarRecord(db, config, day, firstYear, month, v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warnung: Kalenderrekord (%s) nicht prüfbar: %v\n", r.label, err)
+			return "", ""
+		}
+		if !ok || years < minYears {
+			continue
+		}
+		what := tr(config, "%d. %s", day.Day(), monthName(config, day.Month()))
+		if month {
+			what = tr(config, "%stag", monthName(config, day.Month()))
+		}
+		adjective := tr(config, r.adjective)
+		adjective = strings.ToUpper(adjective[:1]) + adjective[1:]
+		return tr(config, "🏅 %s %s seit Messbeginn (%d Jahre Daten): %s, bisher %s am %s",
+			adjective, what, years, r.formatValue(config, v), r.formatValue(config, prev), formatDate(config, prevDay)), adjective + " " + what
+	}
+	return "", ""
+}
+
+// formatAllTimeRecords prüft, ob day einen Stationsrekord aufgestellt hat, z.B.
+// "🏆 Stationsrekord: heißester Tag seit Messbeginn 2023 mit 38.2 °C (bisher 36.1 °C am 19.07.2024)".
+// In den ersten record_min_history_days Tagen der Aufzeichnung gibt es keine Rekorde. Ist es kein Rekord über die
+// gesamte Aufzeichnung, wird ein Rekord für den Kalendermonat oder Kalendertag genannt, sobald genügend Jahre mit
+// Daten vorliegen (calendar_month_record_min_years, calendar_day_record_min_years). Die Rekorde werden zusätzlich
+// als Beschriftung für die Diagramme geliefert.
+func formatAllTimeRecords(db, store *sql.DB, config Config, day time.Time, s dayStats) (string, []chartAnnotation) {
+	start, ok := firstArchiveDay(db, day.Location())
+	if !ok {
+		return "", nil
+	}
+	if day.Sub(start) < time.Duration(config.RecordMinHistoryDays)*24*time.Hour {
+		return "", nil
+	}
+
+	var lines []string
+	var annotations []chartAnnotation
+	for _, r := range allTimeRecords {
+		v := r.value(s)
+		if math.IsNaN(v) {
+			continue
+		}
+		prevDay, prev, ok, err := r.previous(db, store, config, day)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warnung: Stationsrekord (%s) nicht prüfbar: %v\n", r.label, err)
+			continue
+		}
+		if ok && r.beats(v, prev) {
+			lines = append(lines, tr(config, "🏆 Stationsrekord: %s seit Messbeginn %d mit %s (bisher %s am %s)",
+				tr(config, r.label), start.Year(), r.formatValue(config, v), r.formatValue(config, prev), formatDate(config, prevDay)))
+			annotations = append(annotations, chartAnnotation{section: "records", value: r.annotate, label: tr(config, "Stationsrekord")})
+			continue
+		}
+		if line, label := r.formatCalendarRecord(db, config, day, start.Year(), v); line != "" {
+			lines = append(lines, line)
+			annotations = append(annotations, chartAnnotation{section: "records", value: r.annotate, label: label})
+		}
+	}
+	return strings.Join(lines, "\n"), annotations
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAllTimeRecordBeats(t *testing.T) {
+	hottest, coldest := allTimeRecords[0], allTimeRecords[1]
+	tests := []struct {
+		name    string
+		r       allTimeRecord
+		v, prev float64
+		want    bool
+	}{
+		{"wärmer", hottest, 35.1, 35.0, true},
+		{"gleich warm", hottest, 35.0, 35.0, false},
+		{"kälter", coldest, -15.2, -15.0, true},
+		{"gleich kalt", coldest, -15.0, -15.0, false},
+		{"weniger kalt", coldest, -14.0, -15.0, false},
+	}
+	for _, tt := range tests {
+		if got := tt.r.beats(tt.v, tt.prev); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAllTimeRecordPrevious(t *testing.T) {
+	loc := time.UTC
+	day := func(d int) time.Time { return time.Date(2024, 7, d, 0, 0, 0, 0, loc) }
+	// Tiefstwert, Höchstwert und Regenmenge (cm) je Tag, als weewx-Tageszusammenfassung und in archive
+	days := map[int][3]float64{1: {12, 28, 0.5}, 2: {9, 33, 0}, 3: {14, 31, 2.1}, 4: {8, 25, 0.2}}
+	samples := make(map[time.Time]map[string]float64)
+	for d, v := range days {
+		samples[day(d).Add(5*time.Hour)] = map[string]float64{"outTemp": v[0]}
+		samples[day(d).Add(12*time.Hour)] = map[string]float64{"rain": v[2]}
+		samples[day(d).Add(15*time.Hour)] = map[string]float64{"outTemp": v[1]}
+	}
+	db := testArchive(t, samples)
+	if _, err := db.Exec(`CREATE TABLE archive_day_outTemp (dateTime INTEGER PRIMARY KEY, min REAL, max REAL);
+		CREATE TABLE archive_day_rain (dateTime INTEGER PRIMARY KEY, sum REAL);`); err != nil {
+		t.Fatal(err)
+	}
+	for d, v := range days {
+		if _, err := db.Exec(`INSERT INTO archive_day_outTemp VALUES (?, ?, ?); INSERT INTO archive_day_rain VALUES (?, ?);`,
+			day(d).Unix(), v[0], v[1], day(d).Unix(), v[2]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name    string
+		r       allTimeRecord
+		before  time.Time
+		wantDay time.Time
+		want    float64
+	}{
+		{"heißester Tag", allTimeRecords[0], day(5), day(2), 33},
+		{"heißester Tag vor dem 2.", allTimeRecords[0], day(2), day(1), 28},
+		{"kältester Tag", allTimeRecords[1], day(5), day(4), 8},
+		// Regenmengen in der DB in cm
+		{"nassester Tag", allTimeRecords[2], day(5), day(3), 21},
+	}
+	for _, source := range []string{dailySourceWeewx, dailySourceArchive} {
+		config := DefaultConfig()
+		config.DailySource = source
+		config.StorePath = filepath.Join(t.TempDir(), "store.sdb")
+		for _, tt := range tests {
+			gotDay, got, ok, err := tt.r.previous(db, nil, config, tt.before)
+			if err != nil || !ok || !gotDay.Equal(tt.wantDay) || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("%s (%s): got %v, %v (ok %v, err %v), want %v, %v", tt.name, source, gotDay, got, ok, err, tt.wantDay, tt.want)
+			}
+		}
+		if _, _, ok, err := allTimeRecords[0].previous(db, nil, config, day(1)); ok || err != nil {
+			t.Errorf("ohne Werte (%s): got ok %v, err %v", source, ok, err)
+		}
+	}
+	if _, _, ok, err := allTimeRecords[3].previous(db, nil, DefaultConfig(), day(5)); ok || err != nil {
+		t.Errorf("Sonnenschein ohne Zustandsspeicher: got ok %v, err %v", ok, err)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// altTextTemperature beschreibt einen Temperaturverlauf aus Stundenmitteln (NaN für fehlende Stunden),
+// z.B. "Temperaturverlauf von 8 bis 23 °C, Maximum um 16 Uhr, Minimum um 5 Uhr". Leer ohne Messwerte.
+func altTextTemperature(config Config, hourly [24]float64) string {
+	lo, hi := math.NaN(), math.NaN()
+	loHour, hiHour := -1, -1
+	for h, v := range hourly {
+		if math.IsNaN(v) {
+			continue
+		}
+		if loHour < 0 || v < lo {
+			lo, loHour = v, h
+		}
+		if hiHour < 0 || v > hi {
+			hi, hiHour = v, h
+		}
+	}
+	if loHour < 0 {
+		return ""
+	}
+	unit := unitOf(config, quantityTemperature)
+	return tr(config, "Temperaturverlauf von %s bis %s, Maximum um %d Uhr, Minimum um %d Uhr",
+		unit.number(lo, 0), unit.format(hi, 0), hiHour, loHour)
+}
+
+// altTextRain beschreibt die stündlichen Niederschlagsmengen, z.B. "Niederschlag je Stunde, insgesamt 12.4 mm
+// in 5 Stunden, am meisten zwischen 14 und 15 Uhr mit 4.2 mm" bzw. "Niederschlag je Stunde: kein Niederschlag"
+func altTextRain(config Config, hourly [24]float64) string {
+	unit := unitOf(config, quantityRain)
+	var total float64
+	hours, heaviest := 0, -1
+	for h, v := range hourly {
+		if v <= 0 {
+			continue
+		}
+		total += v
+		hours++
+		if heaviest < 0 || v > hourly[heaviest] {
+			heaviest = h
+		}
+	}
+	if heaviest < 0 {
+		return tr(config, "Niederschlag je Stunde: kein Niederschlag")
+	}
+	if hours == 1 {
+		return tr(config, "Niederschlag je Stunde, %s zwischen %d und %d Uhr", unit.format(total, 1), heaviest, (heaviest+1)%24)
+	}
+	return tr(config, "Niederschlag je Stunde, insgesamt %s in %d Stunden, am meisten zwischen %d und %d Uhr mit %s",
+		unit.format(total, 1), hours, heaviest, (heaviest+1)%24, unit.format(hourly[heaviest], 1))
+}
+
+// altTextDailyRain beschreibt die täglichen Niederschlagsmengen ab from (NaN für Tage ohne Daten), z.B.
+// "Niederschlag je Tag vom 01.10.2024 bis 31.10.2024, insgesamt 42.1 mm an 12 Tagen, am meisten am 14.10. mit 12.3 mm"
+func altTextDailyRain(config Config, from time.Time, rain []float64) string {
+	unit := unitOf(config, quantityRain)
+	period := tr(config, "Niederschlag je Tag vom %s bis %s", formatDate(config, from), formatDate(config, from.AddDate(0, 0, len(rain)-1)))
+	var total float64
+	days, wettest := 0, -1
+	for i, r := range rain {
+		if math.IsNaN(r) || r <= 0 {
+			continue
+		}
+		total += r
+		days++
+		if wettest < 0 || r > rain[wettest] {
+			wettest = i
+		}
+	}
+	if wettest < 0 {
+		return period + tr(config, ": kein Niederschlag")
+	}
+	if days == 1 {
+		return period + tr(config, ", %s am %s", unit.format(total, 1), formatDayMonth(config, from.AddDate(0, 0, wettest)))
+	}
+	return period + tr(config, ", insgesamt %s an %d Tagen, am meisten am %s mit %s",
+		unit.format(total, 1), days, formatDayMonth(config, from.AddDate(0, 0, wettest)), unit.format(rain[wettest], 1))
+}
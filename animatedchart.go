@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"math"
+	"strings"
+	"time"
+)
+
+// validateAnimatedChart prüft animated_chart_publishers, animated_chart_frames und animated_chart_delay
+func validateAnimatedChart(config Config) error {
+	for _, p := range config.AnimatedChartPublishers {
+		if p != platformLemmy && p != platformMastodon {
+			return fmt.Errorf("animated_chart_publishers: unbekannte Plattform %q (lemmy, mastodon)", p)
+		}
+	}
+	if len(config.AnimatedChartPublishers) > 0 && len(config.AnimatedChartFrames) == 0 {
+		return fmt.Errorf("animated_chart_frames: keine Bilder angegeben")
+	}
+	for _, name := range config.AnimatedChartFrames {
+		known := false
+		for _, panel := range dailyPanelNames {
+			known = known || name == panel
+		}
+		if !known {
+			return fmt.Errorf("animated_chart_frames: unbekanntes Teildiagramm %q (%s)", name, strings.Join(dailyPanelNames, ", "))
+		}
+	}
+	if config.AnimatedChartDelay < 0.1 || config.AnimatedChartDelay > 60 {
+		return fmt.Errorf("ungültige Anzeigedauer der Bilder im animierten Diagramm (animated_chart_delay 0.1–60 Sekunden)")
+	}
+	return nil
+}
+
+// animatedChartEnabled prüft, ob die Posts für eine Plattform das animierte Tagesdiagramm erhalten
+func animatedChartEnabled(config Config, platform string) bool {
+	for _, p := range config.AnimatedChartPublishers {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// renderAnimatedChart zeichnet die Bilder des animierten Tagesdiagramms: je Eintrag in animated_chart_frames ein
+// Teildiagramm in voller Bildgröße statt der gestapelten, auf dem Handy kleinen Teildiagramme des kombinierten
+// Tagesdiagramms. Teildiagramme ohne Messwerte entfallen; geliefert werden auch die Namen der gezeichneten.
+func renderAnimatedChart(config Config, theme chartTheme, day time.Time, s dailySeries, annotations []chartAnnotation) ([]*chartDrawing, []string, error) {
+	var panels []func(c *chartCanvas) error
+	var names []string
+	for _, name := range config.AnimatedChartFrames {
+		if render := dailyPanel(config, day, s, annotations, name); render != nil {
+			panels = append(panels, render)
+			names = append(names, name)
+		}
+	}
+	if len(panels) == 0 {
+		return nil, nil, fmt.Errorf("keine Messwerte")
+	}
+
+	// über dem Teildiagramm eine Zeile mit Titel und Bildnummer, damit der Wechsel erkennbar ist
+	scale := theme.fontScale
+	header := (glyphHeight + 6) * scale
+	frames := make([]*chartDrawing, len(panels))
+	for i, render := range panels {
+		d := newChartDrawing(theme, theme.height)
+		d.text(2*scale, 3*scale, tr(config, "Wetter am %s", formatDate(config, day)), theme.text, scale)
+		counter := fmt.Sprintf("%d/%d", i+1, len(panels))
+		d.text(theme.width-textWidth(counter, scale)-2*scale, 3*scale, counter, theme.axis, scale)
+		if err := render(newChartPanel(d, theme, image.Rect(0, header, theme.width, theme.height))); err != nil {
+			return nil, nil, err
+		}
+		frames[i] = d
+	}
+	return frames, names, nil
+}
+
+// encodeAnimatedGIF gibt die Bilder als endlos wiederholtes GIF aus, jedes delay Sekunden lang. Die Farben
+// stammen aus den Diagrammen selbst; nur bei mehr als 256 Farben wird auf eine feste Palette gerundet.
+func encodeAnimatedGIF(frames []*chartDrawing, delay float64) ([]byte, error) {
+	images := make([]*image.RGBA, len(frames))
+	for i, d := range frames {
+		images[i] = d.raster()
+	}
+	p := framePalette(images)
+	g := &gif.GIF{}
+	for _, img := range images {
+		frame := image.NewPaletted(img.Bounds(), p)
+		draw.Draw(frame, frame.Bounds(), img, img.Bounds().Min, draw.Src)
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, int(math.Round(delay*100)))
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, g); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// framePalette sammelt die Farben aller Bilder als gemeinsame Palette; bei mehr als 256 Farben palette.Plan9
+func framePalette(images []*image.RGBA) color.Palette {
+	seen := make(map[color.RGBA]bool)
+	var p color.Palette
+	for _, img := range images {
+		for i := 0; i+3 < len(img.Pix); i += 4 {
+			col := color.RGBA{img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3]}
+			if seen[col] {
+				continue
+			}
+			if len(p) == 256 {
+				return palette.Plan9
+			}
+			seen[col] = true
+			p = append(p, col)
+		}
+	}
+	return p
+}
+
+// altTextAnimatedChart beschreibt das animierte Tagesdiagramm aus den Beschreibungen der gezeigten Teildiagramme,
+// z.B. "Wetter am 08.10.2024 (3 Bilder im Wechsel): Temperaturverlauf …; Niederschlag …; Böen bis 45 km/h um 14 Uhr"
+func altTextAnimatedChart(config Config, loc *time.Location, day time.Time, s dailySeries, hourlyTemperature [24]float64, annotations []chartAnnotation, names []string) string {
+	var parts []string
+	described := make(map[string]bool)
+	for _, name := range names {
+		if described[name] {
+			continue
+		}
+		described[name] = true
+		if text := altTextDailyPanel(config, loc, s, hourlyTemperature, annotations, name); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return tr(config, "Wetter am %s (%d Bilder im Wechsel)", formatDate(config, day), len(names)) + ": " + strings.Join(parts, "; ")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// getTempAnomaly liefert das Tagesmittel von day und das Mittel der anomaly_days Tage davor (NaN ohne Daten).
+// Beide kommen mit je einer Abfrage über die Tageszusammenfassungen aus, siehe meanTemperature.
+func getTempAnomaly(db *sql.DB, config Config, day time.Time) (float64, float64, error) {
+	end := day.AddDate(0, 0, 1)
+	mean, err := meanTemperature(db, config, day.Unix(), end.Unix())
+	if err != nil {
+		return math.NaN(), math.NaN(), err
+	}
+	trailing, err := meanTemperature(db, config, day.AddDate(0, 0, -config.AnomalyDays).Unix(), day.Unix())
+	return mean, trailing, err
+}
+
+// formatTempAnomaly erzeugt die Zeile zum Vergleich mit den Vortagen,
+// z.B. "Tagesmittel 14.2 °C: 2.3 °C wärmer als der Schnitt der letzten 30 Tage (11.9 °C)"
+func formatTempAnomaly(db *sql.DB, config Config, day time.Time) string {
+	if config.AnomalyDays <= 0 {
+		return ""
+	}
+	mean, trailing, err := getTempAnomaly(db, config, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Vergleich mit den letzten %d Tagen nicht verfügbar: %v\n", config.AnomalyDays, err)
+		return ""
+	}
+	if math.IsNaN(mean) || math.IsNaN(trailing) {
+		return ""
+	}
+	diff := roundTo(mean-trailing, 0.1)
+	unit := unitOf(config, quantityTemperature)
+	line := tr(config, "Tagesmittel %s: ", unit.format(mean, 1))
+	switch {
+	case diff > 0:
+		line += tr(config, "%s wärmer als der", unit.delta(diff, 1, false))
+	case diff < 0:
+		line += tr(config, "%s kälter als der", unit.delta(-diff, 1, false))
+	default:
+		line += tr(config, "genau im")
+	}
+	return line + tr(config, " Schnitt der letzten %d Tage (%s)", config.AnomalyDays, unit.format(trailing, 1))
+}
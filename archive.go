@@ -0,0 +1,53 @@
+package main
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// archiveColumns liefert die Spaltennamen einer weewx-Tabelle (in Kleinschreibung).
+// Je nach Schema und Erweiterungen fehlen optionale Sensorspalten, die Abfragen müssen das berücksichtigen.
+func archiveColumns(db *sql.DB, table string) (map[string]bool, error) {
+	rows, err := db.Query(`PRAGMA table_info(` + table + `);`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, typ string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			return nil, err
+		}
+		columns[strings.ToLower(name)] = true
+	}
+	return columns, rows.Err()
+}
+
+// archiveHasColumn prüft, ob die archive-Tabelle die angegebene Spalte enthält
+func archiveHasColumn(db *sql.DB, column string) bool {
+	columns, err := archiveColumns(db, "archive")
+	if err != nil {
+		return false
+	}
+	return columns[strings.ToLower(column)]
+}
+
+// optionalColumn prüft einen konfigurierten Spaltennamen und liefert ihn zurück, wenn er ein gültiger
+// Bezeichner ist und in der archive-Tabelle existiert, sonst "". So gelangen nur bekannte Spalten in SQL.
+func optionalColumn(db *sql.DB, name string) string {
+	if name == "" {
+		return ""
+	}
+	for _, r := range name {
+		if !(r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return ""
+		}
+	}
+	if !archiveHasColumn(db, name) {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// testArchive legt eine weewx-ähnliche archive-Tabelle im Speicher an; samples sind Zeitpunkt → Spaltenwerte
+func testArchive(t *testing.T, samples map[time.Time]map[string]float64) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// jede Verbindung hätte sonst ihre eigene leere Datenbank
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE archive (dateTime INTEGER PRIMARY KEY, outTemp REAL, rain REAL);`); err != nil {
+		t.Fatal(err)
+	}
+	for ts, values := range samples {
+		var outTemp, rain sql.NullFloat64
+		if v, ok := values["outTemp"]; ok {
+			outTemp = sql.NullFloat64{Float64: v, Valid: true}
+		}
+		if v, ok := values["rain"]; ok {
+			rain = sql.NullFloat64{Float64: v, Valid: true}
+		}
+		if _, err := db.Exec(`INSERT INTO archive (dateTime, outTemp, rain) VALUES (?, ?, ?);`, ts.Unix(), outTemp, rain); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
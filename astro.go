@@ -0,0 +1,220 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Tagundnachtgleichen und Sonnenwenden in der Reihenfolge von seasonNames (Beginn von Frühling, Sommer, Herbst, Winter)
+var cardinalPointNames = []string{"Frühlings-Tagundnachtgleiche", "Sommersonnenwende", "Herbst-Tagundnachtgleiche", "Wintersonnenwende"}
+
+// cardinalPointTerms sind die periodischen Terme (A, B, C) nach Meeus, Astronomical Algorithms, Tabelle 27.C
+var cardinalPointTerms = [][3]float64{
+	{485, 324.96, 1934.136}, {203, 337.23, 32964.467}, {199, 342.08, 20.186}, {182, 27.85, 445267.112},
+	{156, 73.14, 45036.886}, {136, 171.52, 22518.443}, {77, 222.54, 65928.934}, {74, 296.72, 3034.906},
+	{70, 243.58, 9037.513}, {58, 119.81, 33718.147}, {52, 297.17, 150.678}, {50, 21.02, 2281.226},
+	{45, 247.54, 29929.562}, {44, 325.15, 31555.956}, {29, 60.93, 4443.417}, {18, 155.12, 67555.328},
+	{17, 288.79, 4562.452}, {16, 198.04, 62894.029}, {14, 199.76, 31436.921}, {12, 95.39, 14577.848},
+	{12, 287.11, 31931.756}, {12, 320.81, 34777.259}, {9, 227.73, 1222.114}, {8, 15.45, 16859.074},
+}
+
+// deltaT ist die Differenz zwischen Terrestrischer Zeit und UTC (derzeit etwa 69 s), für Minutengenauigkeit ausreichend
+const deltaT = 69 * time.Second
+
+// cardinalPoint berechnet den Zeitpunkt der Tagundnachtgleiche bzw. Sonnenwende k (0 = März, 1 = Juni,
+// 2 = September, 3 = Dezember) eines Jahres nach Meeus, Kapitel 27 (gültig 1000–3000, Genauigkeit etwa eine Minute)
+func cardinalPoint(year, k int) time.Time {
+	y := float64(year-2000) / 1000
+	var jde0 float64
+	switch k {
+	case 0:
+		jde0 = 2451623.80984 + 365242.37404*y + 0.05169*y*y - 0.00411*y*y*y - 0.00057*y*y*y*y
+	case 1:
+		jde0 = 2451716.56767 + 365241.62603*y + 0.00325*y*y + 0.00888*y*y*y - 0.00030*y*y*y*y
+	case 2:
+		jde0 = 2451810.21715 + 365242.01767*y - 0.11575*y*y + 0.00337*y*y*y + 0.00078*y*y*y*y
+	default:
+		jde0 = 2451900.05952 + 365242.74049*y - 0.06223*y*y - 0.00823*y*y*y + 0.00032*y*y*y*y
+	}
+	rad := math.Pi / 180
+	t := (jde0 - 2451545.0) / 36525
+	w := (35999.373*t - 2.47) * rad
+	dl := 1 + 0.0334*math.Cos(w) + 0.0007*math.Cos(2*w)
+	s := 0.0
+	for _, term := range cardinalPointTerms {
+		s += term[0] * math.Cos((term[1]+term[2]*t)*rad)
+	}
+	jde := jde0 + 0.00001*s/dl
+	unix := (jde - 2440587.5) * 86400
+	return time.Unix(int64(math.Round(unix)), 0).Add(-deltaT)
+}
+
+// astronomicalSeasonStarts liefert die Anfänge (MM-DD) der astronomischen Jahreszeiten eines Jahres in loc
+func astronomicalSeasonStarts(year int, loc *time.Location) []string {
+	starts := make([]string, len(cardinalPointNames))
+	for k := range starts {
+		starts[k] = cardinalPoint(year, k).In(loc).Format("01-02")
+	}
+	return starts
+}
+
+// solarPosition berechnet Deklination (rad) und Zeitgleichung (Minuten) der Sonne am Mittag des Kalendertags day
+// (vereinfachte Sonnenkoordinaten des Astronomical Almanac, für Minutengenauigkeit ausreichend)
+func solarPosition(day time.Time) (float64, float64) {
+	rad := math.Pi / 180
+	noon := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, day.Location())
+	n := float64(noon.Unix())/86400 + 2440587.5 - 2451545.0
+	l := math.Mod(280.460+0.9856474*n, 360)
+	g := (357.528 + 0.9856003*n) * rad
+	lambda := (l + 1.915*math.Sin(g) + 0.020*math.Sin(2*g)) * rad
+	epsilon := (23.439 - 0.0000004*n) * rad
+	decl := math.Asin(math.Sin(epsilon) * math.Sin(lambda))
+	alpha := math.Atan2(math.Cos(epsilon)*math.Sin(lambda), math.Cos(lambda)) / rad
+	e := math.Remainder(l-alpha, 360) // Grad, ±180
+	return decl, 4 * e
+}
+
+// sunriseHourAngle liefert den Stundenwinkel (Grad) von Sonnenauf- und -untergang (Sonnenmittelpunkt 0.833° unter
+// dem Horizont) an der Breite latitude; 180 am Polartag, 0 in der Polarnacht
+func sunriseHourAngle(latitude, decl float64) float64 {
+	rad := math.Pi / 180
+	phi := latitude * rad
+	cosH := (math.Sin(-0.833*rad) - math.Sin(phi)*math.Sin(decl)) / (math.Cos(phi) * math.Cos(decl))
+	switch {
+	case cosH <= -1:
+		return 180 // Polartag
+	case cosH >= 1:
+		return 0 // Polarnacht
+	}
+	return math.Acos(cosH) / rad
+}
+
+// dayLength berechnet die Tageslänge (Sonnenaufgang bis -untergang) für den Kalendertag day an der geografischen
+// Breite latitude
+func dayLength(latitude float64, day time.Time) time.Duration {
+	decl, _ := solarPosition(day)
+	hours := 2 * sunriseHourAngle(latitude, decl) / 15
+	return time.Duration(hours * float64(time.Hour)).Round(time.Minute)
+}
+
+// sunTimes berechnet Sonnenaufgang und -untergang des Kalendertags day an der Position latitude/longitude
+// (Grad, Osten positiv) in der Zeitzone von day. ok ist false am Polartag und in der Polarnacht.
+func sunTimes(latitude, longitude float64, day time.Time) (time.Time, time.Time, bool) {
+	decl, eqTime := solarPosition(day)
+	h := sunriseHourAngle(latitude, decl)
+	if h == 0 || h == 180 {
+		return time.Time{}, time.Time{}, false
+	}
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	transit := 720 - 4*longitude - eqTime // Minuten nach Mitternacht UTC
+	at := func(minutes float64) time.Time {
+		return midnight.Add(time.Duration(minutes * float64(time.Minute))).In(day.Location()).Round(time.Minute)
+	}
+	return at(transit - 4*h), at(transit + 4*h), true
+}
+
+// formatSunTimes erzeugt die Zeile mit Sonnenaufgang, -untergang und Tageslänge des Tages im Vergleich zur Vorwoche,
+// z.B. "Sonnenaufgang 07:48, Sonnenuntergang 18:38, Tageslänge 10 h 50 min (23 Minuten kürzer als vor einer Woche)"
+func formatSunTimes(config Config, day time.Time) string {
+	if !config.SunTimesEnabled {
+		return ""
+	}
+	rise, set, ok := sunTimes(config.Latitude, config.Longitude, day)
+	if !ok {
+		return ""
+	}
+	length := dayLength(config.Latitude, day)
+	diff := int(math.Round((length - dayLength(config.Latitude, day.AddDate(0, 0, -7))).Minutes()))
+	var comparison string
+	switch {
+	case diff == 0:
+		comparison = tr(config, "so lang wie vor einer Woche")
+	case diff == 1:
+		comparison = tr(config, "1 Minute länger als vor einer Woche")
+	case diff == -1:
+		comparison = tr(config, "1 Minute kürzer als vor einer Woche")
+	case diff > 0:
+		comparison = tr(config, "%d Minuten länger als vor einer Woche", diff)
+	default:
+		comparison = tr(config, "%d Minuten kürzer als vor einer Woche", -diff)
+	}
+	return tr(config, "Sonnenaufgang %s, Sonnenuntergang %s, Tageslänge %s (%s)",
+		rise.Format("15:04"), set.Format("15:04"), formatDuration(length.Minutes()), comparison)
+}
+
+// formatSignedDuration formatiert eine Differenz als "+3 h 59 min" bzw. "−3 h 59 min"
+func formatSignedDuration(d time.Duration) string {
+	if d < 0 {
+		return "−" + formatDuration((-d).Minutes())
+	}
+	return "+" + formatDuration(d.Minutes())
+}
+
+// formatCardinalPointPost erzeugt den Sonderpost zur Tagundnachtgleiche bzw. Sonnenwende k (Zeitpunkt event) mit dem
+// Rückblick auf das Vierteljahr seit der vorigen (previous): Tageslänge, Sonnenscheindauer und Temperaturen, jeweils mit Vorjahr
+func formatCardinalPointPost(db *sql.DB, loc *time.Location, config Config, k int, event, previous time.Time) (string, string, error) {
+	today := time.Date(event.Year(), event.Month(), event.Day(), 0, 0, 0, 0, loc)
+	from := time.Date(previous.Year(), previous.Month(), previous.Day(), 0, 0, 0, 0, loc)
+	to := today.AddDate(0, 0, -1)
+	q, err := getPeriodSummary(db, loc, config, from, to)
+	if err != nil {
+		return "", "", err
+	}
+	last, lastErr := getPeriodSummary(db, loc, config, from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0))
+	compare := func(format func(float64) string, v, prev float64) string {
+		line := format(v)
+		if lastErr == nil && !math.IsNaN(prev) {
+			line += tr(config, " (Vorjahr: %s)", format(prev))
+		}
+		return line
+	}
+	temp, rain := unitOf(config, quantityTemperature), unitOf(config, quantityRain)
+
+	name := tr(config, cardinalPointNames[k])
+	title := seasonNames[k].emoji + " " + tr(config, "%s %d in %s", name, event.Year(), config.StationName)
+	lines := []string{
+		tr(config, "%s am %s um %s Uhr – Rückblick auf das Vierteljahr seit dem %s",
+			name, formatDayMonth(config, event), event.Format("15:04"), formatDayMonth(config, from)),
+	}
+	lengthFrom, lengthToday := dayLength(config.Latitude, from), dayLength(config.Latitude, today)
+	lines = append(lines, tr(config, "Tageslänge: %s am %s → %s heute (%s)",
+		formatDuration(lengthFrom.Minutes()), formatDayMonth(config, from), formatDuration(lengthToday.Minutes()), formatSignedDuration(lengthToday-lengthFrom)))
+	if !math.IsNaN(q.sunMinutes) {
+		lines = append(lines, tr(config, "Sonnenschein: ")+compare(func(v float64) string { return fmt.Sprintf("%.0f h", v) }, q.sunMinutes/60, last.sunMinutes/60))
+	}
+	lines = append(lines, tr(config, "Mitteltemperatur: ")+compare(func(v float64) string { return temp.format(v, 1) }, q.tMean, last.tMean))
+	lines = append(lines, tr(config, "Höchstwert: %s am %s, Tiefstwert: %s am %s",
+		temp.format(q.tMax, 1), formatDayMonth(config, q.tMaxDay), temp.format(q.tMin, 1), formatDayMonth(config, q.tMinDay)))
+	lines = append(lines, tr(config, "Niederschlag: ")+compare(func(v float64) string { return rain.format(v, 1) }, q.rainSum, last.rainSum))
+	if q.days < int(today.Sub(from).Hours()/24+0.5) {
+		lines = append(lines, tr(config, "(Daten für %d Tage)", q.days))
+	}
+	return title, strings.Join(lines, "\n"), nil
+}
+
+// publishCardinalPointPost veröffentlicht am Tag einer Tagundnachtgleiche oder Sonnenwende den Sonderpost
+func publishCardinalPointPost(db, store *sql.DB, loc *time.Location, config Config, now time.Time, testMode, loopMode bool) {
+	if !config.CardinalPointPostsEnabled {
+		return
+	}
+	for k := range cardinalPointNames {
+		event := cardinalPoint(now.Year(), k).In(loc)
+		if event.Format("2006-01-02") != now.Format("2006-01-02") {
+			continue
+		}
+		previous := cardinalPoint(now.Year(), (k+3)%4).In(loc)
+		if k == 0 {
+			previous = cardinalPoint(now.Year()-1, 3).In(loc)
+		}
+		title, text, err := formatCardinalPointPost(db, loc, config, k, event, previous)
+		if err != nil {
+			log.Printf("%s: Rückblick nicht möglich: %v", cardinalPointNames[k], err)
+			return
+		}
+		publishSummary(store, config, "astro-"+event.Format("2006-01-02"), postTypeCardinalPoint, cardinalPointNames[k], title, text, "", nil, testMode, loopMode)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// pendingPlatforms liefert die konfigurierten Plattformen, auf denen der Post für day noch nicht veröffentlicht wurde
+func pendingPlatforms(store *sql.DB, config Config, day string) []string {
+	var platforms []string
+	if config.LemmyPassword != "CHANGEME" {
+		platforms = append(platforms, platformLemmy)
+	}
+	if config.MastodonServer != "" && config.MastodonToken != "" {
+		platforms = append(platforms, platformMastodon)
+	}
+	if config.WebhookURL != "" {
+		platforms = append(platforms, platformWebhook)
+	}
+	var pending []string
+	for _, p := range platforms {
+		if !isPublished(store, day, p) {
+			pending = append(pending, p)
+		}
+	}
+	return pending
+}
+
+// runBackfill holt die Posts der letzten days Tage nach, die laut Zustandsspeicher nie (vollständig) veröffentlicht
+// wurden, z.B. weil der Rechner ausgefallen war. Die Tage werden in zeitlicher Reihenfolge so gepostet, als liefe der
+// tägliche Lauf um 4:00 Uhr am Folgetag; zwischen Posts an denselben Server liegt backfill_interval_seconds.
+// Nachgeholt wird nur der Tagespost; scheitert er auf einer Plattform auch nach kurzer Wiederholung, landet er in der
+// Dead-Letter-Datei und der nächste Tag folgt.
+func runBackfill(dbPath string, config Config, days int, testMode bool, noaaFile string) error {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("timezone: %v", err)
+	}
+	store, err := openStore(config.StorePath)
+	if err != nil {
+		return fmt.Errorf("ohne Zustandsspeicher lässt sich nicht feststellen, welche Tage fehlen: %v", err)
+	}
+	defer store.Close()
+
+	if throttle == nil && !testMode {
+		throttle = newPostThrottle(time.Duration(config.BackfillIntervalSeconds) * time.Second)
+	}
+
+	today := time.Now().In(loc)
+	missing, posted := 0, 0
+	for i := days; i >= 1; i-- {
+		// Lauf zur üblichen Uhrzeit am Folgetag des nachzuholenden Tages; steht er heute noch aus, gibt es nichts nachzuholen
+		now := time.Date(today.Year(), today.Month(), today.Day()-i+1, 0, dailyRunMinute(config), 0, 0, loc)
+		if now.After(today) {
+			continue
+		}
+		day := now.AddDate(0, 0, -1).Format("2006-01-02")
+		pending := pendingPlatforms(store, config, day)
+		if len(pending) == 0 {
+			continue
+		}
+		missing++
+		log.Printf("Hole Post für %s nach (%s)", day, strings.Join(pending, ", "))
+		if err := runWeatherPostingAt(dbPath, config, testMode, false, true, noaaFile, now); err != nil {
+			log.Printf("Fehler beim Nachholen von %s: %v", day, err)
+			continue
+		}
+		posted++
+	}
+	log.Printf("%d von %d fehlenden Tagen nachgeholt (letzte %d Tage geprüft)", posted, missing, days)
+	return nil
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// benchArchiveDays ist die Länge des synthetischen Archivs für die Benchmarks
+const benchArchiveDays = 365
+
+var (
+	benchOnce sync.Once
+	benchDir  string
+	benchPath string
+	benchErr  error
+	benchEnd  time.Time
+)
+
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if benchDir != "" {
+		os.RemoveAll(benchDir)
+	}
+	os.Exit(code)
+}
+
+// benchArchive erzeugt einmalig eine weewx-ähnliche Datenbank mit 5-Minuten-Werten
+func benchArchive(b *testing.B) (*sql.DB, *time.Location) {
+	b.Helper()
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		b.Fatal(err)
+	}
+	benchOnce.Do(func() {
+		benchDir, benchErr = os.MkdirTemp("", "weewxbench")
+		if benchErr != nil {
+			return
+		}
+		benchPath = filepath.Join(benchDir, "weewx.sdb")
+		benchEnd = time.Date(2024, 12, 31, 0, 0, 0, 0, loc)
+		benchErr = fillBenchArchive(benchPath, loc, benchEnd.AddDate(0, 0, -benchArchiveDays), benchEnd)
+	})
+	if benchErr != nil {
+		b.Fatal(benchErr)
+	}
+	db, err := sql.Open("sqlite3", benchPath)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { db.Close() })
+	return db, loc
+}
+
+func fillBenchArchive(path string, loc *time.Location, start, end time.Time) error {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	stmts := []string{
+		`CREATE TABLE archive (dateTime INTEGER PRIMARY KEY, usUnits INTEGER, interval INTEGER,
+			barometer REAL, outTemp REAL, outHumidity REAL, dewpoint REAL, windSpeed REAL, windGust REAL,
+			windDir REAL, rain REAL, rainRate REAL, radiation REAL, maxSolarRad REAL, UV REAL);`,
+		`CREATE TABLE archive_day_rain (dateTime INTEGER PRIMARY KEY, min REAL, mintime INTEGER,
+			max REAL, maxtime INTEGER, sum REAL, count INTEGER, wsum REAL, sumtime INTEGER);`,
+		`CREATE TABLE archive_day_outTemp (dateTime INTEGER PRIMARY KEY, min REAL, mintime INTEGER,
+			max REAL, maxtime INTEGER, sum REAL, count INTEGER, wsum REAL, sumtime INTEGER);`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	ins, err := tx.Prepare(`INSERT INTO archive VALUES (?, 16, 5, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`)
+	if err != nil {
+		return err
+	}
+	insDay, err := tx.Prepare(`INSERT INTO archive_day_rain (dateTime, sum, count) VALUES (?, ?, ?);`)
+	if err != nil {
+		return err
+	}
+	insTemp, err := tx.Prepare(`INSERT INTO archive_day_outTemp (dateTime, min, mintime, max, maxtime, count) VALUES (?, ?, ?, ?, ?, ?);`)
+	if err != nil {
+		return err
+	}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		var daySum float64
+		count := 0
+		tMin, tMax := math.Inf(1), math.Inf(-1)
+		var minTime, maxTime int64
+		rainy := day.YearDay()%3 == 0
+		for t := day; t.Before(day.AddDate(0, 0, 1)); t = t.Add(5 * time.Minute) {
+			hour := float64(t.Hour()) + float64(t.Minute())/60
+			temp := 10 - 8*math.Cos(2*math.Pi*float64(day.YearDay())/365) + 5*math.Sin(2*math.Pi*(hour-9)/24)
+			sun := math.Max(0, 900*math.Sin(math.Pi*(hour-6)/12))
+			rain := 0.0
+			if rainy && t.Minute() == 0 {
+				rain = 0.02
+			}
+			daySum += rain
+			count++
+			if temp < tMin {
+				tMin, minTime = temp, t.Unix()
+			}
+			if temp > tMax {
+				tMax, maxTime = temp, t.Unix()
+			}
+			if _, err := ins.Exec(t.Unix(), 1013+3*math.Sin(hour), temp, 80, temp-4, 10, 18, 240, rain, rain*12, sun*0.7, sun, sun/100); err != nil {
+				return err
+			}
+		}
+		if _, err := insDay.Exec(day.Unix(), daySum, count); err != nil {
+			return err
+		}
+		if _, err := insTemp.Exec(day.Unix(), tMin, minTime, tMax, maxTime, count); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func BenchmarkGetStats(b *testing.B) {
+	db, loc := benchArchive(b)
+	config := DefaultConfig()
+	day := benchEnd.AddDate(0, 0, -1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := getStats(db, loc, config, day.Unix(), benchEnd.Unix()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCountDaysSinceRain(b *testing.B) {
+	db, loc := benchArchive(b)
+	config := DefaultConfig()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		countDaysSinceRain(db, loc, config, benchEnd)
+	}
+}
+
+func BenchmarkCountConsecutiveRainDays(b *testing.B) {
+	db, loc := benchArchive(b)
+	config := DefaultConfig()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		countConsecutiveRainDays(db, loc, config, benchEnd)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// defaultAPIBudget sind die voreingestellten Höchstzahlen an API-Aufrufen je Plattform und Kalendertag.
+// Ein Lemmy-Versuch braucht drei Aufrufe (Login, Community, Post), ein Mastodon-Post einen und je Bild einen
+// weiteren (bis zu fünf).
+func defaultAPIBudget() map[string]int {
+	return map[string]int{
+		platformLemmy:    30,
+		platformMastodon: 30,
+		platformWebhook:  10,
+	}
+}
+
+// spendAPICall verbucht einen API-Aufruf an eine Plattform für den heutigen Kalendertag. Ist das Tagesbudget
+// aufgebraucht, wird ein Fehler zurückgegeben und der Admin einmalig benachrichtigt, damit eine
+// Fehlkonfiguration eine Instanz nicht den ganzen Tag mit Wiederholungen belastet.
+// Plattformen ohne Budget (oder ohne Zustandsspeicher) sind unbegrenzt.
+func spendAPICall(store *sql.DB, config Config, platform string) error {
+	budget := config.APIBudget[platform]
+	if store == nil || budget <= 0 {
+		return nil
+	}
+	day := time.Now().Format("2006-01-02")
+
+	var calls int
+	err := store.QueryRow(`SELECT calls FROM api_budget WHERE day = ? AND platform = ?;`, day, platform).Scan(&calls)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Warnung: API-Budget für %s unbekannt: %v", platform, err)
+		return nil
+	}
+	if calls >= budget {
+		notifyBudgetExhausted(store, config, day, platform, budget)
+		return fmt.Errorf("Tagesbudget von %d API-Aufrufen für %s aufgebraucht", budget, platform)
+	}
+
+	const q = `INSERT INTO api_budget (day, platform, calls) VALUES (?, ?, 1)
+		ON CONFLICT(day, platform) DO UPDATE SET calls = calls + 1;`
+	if _, err := store.Exec(q, day, platform); err != nil {
+		log.Printf("Warnung: API-Aufruf für %s konnte nicht verbucht werden: %v", platform, err)
+	}
+	return nil
+}
+
+// notifyBudgetExhausted benachrichtigt den Admin einmal pro Tag und Plattform über ein aufgebrauchtes Budget
+func notifyBudgetExhausted(store *sql.DB, config Config, day, platform string, budget int) {
+	period := "budget-" + day + "-" + platform
+	var sent int64
+	if err := store.QueryRow(`SELECT sent FROM admin_reports WHERE period = ?;`, period).Scan(&sent); err == nil {
+		return // bereits benachrichtigt
+	}
+
+	text := fmt.Sprintf("⚠️ API-Budget für %s am %s aufgebraucht (%d Aufrufe). Weitere Versuche werden bis morgen ausgesetzt – bitte Konfiguration und Erreichbarkeit prüfen.",
+		platform, day, budget)
+	log.Print(text)
+	if !adminChannelConfigured(config) {
+		return
+	}
+	if err := sendAdminMessage(config, text); err != nil {
+		log.Printf("Budget-Warnung konnte nicht verschickt werden: %v", err)
+		return
+	}
+	if _, err := store.Exec(`INSERT INTO admin_reports (period, sent) VALUES (?, ?);`, period, time.Now().Unix()); err != nil {
+		log.Printf("Warnung: Versand der Budget-Warnung konnte nicht vermerkt werden: %v", err)
+	}
+}
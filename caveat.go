@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// DateRange beschreibt einen jährlich wiederkehrenden Zeitraum als MM-DD, auch über den Jahreswechsel
+type DateRange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// contains prüft, ob day im Zeitraum liegt
+func (r DateRange) contains(day time.Time) bool {
+	_, ok := seasonStart(day, r.From, r.To)
+	return ok
+}
+
+// inSeasons prüft, ob day in einem der Zeiträume liegt
+func inSeasons(seasons []DateRange, day time.Time) bool {
+	for _, season := range seasons {
+		if season.contains(day) {
+			return true
+		}
+	}
+	return false
+}
+
+// rainCaveat liefert den Hinweis zur Niederschlagsmessung, wenn der Tag in einem der konfigurierten
+// Zeiträume liegt oder der Tiefstwert unter der konfigurierten Temperatur lag, sonst "".
+// Unbeheizte Regenmesser erfassen Schnee erst beim Tauen, die Tagessumme ist dann irreführend.
+func rainCaveat(config Config, day time.Time, s dayStats) string {
+	if config.RainCaveatText == "" {
+		return ""
+	}
+	for _, r := range config.RainCaveatPeriods {
+		if r.contains(day) {
+			return config.RainCaveatText
+		}
+	}
+	if config.RainCaveatTempEnabled && !math.IsNaN(s.tMin) && s.tMin < config.RainCaveatBelowTemp {
+		return config.RainCaveatText
+	}
+	return ""
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+)
+
+// chartTheme legt Größe, Farben und Schriftgröße der Diagramme fest
+type chartTheme struct {
+	width, height int
+	background    color.RGBA
+	text          color.RGBA
+	grid          color.RGBA
+	axis          color.RGBA
+	line          color.RGBA   // Temperaturkurve
+	bar           color.RGBA   // Niederschlagsbalken
+	solar         color.RGBA   // Sonnenstrahlung
+	wind, gust    color.RGBA   // mittlere Windgeschwindigkeit und Böen
+	high, low     color.RGBA   // Markierung von Höchst- und Tiefstwert
+	scale         []color.RGBA // Farbstufen, z.B. Geschwindigkeitsklassen der Windrose
+	fontScale     int
+	fontFamily    string // Schriftart der SVG-Ausgabe; PNG nutzt immer die eingebaute Pixelschrift
+}
+
+// defaultChartTheme ist das helle Standardthema
+var defaultChartTheme = chartTheme{
+	width:      800,
+	height:     400,
+	background: color.RGBA{0xff, 0xff, 0xff, 0xff},
+	text:       color.RGBA{0x33, 0x33, 0x33, 0xff},
+	grid:       color.RGBA{0xe0, 0xe0, 0xe0, 0xff},
+	axis:       color.RGBA{0x99, 0x99, 0x99, 0xff},
+	line:       color.RGBA{0xd3, 0x54, 0x00, 0xff},
+	bar:        color.RGBA{0x29, 0x80, 0xb9, 0xff},
+	solar:      color.RGBA{0xf3, 0x9c, 0x12, 0xff},
+	wind:       color.RGBA{0x16, 0xa0, 0x85, 0xff},
+	gust:       color.RGBA{0xa3, 0xd9, 0xcf, 0xff},
+	high:       color.RGBA{0xc0, 0x39, 0x2b, 0xff},
+	low:        color.RGBA{0x29, 0x80, 0xb9, 0xff},
+	scale: []color.RGBA{
+		{0xa6, 0xd9, 0x6a, 0xff}, {0x1a, 0x98, 0x50, 0xff}, {0x29, 0x80, 0xb9, 0xff},
+		{0x8e, 0x44, 0xad, 0xff}, {0xc0, 0x39, 0x2b, 0xff},
+	},
+	fontScale:  2,
+	fontFamily: "monospace",
+}
+
+// darkChartTheme ist das dunkle Standardthema
+var darkChartTheme = chartTheme{
+	width:      800,
+	height:     400,
+	background: color.RGBA{0x1f, 0x23, 0x2a, 0xff},
+	text:       color.RGBA{0xdd, 0xdd, 0xdd, 0xff},
+	grid:       color.RGBA{0x3a, 0x40, 0x4a, 0xff},
+	axis:       color.RGBA{0x80, 0x86, 0x90, 0xff},
+	line:       color.RGBA{0xff, 0x8c, 0x42, 0xff},
+	bar:        color.RGBA{0x4f, 0xa3, 0xe0, 0xff},
+	solar:      color.RGBA{0xf7, 0xc5, 0x48, 0xff},
+	wind:       color.RGBA{0x3f, 0xd0, 0xb0, 0xff},
+	gust:       color.RGBA{0x2a, 0x6b, 0x60, 0xff},
+	high:       color.RGBA{0xff, 0x6b, 0x5b, 0xff},
+	low:        color.RGBA{0x4f, 0xa3, 0xe0, 0xff},
+	scale: []color.RGBA{
+		{0xb8, 0xe9, 0x86, 0xff}, {0x3c, 0xc0, 0x70, 0xff}, {0x4f, 0xa3, 0xe0, 0xff},
+		{0xb0, 0x6c, 0xd0, 0xff}, {0xff, 0x6b, 0x5b, 0xff},
+	},
+	fontScale:  2,
+	fontFamily: "monospace",
+}
+
+// chartPoint ist ein Punkt einer Datenreihe; NaN als y unterbricht die Linie
+type chartPoint struct {
+	x, y float64
+}
+
+// chartTick ist eine Markierung der x-Achse mit Beschriftung
+type chartTick struct {
+	x     float64
+	label string
+}
+
+// chartCanvas ist die Zeichenfläche eines Diagramms mit Plotbereich und Wertebereichen der Achsen.
+// Die einzelnen Diagramme (Temperaturkurve, Niederschlagsbalken) zeichnen darauf.
+type chartCanvas struct {
+	d          *chartDrawing
+	theme      chartTheme
+	bounds     image.Rectangle // Bereich des Diagramms in der Zeichnung
+	plot       image.Rectangle
+	xMin, xMax float64
+	yMin, yMax float64
+}
+
+// newChartCanvas legt eine Zeichenfläche in Größe und Farben des Themas an
+func newChartCanvas(theme chartTheme) *chartCanvas {
+	return newChartPanel(newChartDrawing(theme, theme.height), theme, image.Rect(0, 0, theme.width, theme.height))
+}
+
+// newChartPanel legt eine Zeichenfläche im Bereich bounds einer Zeichnung an, z.B. für übereinander gestapelte
+// Diagramme. Oben bleibt Platz für den Titel, links und unten für die Achsenbeschriftung.
+func newChartPanel(d *chartDrawing, theme chartTheme, bounds image.Rectangle) *chartCanvas {
+	line := (glyphHeight + 4) * theme.fontScale
+	plot := image.Rect(bounds.Min.X+textWidth("-00.0", theme.fontScale)+3*theme.fontScale, bounds.Min.Y+3*line,
+		bounds.Max.X-textWidth("00", theme.fontScale)/2-4*theme.fontScale, bounds.Max.Y-2*line)
+	return &chartCanvas{d: d, theme: theme, bounds: bounds, plot: plot}
+}
+
+// setRange legt die Wertebereiche der Achsen fest
+func (c *chartCanvas) setRange(xMin, xMax, yMin, yMax float64) {
+	c.xMin, c.xMax, c.yMin, c.yMax = xMin, xMax, yMin, yMax
+}
+
+// px rechnet einen x-Wert in die Pixelspalte um
+func (c *chartCanvas) px(x float64) int {
+	return c.plot.Min.X + int(math.Round((x-c.xMin)/(c.xMax-c.xMin)*float64(c.plot.Dx())))
+}
+
+// py rechnet einen y-Wert in die Pixelzeile um
+func (c *chartCanvas) py(y float64) int {
+	return c.plot.Max.Y - int(math.Round((y-c.yMin)/(c.yMax-c.yMin)*float64(c.plot.Dy())))
+}
+
+// title schreibt den Titel oben links und die Einheit der y-Achse darunter
+func (c *chartCanvas) title(title, unit string) {
+	scale := c.theme.fontScale
+	c.d.text(c.plot.Min.X, c.bounds.Min.Y+2*scale, title, c.theme.text, scale)
+	c.d.text(c.bounds.Min.X+2*scale, c.plot.Min.Y-(glyphHeight+4)*scale, unit, c.theme.text, scale)
+}
+
+// yAxis zeichnet waagerechte Gitterlinien im Abstand step mit Beschriftung am linken Rand
+func (c *chartCanvas) yAxis(step float64, label func(float64) string) {
+	scale := c.theme.fontScale
+	for v := math.Ceil(c.yMin/step) * step; v <= c.yMax+step/1000; v += step {
+		y := c.py(v)
+		c.d.rect(image.Rect(c.plot.Min.X, y, c.plot.Max.X, y+1), c.theme.grid)
+		text := label(v)
+		c.d.text(c.plot.Min.X-textWidth(text, scale)-2*scale, y-glyphHeight*scale/2, text, c.theme.text, scale)
+	}
+	c.d.rect(image.Rect(c.plot.Min.X, c.plot.Min.Y, c.plot.Min.X+1, c.plot.Max.Y+1), c.theme.axis)
+	c.d.rect(image.Rect(c.plot.Min.X, c.plot.Max.Y, c.plot.Max.X, c.plot.Max.Y+1), c.theme.axis)
+}
+
+// xAxis zeichnet senkrechte Gitterlinien an den Markierungen mit zentrierter Beschriftung unter dem Plotbereich
+func (c *chartCanvas) xAxis(ticks []chartTick) {
+	scale := c.theme.fontScale
+	for _, t := range ticks {
+		x := c.px(t.x)
+		c.d.rect(image.Rect(x, c.plot.Min.Y, x+1, c.plot.Max.Y), c.theme.grid)
+		c.d.text(x-textWidth(t.label, scale)/2, c.plot.Max.Y+3*scale, t.label, c.theme.text, scale)
+	}
+}
+
+// polyline verbindet die Punkte einer Datenreihe; NaN-Werte unterbrechen die Linie
+func (c *chartCanvas) polyline(points []chartPoint, col color.RGBA) {
+	width := c.theme.fontScale
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		if math.IsNaN(a.y) || math.IsNaN(b.y) {
+			continue
+		}
+		c.d.line(c.px(a.x), c.py(a.y), c.px(b.x), c.py(b.y), width, col)
+	}
+}
+
+// bar zeichnet einen Balken von der Nulllinie (bzw. dem unteren Rand) bis y zwischen x0 und x1
+func (c *chartCanvas) bar(x0, x1, y float64, col color.RGBA) {
+	base := c.py(math.Max(c.yMin, 0))
+	top := c.py(y)
+	if top > base {
+		top, base = base, top
+	}
+	c.d.rect(image.Rect(c.px(x0)+1, top, c.px(x1), base), col)
+}
+
+// band hinterlegt den Plotbereich zwischen x0 und x1 in voller Höhe, z.B. um einen Tag hervorzuheben
+func (c *chartCanvas) band(x0, x1 float64, col color.RGBA) {
+	c.d.rect(image.Rect(c.px(x0), c.plot.Min.Y, c.px(x1), c.plot.Max.Y).Intersect(c.plot), col)
+}
+
+// marker setzt einen Punkt bei (x, y) und schreibt label darüber (above) bzw. darunter, innerhalb des Plotbereichs
+func (c *chartCanvas) marker(x, y float64, label string, above bool, col color.RGBA) {
+	scale := c.theme.fontScale
+	px, py := c.px(x), c.py(y)
+	r := 3 * scale
+	c.d.rect(image.Rect(px-r, py-r, px+r+1, py+r+1), col)
+	w := textWidth(label, scale)
+	tx := px - w/2
+	if tx < c.plot.Min.X+scale {
+		tx = c.plot.Min.X + scale
+	}
+	if tx+w > c.plot.Max.X-scale {
+		tx = c.plot.Max.X - scale - w
+	}
+	ty := py - r - (glyphHeight+2)*scale
+	if !above || ty < c.plot.Min.Y-(glyphHeight+2)*scale {
+		ty = py + r + 2*scale
+	}
+	c.d.text(tx, ty, label, col, scale)
+}
+
+// highlight umrandet einen markierten Wert, z.B. einen Rekord
+func (c *chartCanvas) highlight(x, y float64, col color.RGBA) {
+	scale := c.theme.fontScale
+	for i := 0; i < scale; i++ {
+		c.d.circle(c.px(x), c.py(y), 6*scale+i, col)
+	}
+}
+
+// note schreibt einen Hinweis rechtsbündig in die Titelzeile, z.B. zu einem Rekord
+func (c *chartCanvas) note(text string, col color.RGBA) {
+	scale := c.theme.fontScale
+	c.d.text(c.bounds.Max.X-textWidth(text, scale)-2*scale, c.bounds.Min.Y+2*scale, text, col, scale)
+}
+
+// encodePNG kodiert ein Bild als PNG
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// niceStep liefert einen runden Abstand (1, 2 oder 5 · 10ⁿ) für Gitterlinien, sodass span in höchstens maxTicks
+// Abschnitte zerfällt
+func niceStep(span float64, maxTicks int) float64 {
+	if span <= 0 || maxTicks < 1 {
+		return 1
+	}
+	raw := span / float64(maxTicks)
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, f := range []float64{1, 2, 5, 10} {
+		if f*magnitude >= raw {
+			return f * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// niceRange erweitert [lo, hi] auf Vielfache von step; ein leerer Bereich wird um einen Schritt aufgeweitet
+func niceRange(lo, hi float64, maxTicks int) (float64, float64, float64) {
+	if hi-lo < 1e-9 {
+		lo, hi = lo-1, hi+1
+	}
+	step := niceStep(hi-lo, maxTicks)
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step
+}
+
+// axisLabel formatiert die Beschriftung einer Achse mit Teilung step, mit Nachkommastelle nur bei Schritten unter 1
+func axisLabel(step float64) func(float64) string {
+	decimals := 0
+	if step < 1 {
+		decimals = 1
+	}
+	return func(v float64) string { return strconv.FormatFloat(v, 'f', decimals, 64) }
+}
+
+// fillRect füllt ein Rechteck (auf das Bild beschnitten)
+func fillRect(img *image.RGBA, r image.Rectangle, col color.RGBA) {
+	draw.Draw(img, r.Intersect(img.Bounds()), &image.Uniform{col}, image.Point{}, draw.Src)
+}
+
+// drawCircle zeichnet einen Kreis mit Radius r um (cx, cy)
+func drawCircle(img *image.RGBA, cx, cy, r int, col color.RGBA) {
+	steps := int(2*math.Pi*float64(r)) + 1
+	for i := 0; i < steps; i++ {
+		a := 2 * math.Pi * float64(i) / float64(steps)
+		img.SetRGBA(cx+int(math.Round(math.Cos(a)*float64(r))), cy+int(math.Round(math.Sin(a)*float64(r))), col)
+	}
+}
+
+// drawLine zeichnet eine Linie der Breite width von (x0, y0) nach (x1, y1) (Bresenham mit quadratischem Pinsel)
+func drawLine(img *image.RGBA, x0, y0, x1, y1, width int, col color.RGBA) {
+	dx, dy := x1-x0, y1-y0
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	half := width / 2
+	err := dx - dy
+	for {
+		fillRect(img, image.Rect(x0-half, y0-half, x0-half+width, y0-half+width), col)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 > -dy {
+			err -= dy
+			x0 += sx
+		}
+		if e2 < dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import "strings"
+
+// Tageswerte, an denen die Diagramme einen Rekord oder eine Serie beschriften
+const (
+	annotateTMax = "tmax" // Höchsttemperatur
+	annotateTMin = "tmin" // Tiefsttemperatur
+	annotateRain = "rain" // Niederschlagssumme
+	annotateSun  = "sun"  // Sonnenscheindauer
+)
+
+// annotationNames benennen die Tageswerte im Alt-Text
+var annotationNames = map[string]string{
+	annotateTMax: "Höchstwert",
+	annotateTMin: "Tiefstwert",
+	annotateRain: "Niederschlag",
+	annotateSun:  "Sonnenschein",
+}
+
+// chartAnnotation ist ein Rekord oder eine Serie aus dem Tagespost, die die Diagramme am betreffenden Tageswert
+// beschriften, damit Bild und Text dasselbe aussagen
+type chartAnnotation struct {
+	section string // Abschnitt des Posts mit der Aussage; entfällt er, entfällt auch die Beschriftung
+	value   string // annotateTMax, annotateTMin, annotateRain, annotateSun
+	label   string // z.B. "Stationsrekord" oder "3. Hitzetag in Folge"
+}
+
+// annotationLabel fasst die Beschriftungen des Tageswerts value zusammen; leer ohne Rekord oder Serie
+func annotationLabel(annotations []chartAnnotation, value string) string {
+	var labels []string
+	for _, a := range annotations {
+		if a.value == value {
+			labels = append(labels, a.label)
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// withAnnotations ergänzt einen Alt-Text um die Beschriftungen der Tageswerte values, z.B. "…; Höchstwert:
+// Stationsrekord"
+func withAnnotations(config Config, text string, annotations []chartAnnotation, values ...string) string {
+	for _, value := range values {
+		if label := annotationLabel(annotations, value); label != "" {
+			text += "; " + tr(config, annotationNames[value]) + ": " + label
+		}
+	}
+	return text
+}
+
+// chartAnnotations liefert die Beschriftungen für die Diagramme, deren Abschnitte im Post stehen (sections)
+func (p dailyPost) chartAnnotations() []chartAnnotation {
+	present := make(map[string]bool)
+	for _, s := range p.sections {
+		present[s.name] = true
+	}
+	var annotations []chartAnnotation
+	for _, a := range p.annotations {
+		if present[a.section] {
+			annotations = append(annotations, a)
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+)
+
+// chartShape ist ein Element einer Diagrammzeichnung, das sich als Pixelbild und als SVG ausgeben lässt
+type chartShape interface {
+	drawPNG(img *image.RGBA)
+	drawSVG(w *svgWriter)
+}
+
+// chartDrawing ist das gemeinsame Modell aller Diagramme: die Diagramme zeichnen in Pixelkoordinaten darauf, die
+// Ausgabe erfolgt danach als PNG (zum Hochladen) oder SVG (chart_dir)
+type chartDrawing struct {
+	width, height int
+	background    color.RGBA
+	fontFamily    string
+	shapes        []chartShape
+}
+
+// newChartDrawing legt eine Zeichnung in der Breite des Themas und der Höhe height an
+func newChartDrawing(theme chartTheme, height int) *chartDrawing {
+	return &chartDrawing{width: theme.width, height: height, background: theme.background, fontFamily: theme.fontFamily}
+}
+
+// bounds liefert die Fläche der Zeichnung
+func (d *chartDrawing) bounds() image.Rectangle {
+	return image.Rect(0, 0, d.width, d.height)
+}
+
+// rect füllt ein Rechteck
+func (d *chartDrawing) rect(r image.Rectangle, col color.RGBA) {
+	if r = r.Canon().Intersect(d.bounds()); !r.Empty() {
+		d.shapes = append(d.shapes, chartRect{r, col})
+	}
+}
+
+// line zeichnet eine Linie der Breite width
+func (d *chartDrawing) line(x0, y0, x1, y1, width int, col color.RGBA) {
+	d.shapes = append(d.shapes, chartLine{x0, y0, x1, y1, width, col})
+}
+
+// text schreibt s mit der oberen linken Ecke bei (x, y) in der Diagrammschrift der Größe scale
+func (d *chartDrawing) text(x, y int, s string, col color.RGBA, scale int) {
+	if s != "" {
+		d.shapes = append(d.shapes, chartText{x, y, s, col, scale})
+	}
+}
+
+// circle zeichnet einen Kreis mit Radius r um (cx, cy)
+func (d *chartDrawing) circle(cx, cy, r int, col color.RGBA) {
+	d.shapes = append(d.shapes, chartCircle{cx, cy, r, col})
+}
+
+// sector füllt den Ringausschnitt zwischen den Radien r0 und r1 und den Richtungen a0 bis a1 (Grad ab Nord im
+// Uhrzeigersinn) um (cx, cy)
+func (d *chartDrawing) sector(cx, cy int, r0, r1, a0, a1 float64, col color.RGBA) {
+	if r1 > r0 {
+		d.shapes = append(d.shapes, chartSector{cx, cy, r0, r1, a0, a1, col})
+	}
+}
+
+// dots füllt ein Rechteck mit einem Punktraster im Abstand spacing
+func (d *chartDrawing) dots(r image.Rectangle, spacing, size int, col color.RGBA) {
+	if r = r.Canon().Intersect(d.bounds()); !r.Empty() {
+		d.shapes = append(d.shapes, chartDots{r, spacing, size, col})
+	}
+}
+
+// png gibt die Zeichnung als PNG aus
+func (d *chartDrawing) png() ([]byte, error) {
+	return encodePNG(d.raster())
+}
+
+// raster zeichnet die Zeichnung als Pixelbild
+func (d *chartDrawing) raster() *image.RGBA {
+	img := image.NewRGBA(d.bounds())
+	draw.Draw(img, img.Bounds(), &image.Uniform{d.background}, image.Point{}, draw.Src)
+	for _, s := range d.shapes {
+		s.drawPNG(img)
+	}
+	return img
+}
+
+// svg gibt die Zeichnung als SVG aus. Texte bleiben Text (in der Breite der Diagrammschrift), damit sie im
+// Browser lesbar und durchsuchbar sind. Mit dark (siehe chartDarkPalette) wechseln die Farben per CSS, wenn
+// der Browser dunkel eingestellt ist.
+func (d *chartDrawing) svg(dark map[color.RGBA]color.RGBA) []byte {
+	w := &svgWriter{fontFamily: d.fontFamily}
+	if dark != nil {
+		w.vars = make(map[color.RGBA]int)
+	}
+	fmt.Fprintf(&w.buf, `<rect width="100%%" height="100%%" %s/>`+"\n", w.paint("fill", d.background))
+	for i, s := range d.shapes {
+		if dots, ok := s.(chartDots); ok {
+			// Punktraster brauchen eine eindeutige Musterkennung
+			dots.drawSVGPattern(w, fmt.Sprintf("dots%d", i))
+			continue
+		}
+		s.drawSVG(w)
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		d.width, d.height, d.width, d.height)
+	if dark != nil {
+		light, night := make([]string, len(w.order)), make([]string, len(w.order))
+		for i, col := range w.order {
+			darkCol, ok := dark[col]
+			if !ok {
+				darkCol = col
+			}
+			light[i] = fmt.Sprintf("--c%d:%s", i, svgColor(col))
+			night[i] = fmt.Sprintf("--c%d:%s", i, svgColor(darkCol))
+		}
+		fmt.Fprintf(&out, "<style>svg{%s}@media (prefers-color-scheme: dark){svg{%s}}</style>\n",
+			strings.Join(light, ";"), strings.Join(night, ";"))
+	}
+	out.Write(w.buf.Bytes())
+	out.WriteString("</svg>\n")
+	return out.Bytes()
+}
+
+// svgWriter sammelt die SVG-Elemente einer Zeichnung und bei wechselnden Farben die verwendeten Farbvariablen
+type svgWriter struct {
+	buf        bytes.Buffer
+	fontFamily string
+	vars       map[color.RGBA]int // nil = feste Farben
+	order      []color.RGBA
+}
+
+// paint liefert die Farbangabe für die Eigenschaft property ("fill" oder "stroke"): als Attribut oder, bei
+// wechselnden Farben, als Stil mit CSS-Variable
+func (w *svgWriter) paint(property string, col color.RGBA) string {
+	if w.vars == nil {
+		return fmt.Sprintf(`%s="%s"`, property, svgColor(col))
+	}
+	i, ok := w.vars[col]
+	if !ok {
+		i = len(w.order)
+		w.vars[col] = i
+		w.order = append(w.order, col)
+	}
+	return fmt.Sprintf(`style="%s:var(--c%d)"`, property, i)
+}
+
+// svgColor formatiert eine Farbe für SVG, z.B. "#2980b9"
+func svgColor(col color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", col.R, col.G, col.B)
+}
+
+type chartRect struct {
+	r   image.Rectangle
+	col color.RGBA
+}
+
+func (s chartRect) drawPNG(img *image.RGBA) {
+	fillRect(img, s.r, s.col)
+}
+
+func (s chartRect) drawSVG(w *svgWriter) {
+	fmt.Fprintf(&w.buf, `<rect x="%d" y="%d" width="%d" height="%d" %s/>`+"\n",
+		s.r.Min.X, s.r.Min.Y, s.r.Dx(), s.r.Dy(), w.paint("fill", s.col))
+}
+
+type chartLine struct {
+	x0, y0, x1, y1, width int
+	col                   color.RGBA
+}
+
+func (s chartLine) drawPNG(img *image.RGBA) {
+	drawLine(img, s.x0, s.y0, s.x1, s.y1, s.width, s.col)
+}
+
+func (s chartLine) drawSVG(w *svgWriter) {
+	fmt.Fprintf(&w.buf, `<line x1="%d" y1="%d" x2="%d" y2="%d" %s stroke-width="%d" stroke-linecap="square"/>`+"\n",
+		s.x0, s.y0, s.x1, s.y1, w.paint("stroke", s.col), s.width)
+}
+
+type chartText struct {
+	x, y  int
+	s     string
+	col   color.RGBA
+	scale int
+}
+
+func (s chartText) drawPNG(img *image.RGBA) {
+	drawText(img, s.x, s.y, s.s, s.col, s.scale)
+}
+
+func (s chartText) drawSVG(w *svgWriter) {
+	fmt.Fprintf(&w.buf, `<text x="%d" y="%d" font-family="%s" font-size="%.1f" textLength="%d" lengthAdjust="spacingAndGlyphs" %s>%s</text>`+"\n",
+		s.x, s.y+glyphHeight*s.scale, html.EscapeString(w.fontFamily), float64(glyphHeight*s.scale)*1.4, textWidth(s.s, s.scale),
+		w.paint("fill", s.col), html.EscapeString(s.s))
+}
+
+type chartCircle struct {
+	cx, cy, r int
+	col       color.RGBA
+}
+
+func (s chartCircle) drawPNG(img *image.RGBA) {
+	drawCircle(img, s.cx, s.cy, s.r, s.col)
+}
+
+func (s chartCircle) drawSVG(w *svgWriter) {
+	fmt.Fprintf(&w.buf, `<circle cx="%d" cy="%d" r="%d" fill="none" %s/>`+"\n", s.cx, s.cy, s.r, w.paint("stroke", s.col))
+}
+
+type chartSector struct {
+	cx, cy         int
+	r0, r1, a0, a1 float64
+	col            color.RGBA
+}
+
+func (s chartSector) drawPNG(img *image.RGBA) {
+	span := math.Mod(s.a1-s.a0+360, 360)
+	r := int(math.Ceil(s.r1))
+	for y := s.cy - r; y <= s.cy+r; y++ {
+		for x := s.cx - r; x <= s.cx+r; x++ {
+			dx, dy := float64(x-s.cx), float64(y-s.cy)
+			dist := math.Hypot(dx, dy)
+			if dist < s.r0 || dist >= s.r1 {
+				continue
+			}
+			angle := math.Atan2(dx, -dy) * 180 / math.Pi
+			if math.Mod(angle-s.a0+720, 360) <= span {
+				img.SetRGBA(x, y, s.col)
+			}
+		}
+	}
+}
+
+func (s chartSector) drawSVG(w *svgWriter) {
+	point := func(r, a float64) (float64, float64) {
+		rad := a * math.Pi / 180
+		return float64(s.cx) + r*math.Sin(rad), float64(s.cy) - r*math.Cos(rad)
+	}
+	large := 0
+	if math.Mod(s.a1-s.a0+360, 360) > 180 {
+		large = 1
+	}
+	x0, y0 := point(s.r1, s.a0)
+	x1, y1 := point(s.r1, s.a1)
+	fmt.Fprintf(&w.buf, `<path d="M%.1f,%.1f A%.1f,%.1f 0 %d 1 %.1f,%.1f`, x0, y0, s.r1, s.r1, large, x1, y1)
+	if s.r0 > 0 {
+		x2, y2 := point(s.r0, s.a1)
+		x3, y3 := point(s.r0, s.a0)
+		fmt.Fprintf(&w.buf, ` L%.1f,%.1f A%.1f,%.1f 0 %d 0 %.1f,%.1f`, x2, y2, s.r0, s.r0, large, x3, y3)
+	} else {
+		fmt.Fprintf(&w.buf, ` L%d,%d`, s.cx, s.cy)
+	}
+	fmt.Fprintf(&w.buf, ` Z" %s/>`+"\n", w.paint("fill", s.col))
+}
+
+type chartDots struct {
+	r             image.Rectangle
+	spacing, size int
+	col           color.RGBA
+}
+
+func (s chartDots) drawPNG(img *image.RGBA) {
+	for y := s.r.Min.Y; y < s.r.Max.Y; y += s.spacing {
+		for x := s.r.Min.X; x < s.r.Max.X; x += s.spacing {
+			fillRect(img, image.Rect(x, y, x+s.size, y+s.size).Intersect(s.r), s.col)
+		}
+	}
+}
+
+func (s chartDots) drawSVG(w *svgWriter) {
+	s.drawSVGPattern(w, "dots")
+}
+
+// drawSVGPattern legt das Punktraster als Muster mit der Kennung id an und füllt damit das Rechteck
+func (s chartDots) drawSVGPattern(w *svgWriter, id string) {
+	fmt.Fprintf(&w.buf, `<pattern id="%s" x="%d" y="%d" width="%d" height="%d" patternUnits="userSpaceOnUse"><rect width="%d" height="%d" %s/></pattern>`+"\n",
+		id, s.r.Min.X, s.r.Min.Y, s.spacing, s.spacing, s.size, s.size, w.paint("fill", s.col))
+	fmt.Fprintf(&w.buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="url(#%s)"/>`+"\n", s.r.Min.X, s.r.Min.Y, s.r.Dx(), s.r.Dy(), id)
+}
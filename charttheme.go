@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Darstellung der Diagramme (chart_style.mode)
+const (
+	chartModeLight = "light" // helles Thema
+	chartModeDark  = "dark"  // dunkles Thema
+	chartModeAuto  = "auto"  // PNG hell, SVG folgt der Hell-/Dunkel-Einstellung des Browsers
+)
+
+// ChartStyle passt Größe, Schrift und Farben der Diagramme an. Farben gelten als Abweichung vom hellen bzw.
+// dunklen Standardthema, z.B. {"background": "#282c34", "line": "#e06c75"}.
+type ChartStyle struct {
+	Mode       string            `json:"mode"`
+	Width      int               `json:"width"`
+	Height     int               `json:"height"`
+	FontScale  int               `json:"font_scale"`
+	FontFamily string            `json:"font_family"`
+	Colors     map[string]string `json:"colors"`
+	DarkColors map[string]string `json:"dark_colors"`
+}
+
+// chartColorRoles liefert die einstellbaren Farben eines Themas nach Namen; die Farbstufen heißen scale1 bis scale5.
+// Die Farbstufen werden dafür kopiert, damit Änderungen das Standardthema nicht berühren.
+func chartColorRoles(theme *chartTheme) map[string]*color.RGBA {
+	theme.scale = append([]color.RGBA(nil), theme.scale...)
+	roles := map[string]*color.RGBA{
+		"background": &theme.background, "text": &theme.text, "grid": &theme.grid, "axis": &theme.axis,
+		"line": &theme.line, "bar": &theme.bar, "solar": &theme.solar, "wind": &theme.wind, "gust": &theme.gust,
+		"high": &theme.high, "low": &theme.low,
+	}
+	for i := range theme.scale {
+		roles["scale"+strconv.Itoa(i+1)] = &theme.scale[i]
+	}
+	return roles
+}
+
+// chartColorNames listet die Namen der einstellbaren Farben für Fehlermeldungen
+func chartColorNames() string {
+	theme := defaultChartTheme
+	var names []string
+	for name := range chartColorRoles(&theme) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// parseChartColor liest eine Farbe als "#rrggbb" oder "#rgb"
+func parseChartColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 || !strings.HasPrefix(s, "#") {
+		return color.RGBA{}, fmt.Errorf("ungültige Farbe %q (erwartet #rrggbb)", s)
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}, nil
+}
+
+// buildChartTheme erzeugt das helle (dark = false) oder dunkle Thema aus Standardthema und chart_style
+func buildChartTheme(style ChartStyle, dark bool) (chartTheme, error) {
+	theme, colors := defaultChartTheme, style.Colors
+	if dark {
+		theme, colors = darkChartTheme, style.DarkColors
+	}
+	if style.Width > 0 {
+		theme.width = style.Width
+	}
+	if style.Height > 0 {
+		theme.height = style.Height
+	}
+	if style.FontScale > 0 {
+		theme.fontScale = style.FontScale
+	}
+	if style.FontFamily != "" {
+		theme.fontFamily = style.FontFamily
+	}
+	roles := chartColorRoles(&theme)
+	for name, value := range colors {
+		role, ok := roles[name]
+		if !ok {
+			return theme, fmt.Errorf("unbekannte Diagrammfarbe %q (möglich: %s)", name, chartColorNames())
+		}
+		col, err := parseChartColor(value)
+		if err != nil {
+			return theme, fmt.Errorf("Diagrammfarbe %s: %v", name, err)
+		}
+		*role = col
+	}
+	return theme, nil
+}
+
+// validateChartStyle prüft chart_style
+func validateChartStyle(config Config) error {
+	style := config.ChartStyle
+	switch style.Mode {
+	case chartModeLight, chartModeDark, chartModeAuto:
+	default:
+		return fmt.Errorf("unbekannte Diagrammdarstellung %q (chart_style.mode: light, dark, auto)", style.Mode)
+	}
+	if style.Width < 200 || style.Height < 150 || style.FontScale < 1 || style.FontScale > 6 {
+		return fmt.Errorf("ungültige Diagrammgröße (chart_style: width >= 200, height >= 150, font_scale 1-6)")
+	}
+	if strings.ContainsAny(style.FontFamily, `"<>&`) {
+		return fmt.Errorf("ungültige Schriftart %q (chart_style.font_family)", style.FontFamily)
+	}
+	for _, dark := range []bool{false, true} {
+		if _, err := buildChartTheme(style, dark); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chartThemeFor liefert das Thema für die PNG-Bilder: dunkel bei mode "dark", sonst hell. Die Einstellungen
+// sind in loadConfig geprüft; bei Fehlern gilt das Standardthema.
+func chartThemeFor(config Config) chartTheme {
+	theme, err := buildChartTheme(config.ChartStyle, config.ChartStyle.Mode == chartModeDark)
+	if err != nil {
+		return defaultChartTheme
+	}
+	return theme
+}
+
+// chartDarkPalette ordnet bei mode "auto" den Farben des hellen Themas die des dunklen zu, damit die SVG-Ausgabe
+// der Hell-/Dunkel-Einstellung des Browsers folgt. Sonst nil.
+func chartDarkPalette(config Config) map[color.RGBA]color.RGBA {
+	if config.ChartStyle.Mode != chartModeAuto {
+		return nil
+	}
+	light, err := buildChartTheme(config.ChartStyle, false)
+	if err != nil {
+		return nil
+	}
+	dark, err := buildChartTheme(config.ChartStyle, true)
+	if err != nil {
+		return nil
+	}
+	lightRoles, darkRoles := chartColorRoles(&light), chartColorRoles(&dark)
+	var names []string
+	for name := range lightRoles {
+		names = append(names, name)
+	}
+	// feste Reihenfolge, falls mehrere Farben im hellen Thema gleich sind
+	sort.Strings(names)
+	palette := make(map[color.RGBA]color.RGBA)
+	for _, name := range names {
+		if _, ok := palette[*lightRoles[name]]; !ok {
+			palette[*lightRoles[name]] = *darkRoles[name]
+		}
+	}
+	return palette
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// chillHours summiert die Stunden mit Temperaturen im Kältebereich (Standard 0–7,2 °C) über die Archivintervalle
+func chillHours(db *sql.DB, config Config, start, end int64) (float64, error) {
+	const q = `SELECT SUM(interval) FROM archive WHERE dateTime >= ? AND dateTime < ? AND outTemp >= ? AND outTemp <= ?;`
+	var minutes sql.NullFloat64
+	if err := db.QueryRow(q, start, end, config.ChillMinTemp, config.ChillMaxTemp).Scan(&minutes); err != nil {
+		return 0, err
+	}
+	return minutes.Float64 / 60, nil
+}
+
+// formatChillHours erzeugt die Zeile mit Kältestunden des Tages und seit Beginn der Ruheperiode.
+// Nur in der konfigurierten Ruheperiode und wenn aktiviert.
+func formatChillHours(db *sql.DB, config Config, day time.Time) string {
+	if !config.ChillHoursEnabled {
+		return ""
+	}
+	seasonFrom, ok := seasonStart(day, config.ChillSeasonStart, config.ChillSeasonEnd)
+	if !ok {
+		return ""
+	}
+	dayEnd := day.AddDate(0, 0, 1)
+	daily, err := chillHours(db, config, day.Unix(), dayEnd.Unix())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Kältestunden nicht verfügbar: %v\n", err)
+		return ""
+	}
+	unit := unitOf(config, quantityTemperature)
+	limits := unit.number(config.ChillMinTemp, 0) + "–" + unit.format(config.ChillMaxTemp, 1)
+	season, err := chillHours(db, config, seasonFrom.Unix(), dayEnd.Unix())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Kältestunden seit Saisonbeginn nicht verfügbar: %v\n", err)
+		return tr(config, "Kältestunden (%s): %.1f h", limits, daily)
+	}
+	return tr(config, "Kältestunden (%s): %.1f h (seit %s: %.0f h)",
+		limits, daily, formatDayMonth(config, seasonFrom), season)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"database/sql"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// archiveRowBytes ist eine großzügige Schätzung des Speicherbedarfs einer verarbeiteten Archivzeile
+const archiveRowBytes = 256
+
+// defaultChunkRows wird verwendet, wenn weder Speicherlimit noch Blockgröße konfiguriert sind
+const defaultChunkRows = 50000
+
+// archiveRowsPerDay ist die Zahl der Archivzeilen eines Tages beim üblichen 5-Minuten-Intervall von weewx
+const archiveRowsPerDay = 288
+
+// applyMemoryLimit setzt das konfigurierte Speicherlimit der Go-Laufzeit
+func applyMemoryLimit(config Config) {
+	if config.MemoryLimitMB > 0 {
+		limit := int64(config.MemoryLimitMB) << 20
+		debug.SetMemoryLimit(limit)
+		log.Printf("Speicherlimit: %d MB", config.MemoryLimitMB)
+	}
+}
+
+// scanChunkRows liefert die Blockgröße (Archivzeilen pro Block): scan_chunk_rows oder aus memory_limit_mb abgeleitet
+func scanChunkRows(config Config) int {
+	if config.ScanChunkRows > 0 {
+		return config.ScanChunkRows
+	}
+	if config.MemoryLimitMB > 0 {
+		// Ein Viertel des Limits für Archivzeilen, der Rest bleibt für SQLite-Cache und Laufzeit
+		rows := int(int64(config.MemoryLimitMB) << 20 / 4 / archiveRowBytes)
+		if rows < 1000 {
+			rows = 1000
+		}
+		return rows
+	}
+	return defaultChunkRows
+}
+
+// archiveChunks zerlegt den Zeitraum [start, end) in aufeinanderfolgende Abschnitte mit höchstens
+// chunkRows Archivzeilen und ruft fn für jeden Abschnitt auf. Die Grenzen werden über den Index
+// auf dateTime ermittelt, so dass nie mehr als ein Block gleichzeitig gelesen werden muss.
+func archiveChunks(db *sql.DB, start, end int64, chunkRows int, fn func(chunkStart, chunkEnd int64) error) error {
+	const qBoundary = `SELECT dateTime FROM archive WHERE dateTime >= ? AND dateTime < ? ORDER BY dateTime LIMIT 1 OFFSET ?;`
+	for chunkStart := start; chunkStart < end; {
+		var boundary int64
+		err := db.QueryRow(qBoundary, chunkStart, end, chunkRows).Scan(&boundary)
+		if err == sql.ErrNoRows {
+			return fn(chunkStart, end)
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(chunkStart, boundary); err != nil {
+			return err
+		}
+		chunkStart = boundary
+	}
+	return nil
+}
+
+// archiveDays liefert die lokalen Kalendertage (Format 2006-01-02), für die Archivdaten vorliegen.
+// SQLite liefert je Block nur die Stunden mit Daten (SELECT DISTINCT), höchstens 24 Zeilen pro Tag; weil die
+// Zeitzone nur um ganze Stunden verschoben ist, liegt jede Stunde vollständig in einem lokalen Tag.
+func archiveDays(db *sql.DB, loc *time.Location, start, end int64, chunkRows int) (map[string]bool, error) {
+	days := make(map[string]bool)
+	err := archiveChunks(db, start, end, chunkRows, func(chunkStart, chunkEnd int64) error {
+		rows, err := db.Query(`SELECT DISTINCT dateTime / 3600 FROM archive WHERE dateTime >= ? AND dateTime < ?;`, chunkStart, chunkEnd)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var hour int64
+			if err := rows.Scan(&hour); err != nil {
+				return err
+			}
+			days[time.Unix(hour*3600, 0).In(loc).Format("2006-01-02")] = true
+		}
+		return rows.Err()
+	})
+	return days, err
+}
+
+// eachDailyValue liest die Tageswerte von series für die Tage [from, to] (inklusive) in Blöcken von höchstens
+// scan_chunk_rows Archivzeilen und ruft fn für jeden Tageswert in Tagesreihenfolge auf. So bleiben auch Auswertungen
+// über das ganze Archiv (Stationsrekorde, Bestenlisten) im Speicherrahmen von memory_limit_mb.
+func eachDailyValue(db *sql.DB, config Config, series dailyValues, from, to time.Time, fn func(v dailyValue)) error {
+	days := scanChunkRows(config) / archiveRowsPerDay
+	if days < 1 {
+		days = 1
+	}
+	for blockFrom := from; !blockFrom.After(to); blockFrom = blockFrom.AddDate(0, 0, days) {
+		blockTo := blockFrom.AddDate(0, 0, days-1)
+		if blockTo.After(to) {
+			blockTo = to
+		}
+		values, err := series(db, config, blockFrom, blockTo)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			fn(v)
+		}
+	}
+	return nil
+}
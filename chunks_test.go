@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestArchiveDays(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Umstellung auf Sommerzeit am 31.03.2024; 0:30 Uhr Ortszeit liegt noch am Vortag in UTC
+	samples := map[time.Time]map[string]float64{
+		time.Date(2024, 3, 30, 23, 55, 0, 0, loc): {"outTemp": 5},
+		time.Date(2024, 3, 31, 0, 30, 0, 0, loc):  {"outTemp": 5},
+		time.Date(2024, 3, 31, 3, 10, 0, 0, loc):  {"outTemp": 4},
+		time.Date(2024, 4, 2, 12, 0, 0, 0, loc):   {"outTemp": 9},
+	}
+	db := testArchive(t, samples)
+	start := time.Date(2024, 3, 30, 0, 0, 0, 0, loc)
+	end := time.Date(2024, 4, 3, 0, 0, 0, 0, loc)
+	for _, chunkRows := range []int{1, 2, 1000} {
+		days, err := archiveDays(db, loc, start.Unix(), end.Unix(), chunkRows)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, day := range []string{"2024-03-30", "2024-03-31", "2024-04-02"} {
+			if !days[day] {
+				t.Errorf("Blockgröße %d: %s fehlt in %v", chunkRows, day, days)
+			}
+		}
+		if len(days) != 3 {
+			t.Errorf("Blockgröße %d: got %v, want 3 Tage", chunkRows, days)
+		}
+	}
+}
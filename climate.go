@@ -0,0 +1,98 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// climateDayType beschreibt einen klimatologischen Kenntag nach DWD-Definition
+type climateDayType struct {
+	name      string
+	plural    string
+	test      func(tMax, tMin float64) bool // nil: nicht aus den Tageswerten bestimmbar
+	impliedBy string                        // spezifischerer Kenntag, der diesen einschließt (wird dann nicht separat erwähnt)
+}
+
+// climateDayTypes sind die klimatologischen Kenntage in der Reihenfolge ihrer Erwähnung im Post
+var climateDayTypes = []climateDayType{
+	{"Eistag", "Eistage", func(tMax, tMin float64) bool { return tMax < 0 }, ""},
+	{"Frosttag", "Frosttage", func(tMax, tMin float64) bool { return tMin < 0 }, "Eistag"},
+	{"Hitzetag", "Hitzetage", func(tMax, tMin float64) bool { return tMax >= 30 }, ""},
+	{"Sommertag", "Sommertage", func(tMax, tMin float64) bool { return tMax >= 25 }, "Hitzetag"},
+	// Tropennächte zählen nach dem Nachtfenster (tropical_night_from/-to), nicht nach dem Tiefstwert des Kalendertags
+	{"Tropennacht", "Tropennächte", nil, ""},
+}
+
+// classifyDay liefert die Namen aller Kenntage, die auf einen Tag zutreffen
+func classifyDay(tMax, tMin float64) []string {
+	var types []string
+	if math.IsNaN(tMax) || math.IsNaN(tMin) {
+		return types
+	}
+	for _, t := range climateDayTypes {
+		if t.test != nil && t.test(tMax, tMin) {
+			types = append(types, t.name)
+		}
+	}
+	return types
+}
+
+// countClimateDays zählt die Kenntage je Typ im Zeitraum [from, to] (inklusive) aus den Tageswerten, die Tropennächte
+// wie formatTropicalNight aus den Nächten, die am Morgen der Tage enden
+func countClimateDays(db *sql.DB, config Config, from, to time.Time) (map[string]int, error) {
+	temps, err := dailyTemperatures(db, config, from, to)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, t := range temps {
+		for _, name := range classifyDay(t.tMax, t.tMin) {
+			counts[name]++
+		}
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		nightMin, err := getNightMin(db, from.Location(), config, day)
+		if err != nil {
+			return nil, err
+		}
+		if isTropicalNight(dayStats{nightMin: nightMin}, config) {
+			counts["Tropennacht"]++
+		}
+	}
+	return counts, nil
+}
+
+// formatClimateDays erzeugt den Satz zu den Kenntagen des Tages mit laufender Monatszählung,
+// z.B. "3. Hitzetag in diesem Monat". Eingeschlossene Kenntage (Sommertag bei Hitzetag) entfallen.
+func formatClimateDays(db *sql.DB, config Config, day time.Time, s dayStats) string {
+	types := classifyDay(s.tMax, s.tMin)
+	if len(types) == 0 {
+		return ""
+	}
+	monthStart := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	counts, err := countClimateDays(db, config, monthStart, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Kenntage des Monats nicht verfügbar: %v\n", err)
+	}
+
+	present := make(map[string]bool)
+	for _, name := range types {
+		present[name] = true
+	}
+	var parts []string
+	for _, t := range climateDayTypes {
+		if !present[t.name] || (t.impliedBy != "" && present[t.impliedBy]) {
+			continue
+		}
+		if n := counts[t.name]; n > 0 {
+			parts = append(parts, tr(config, "%s %s in diesem Monat", ordinal(config, n), tr(config, t.name)))
+		} else {
+			parts = append(parts, tr(config, t.name))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCountClimateDaysTropicalNights(t *testing.T) {
+	loc := time.UTC
+	day := func(d, hour int) time.Time { return time.Date(2024, 7, d, hour, 0, 0, 0, loc) }
+	// Nacht zum 2.: nicht unter 21 °C, aber am Abend des 2. nur 18 °C (Kalendertag kein Tiefstwert über 20 °C);
+	// Nacht zum 3.: 18 °C
+	db := testArchive(t, map[time.Time]map[string]float64{
+		day(1, 12): {"outTemp": 31},
+		day(1, 22): {"outTemp": 24},
+		day(2, 4):  {"outTemp": 21},
+		day(2, 15): {"outTemp": 33},
+		day(2, 23): {"outTemp": 18},
+		day(3, 5):  {"outTemp": 19},
+		day(3, 15): {"outTemp": 29},
+	})
+	config := DefaultConfig()
+	config.DailySource = dailySourceArchive
+	config.StorePath = filepath.Join(t.TempDir(), "store.sdb")
+
+	counts, err := countClimateDays(db, config, day(2, 0), day(3, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"Tropennacht": 1, "Hitzetag": 1, "Sommertag": 2}
+	for name, n := range want {
+		if counts[name] != n {
+			t.Errorf("%s: got %d, want %d (%v)", name, counts[name], n, counts)
+		}
+	}
+	if types := classifyDay(33, 21); len(types) != 2 {
+		t.Errorf("classifyDay nennt nur Kenntage aus den Tageswerten: got %v", types)
+	}
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// climateRainLimit ist die Niederschlagsmenge (mm), ab der das Klimadiagramm den Maßstab auf ein Zehntel verkürzt
+const climateRainLimit = 100.0
+
+// climateMonth sind Mitteltemperatur (°C) und Niederschlagssumme (mm) eines Monats; NaN ohne Daten
+type climateMonth struct {
+	month time.Time // Monatserster
+	temp  float64
+	rain  float64
+}
+
+// getClimateMonths liefert die Monatswerte der count Monate ab dem Monat von first. Die Mitteltemperatur ist das
+// Mittel der Tagesmittel aus Höchst- und Tiefstwert, der Niederschlag die Summe der Niederschlagstage.
+func getClimateMonths(db *sql.DB, loc *time.Location, config Config, first time.Time, count int) ([]climateMonth, error) {
+	var months []climateMonth
+	for i := 0; i < count; i++ {
+		start := time.Date(first.Year(), first.Month()+time.Month(i), 1, 0, 0, 0, 0, loc)
+		end := start.AddDate(0, 1, -1)
+		m := climateMonth{month: start, temp: math.NaN(), rain: math.NaN()}
+		temps, err := dailyTemperatures(db, config, start, end)
+		if err != nil {
+			return nil, err
+		}
+		if len(temps) > 0 {
+			sum := 0.0
+			for _, t := range temps {
+				sum += (t.tMax + t.tMin) / 2
+			}
+			m.temp = sum / float64(len(temps))
+		}
+		for _, r := range getDailyRain(db, loc, config, start, end) {
+			if math.IsNaN(r) {
+				continue
+			}
+			if math.IsNaN(m.rain) {
+				m.rain = 0
+			}
+			m.rain += r
+		}
+		months = append(months, m)
+	}
+	return months, nil
+}
+
+// climateRainScale rechnet eine Niederschlagsmenge in die Temperaturskala des Klimadiagramms um: 10 °C entsprechen
+// 20 mm, über climateRainLimit 200 mm
+func climateRainScale(rain float64) float64 {
+	if rain <= climateRainLimit {
+		return rain / 2
+	}
+	return climateRainLimit/2 + (rain-climateRainLimit)/20
+}
+
+// climateTempToRain ist die Umkehrung von climateRainScale für die Beschriftung der rechten Achse
+func climateTempToRain(t float64) float64 {
+	if t <= climateRainLimit/2 {
+		return t * 2
+	}
+	return climateRainLimit + (t-climateRainLimit/2)*20
+}
+
+// climateTotals liefert Mitteltemperatur und Niederschlagssumme über alle Monate mit Daten
+func climateTotals(months []climateMonth) (float64, float64) {
+	temp, rain := 0.0, math.NaN()
+	n := 0
+	for _, m := range months {
+		if !math.IsNaN(m.temp) {
+			temp += m.temp
+			n++
+		}
+		if !math.IsNaN(m.rain) {
+			if math.IsNaN(rain) {
+				rain = 0
+			}
+			rain += m.rain
+		}
+	}
+	if n == 0 {
+		return math.NaN(), rain
+	}
+	return temp / float64(n), rain
+}
+
+// renderClimateDiagram zeichnet ein Klimadiagramm nach Walter und Lieth als PNG: Monatsmittel der Temperatur als
+// Kurve (linke Achse) und Monatsniederschlag als Balken (rechte Achse) im Maßstab 10 °C = 20 mm, über 100 mm auf
+// ein Zehntel verkürzt und dunkel gefüllt. Liegt die Temperatur über dem Niederschlag (aride Monate), ist der
+// Abstand gepunktet markiert. Das Diagramm ist wie üblich immer in °C und mm beschriftet.
+func renderClimateDiagram(config Config, theme chartTheme, months []climateMonth) (*chartDrawing, error) {
+	lo, hi := 0.0, 20.0
+	valid := false
+	for _, m := range months {
+		if !math.IsNaN(m.temp) {
+			lo, hi = math.Min(lo, m.temp), math.Max(hi, m.temp)
+			valid = true
+		}
+		if !math.IsNaN(m.rain) {
+			hi = math.Max(hi, climateRainScale(m.rain))
+			valid = true
+		}
+	}
+	if !valid || len(months) == 0 {
+		return nil, fmt.Errorf("keine Monatswerte")
+	}
+	const step = 10.0
+	yMin, yMax := math.Floor(lo/step)*step, math.Ceil(hi/step)*step
+
+	c := newChartCanvas(theme)
+	scale := theme.fontScale
+	// rechts Platz für die Niederschlagsachse
+	c.plot.Max.X -= textWidth("000", scale) + 2*scale
+	c.setRange(0, float64(len(months)), yMin, yMax)
+	last := months[len(months)-1].month
+	c.title(tr(config, "Klimadiagramm %s bis %s", monthYear(config, months[0].month), monthYear(config, last)), "°C")
+	temp, rain := climateTotals(months)
+	summary := ""
+	if !math.IsNaN(temp) {
+		summary = fmt.Sprintf("%.1f °C", temp)
+	}
+	if !math.IsNaN(rain) {
+		summary = strings.TrimSpace(summary + fmt.Sprintf("  %.0f mm", rain))
+	}
+	c.d.text(c.bounds.Max.X-textWidth(summary, scale)-2*scale, c.bounds.Min.Y+2*scale, summary, theme.text, scale)
+	c.d.text(c.plot.Max.X+2*scale, c.plot.Min.Y-(glyphHeight+4)*scale, "mm", theme.text, scale)
+	c.yAxis(step, axisLabel(step))
+	for v := math.Max(yMin, 0); v <= yMax+step/1000; v += step {
+		label := fmt.Sprintf("%.0f", climateTempToRain(v))
+		c.d.text(c.plot.Max.X+2*scale, c.py(v)-glyphHeight*scale/2, label, theme.text, scale)
+	}
+	c.d.rect(image.Rect(c.plot.Max.X, c.plot.Min.Y, c.plot.Max.X+1, c.plot.Max.Y+1), theme.axis)
+	if yMin < 0 {
+		c.d.rect(image.Rect(c.plot.Min.X, c.py(0), c.plot.Max.X, c.py(0)+1), theme.axis)
+	}
+	var ticks []chartTick
+	for i, m := range months {
+		name := monthName(config, m.month.Month())
+		r, _ := utf8.DecodeRuneInString(name)
+		ticks = append(ticks, chartTick{x: float64(i) + 0.5, label: string(r)})
+	}
+	c.xAxis(ticks)
+
+	var points []chartPoint
+	for i, m := range months {
+		x0, x1 := float64(i)+0.15, float64(i)+0.85
+		if !math.IsNaN(m.rain) {
+			top := climateRainScale(m.rain)
+			c.bar(x0, x1, math.Min(top, climateRainLimit/2), theme.bar)
+			if top > climateRainLimit/2 {
+				c.d.rect(image.Rect(c.px(x0)+1, c.py(top), c.px(x1), c.py(climateRainLimit/2)), theme.text)
+			}
+			if !math.IsNaN(m.temp) && m.temp > top {
+				c.dotted(x0, x1, top, m.temp, theme.high)
+			}
+		}
+		points = append(points, chartPoint{x: float64(i) + 0.5, y: m.temp})
+	}
+	c.polyline(points, theme.line)
+	return c.d, nil
+}
+
+// dotted füllt das Rechteck zwischen x0, x1 und y0, y1 mit einem Punktraster
+func (c *chartCanvas) dotted(x0, x1, y0, y1 float64, col color.RGBA) {
+	r := image.Rect(c.px(x0)+1, c.py(y1), c.px(x1), c.py(y0)).Intersect(c.plot)
+	c.d.dots(r, 2*c.theme.fontScale, c.theme.fontScale/2+1, col)
+}
+
+// monthYear formatiert Monat und Jahr, z.B. "Oktober 2024"
+func monthYear(config Config, t time.Time) string {
+	return fmt.Sprintf("%s %d", monthName(config, t.Month()), t.Year())
+}
+
+// altTextClimateDiagram beschreibt das Klimadiagramm, z.B. "Klimadiagramm Januar 2024 bis Dezember 2024: Mittel
+// 9.8 °C, Niederschlag 712 mm; wärmster Monat Juli (18.9 °C), kältester Monat Januar (0.8 °C), nassester Monat Juni
+// (95 mm), trockenster Monat April (21 mm); aride Monate: 2"
+func altTextClimateDiagram(config Config, months []climateMonth) string {
+	if len(months) == 0 {
+		return ""
+	}
+	tempUnit, rainUnit := unitOf(config, quantityTemperature), unitOf(config, quantityRain)
+	text := tr(config, "Klimadiagramm %s bis %s", monthYear(config, months[0].month), monthYear(config, months[len(months)-1].month))
+	temp, rain := climateTotals(months)
+	var totals, extremes []string
+	if !math.IsNaN(temp) {
+		totals = append(totals, tr(config, "Mittel %s", tempUnit.format(temp, 1)))
+	}
+	if !math.IsNaN(rain) {
+		totals = append(totals, tr(config, "Niederschlag %s", rainUnit.format(rain, 0)))
+	}
+	warmest, coldest, wettest, driest, arid := -1, -1, -1, -1, 0
+	for i, m := range months {
+		if !math.IsNaN(m.temp) {
+			if warmest < 0 || m.temp > months[warmest].temp {
+				warmest = i
+			}
+			if coldest < 0 || m.temp < months[coldest].temp {
+				coldest = i
+			}
+		}
+		if !math.IsNaN(m.rain) {
+			if wettest < 0 || m.rain > months[wettest].rain {
+				wettest = i
+			}
+			if driest < 0 || m.rain < months[driest].rain {
+				driest = i
+			}
+			if !math.IsNaN(m.temp) && m.temp > climateRainScale(m.rain) {
+				arid++
+			}
+		}
+	}
+	name := func(i int) string { return monthName(config, months[i].month.Month()) }
+	if warmest >= 0 {
+		extremes = append(extremes,
+			tr(config, "wärmster Monat %s (%s)", name(warmest), tempUnit.format(months[warmest].temp, 1)),
+			tr(config, "kältester Monat %s (%s)", name(coldest), tempUnit.format(months[coldest].temp, 1)))
+	}
+	if wettest >= 0 {
+		extremes = append(extremes,
+			tr(config, "nassester Monat %s (%s)", name(wettest), rainUnit.format(months[wettest].rain, 0)),
+			tr(config, "trockenster Monat %s (%s)", name(driest), rainUnit.format(months[driest].rain, 0)))
+	}
+	text += ": " + strings.Join(totals, ", ")
+	if len(extremes) > 0 {
+		text += "; " + strings.Join(extremes, ", ")
+	}
+	if arid > 0 {
+		text += "; " + tr(config, "aride Monate: %d", arid)
+	}
+	return text
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+)
+
+// cloudBaseFactor ist die Faustformel für die Höhe der Wolkenbasis: etwa 125 m je Kelvin
+// Abstand zwischen Temperatur und Taupunkt (Spread)
+const cloudBaseFactor = 125.0
+
+// fogSpread ist der Spread (K), unter dem Nebel bzw. stark eingeschränkte Sicht wahrscheinlich ist
+const fogSpread = 1.0
+
+// getCloudBase schätzt die niedrigste und höchste Wolkenbasis (m über Grund) des Zeitraums aus dem
+// Spread zwischen outTemp und Taupunkt. Fehlt die dewpoint-Spalte, wird der Taupunkt aus der Luftfeuchte berechnet.
+func getCloudBase(db *sql.DB, start, end int64) (float64, float64, error) {
+	baseMin, baseMax := math.NaN(), math.NaN()
+
+	dewColumn := "NULL"
+	if archiveHasColumn(db, "dewpoint") {
+		dewColumn = "dewpoint"
+	}
+	q := `SELECT ` + dewColumn + `, outTemp, outHumidity FROM archive WHERE dateTime >= ? AND dateTime < ? AND outTemp IS NOT NULL;`
+	rows, err := db.Query(q, start, end)
+	if err != nil {
+		return baseMin, baseMax, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dew, temp, hum sql.NullFloat64
+		if err := rows.Scan(&dew, &temp, &hum); err != nil {
+			return baseMin, baseMax, err
+		}
+		var d float64
+		switch {
+		case dew.Valid:
+			d = dew.Float64
+		case hum.Valid && hum.Float64 > 0:
+			d = dewpointFromHumidity(temp.Float64, hum.Float64)
+		default:
+			continue
+		}
+		base := cloudBaseFactor * math.Max(temp.Float64-d, 0)
+		if math.IsNaN(baseMin) || base < baseMin {
+			baseMin = base
+		}
+		if math.IsNaN(baseMax) || base > baseMax {
+			baseMax = base
+		}
+	}
+	return baseMin, baseMax, rows.Err()
+}
+
+// formatCloudBase erzeugt die Zeile zur geschätzten Wolkenbasis, leer wenn deaktiviert oder ohne Daten
+func formatCloudBase(s dayStats, config Config) string {
+	if !config.CloudBaseEnabled || math.IsNaN(s.cloudBaseMin) {
+		return ""
+	}
+	unit := unitOf(config, quantityHeight)
+	line := tr(config, "☁️ Geschätzte Wolkenbasis: %s bis %s %s", unit.number(roundTo(s.cloudBaseMin, 50), 0), unit.number(roundTo(s.cloudBaseMax, 50), 0), unit.symbol)
+	if s.cloudBaseMin < cloudBaseFactor*fogSpread {
+		line += tr(config, " (zeitweise Nebel möglich)")
+	}
+	return line
+}
+
+// roundTo rundet v auf ein Vielfaches von step, da die Schätzung keine genaueren Angaben hergibt
+func roundTo(v, step float64) float64 {
+	return math.Round(v/step) * step
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Post-Arten, für die sich Einstellungen wie die Inhaltswarnung (content_warnings) getrennt festlegen lassen
+const (
+	postTypeDaily         = "daily"          // Tagespost
+	postTypeAlert         = "alert"          // Warn-Post der Hinweisregeln
+	postTypeTopList       = "top_list"       // Bestenliste
+	postTypeYearReview    = "year_review"    // Jahresrückblick
+	postTypeSeason        = "season"         // Rückblick auf eine Jahreszeit
+	postTypeCardinalPoint = "cardinal_point" // Post zu Tagundnachtgleiche und Sonnenwende
+	postTypeRange         = "range"          // Zusammenfassung eines Zeitraums (-from/-to mit -post)
+)
+
+// postTypes sind alle Post-Arten in der Reihenfolge der Dokumentation
+var postTypes = []string{postTypeDaily, postTypeAlert, postTypeTopList, postTypeYearReview, postTypeSeason, postTypeCardinalPoint, postTypeRange}
+
+// validatePostTypes prüft, dass eine Einstellung (name) je Post-Art nur bekannte Post-Arten enthält
+func validatePostTypes(name string, settings map[string]string) error {
+	for postType := range settings {
+		if !isPostType(postType) {
+			return fmt.Errorf("unbekannte Post-Art %q in %s (daily, alert, top_list, year_review, season, cardinal_point, range)", postType, name)
+		}
+	}
+	return nil
+}
+
+// isPostType prüft, ob name eine bekannte Post-Art ist
+func isPostType(name string) bool {
+	for _, t := range postTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// summaryData ist der Datenkontext der Inhaltswarnung für Posts ohne Tageswerte (Rückblicke, Warn-Posts)
+type summaryData struct {
+	Title string
+	Text  string
+}
+
+// contentWarning liefert die Inhaltswarnung (Mastodon spoiler_text) für eine Post-Art. Das Template aus
+// content_warnings wird mit data gefüllt; ohne Eintrag oder bei Fehlern im Template gibt es keine Inhaltswarnung.
+func contentWarning(config Config, postType string, data interface{}) string {
+	return renderTemplate("content_warning_"+postType, config.ContentWarnings[postType], data, "")
+}
+
+// mastodonLimit liefert das Zeichenlimit für den Text eines Mastodon-Posts: Mastodon zählt die Inhaltswarnung mit.
+// 0 bedeutet kein Limit.
+func mastodonLimit(config Config, cw string) int {
+	limit := config.CharLimits[platformMastodon]
+	if limit <= 0 {
+		return 0
+	}
+	if limit -= utf8.RuneCountInString(cw); limit < 1 {
+		return 1
+	}
+	return limit
+}
+
+// fitMastodonText kürzt einen fertigen Mastodon-Post so, dass er zusammen mit der Inhaltswarnung in das Zeichenlimit passt
+func fitMastodonText(config Config, cw, text string) string {
+	if limit := mastodonLimit(config, cw); limit > 0 {
+		return truncateText(text, limit)
+	}
+	return text
+}
+
+// mastodonVisibilityValues sind die Sichtbarkeiten, die Mastodon für einen Status kennt
+var mastodonVisibilityValues = []string{"public", "unlisted", "private", "direct"}
+
+// validateMastodonSettings prüft Sichtbarkeit, Sprache und Medien-Wartezeit der Mastodon-Posts
+func validateMastodonSettings(config Config) error {
+	if err := validatePostTypes("mastodon_visibilities", config.MastodonVisibilities); err != nil {
+		return err
+	}
+	for postType, visibility := range config.MastodonVisibilities {
+		if !isMastodonVisibility(visibility) {
+			return fmt.Errorf("unbekannte Sichtbarkeit %q für %s in mastodon_visibilities (public, unlisted, private, direct)", visibility, postType)
+		}
+	}
+	if config.MastodonVisibility != "" && !isMastodonVisibility(config.MastodonVisibility) {
+		return fmt.Errorf("unbekannte Sichtbarkeit %q in mastodon_visibility (public, unlisted, private, direct)", config.MastodonVisibility)
+	}
+	if config.MastodonMediaTimeout < 1 {
+		return fmt.Errorf("mastodon_media_timeout muss mindestens 1 Sekunde sein")
+	}
+	if n := len(config.MastodonLanguage); n != 0 && (n < 2 || n > 3 || strings.ToLower(config.MastodonLanguage) != config.MastodonLanguage) {
+		return fmt.Errorf("ungültige Sprache %q in mastodon_language (ISO 639, z.B. \"de\")", config.MastodonLanguage)
+	}
+	return nil
+}
+
+// isMastodonVisibility prüft, ob v eine Sichtbarkeit von Mastodon ist
+func isMastodonVisibility(v string) bool {
+	for _, known := range mastodonVisibilityValues {
+		if v == known {
+			return true
+		}
+	}
+	return false
+}
+
+// mastodonVisibility liefert die Sichtbarkeit für eine Post-Art: den Eintrag aus mastodon_visibilities, sonst
+// mastodon_visibility
+func mastodonVisibility(config Config, postType string) string {
+	if v := config.MastodonVisibilities[postType]; v != "" {
+		return v
+	}
+	return config.MastodonVisibility
+}
+
+// mastodonLanguage liefert die Sprache der Mastodon-Posts: mastodon_language, sonst die Sprache der Posts (language)
+func mastodonLanguage(config Config) string {
+	if config.MastodonLanguage != "" {
+		return config.MastodonLanguage
+	}
+	return config.Language
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"time"
+)
+
+// Quellen für Tageswerte (daily_source)
+const (
+	dailySourceWeewx   = "weewx"   // Tageszusammenfassungen von weewx (archive_day_*)
+	dailySourceArchive = "archive" // aus der archive-Tabelle neu berechnet (Zustandsspeicher, siehe -rebuild-daily)
+)
+
+// dailyTemp sind Höchst- und Tiefstwert eines Tages
+type dailyTemp struct {
+	day        time.Time
+	tMax, tMin float64
+}
+
+// dailyValue ist der Tageswert einer Messgröße
+type dailyValue struct {
+	day   time.Time
+	value float64
+}
+
+// dailyValues liefert die Tageswerte einer Messgröße für die Tage [from, to] (inklusive) in Tagesreihenfolge, wie
+// dailyTemperatures nach daily_source bzw. rainOnDay nach rain_day_start_hour. Tage ohne Daten fehlen im Ergebnis.
+type dailyValues func(db *sql.DB, config Config, from, to time.Time) ([]dailyValue, error)
+
+// dailyMaxima liefert die Tageshöchstwerte der Temperatur
+func dailyMaxima(db *sql.DB, config Config, from, to time.Time) ([]dailyValue, error) {
+	return temperatureValues(db, config, from, to, func(t dailyTemp) float64 { return t.tMax })
+}
+
+// dailyMinima liefert die Tagestiefstwerte der Temperatur
+func dailyMinima(db *sql.DB, config Config, from, to time.Time) ([]dailyValue, error) {
+	return temperatureValues(db, config, from, to, func(t dailyTemp) float64 { return t.tMin })
+}
+
+// temperatureValues wählt mit value einen Wert je Tag aus dailyTemperatures
+func temperatureValues(db *sql.DB, config Config, from, to time.Time, value func(t dailyTemp) float64) ([]dailyValue, error) {
+	temps, err := dailyTemperatures(db, config, from, to)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]dailyValue, len(temps))
+	for i, t := range temps {
+		values[i] = dailyValue{t.day, value(t)}
+	}
+	return values, nil
+}
+
+// firstArchiveDay liefert den ersten Tag mit Daten in der archive-Tabelle (Mitternacht in loc)
+func firstArchiveDay(db *sql.DB, loc *time.Location) (time.Time, bool) {
+	var first sql.NullInt64
+	if err := db.QueryRow(`SELECT MIN(dateTime) FROM archive;`).Scan(&first); err != nil || !first.Valid {
+		return time.Time{}, false
+	}
+	t := time.Unix(first.Int64, 0).In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc), true
+}
+
+// dailyTemperatures liefert Höchst- und Tiefstwert für die Tage [from, to] (inklusive) in Tagesreihenfolge.
+// Tage ohne Daten fehlen im Ergebnis. Die Quelle richtet sich nach daily_source.
+func dailyTemperatures(db *sql.DB, config Config, from, to time.Time) ([]dailyTemp, error) {
+	if config.DailySource == dailySourceArchive {
+		return archiveDailyTemperatures(db, config, from, to)
+	}
+	const q = `SELECT dateTime, max, min FROM archive_day_outTemp WHERE dateTime >= ? AND dateTime < ? ORDER BY dateTime;`
+	rows, err := db.Query(q, from.Unix(), to.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var temps []dailyTemp
+	for rows.Next() {
+		var ts int64
+		var tMax, tMin sql.NullFloat64
+		if err := rows.Scan(&ts, &tMax, &tMin); err != nil {
+			return nil, err
+		}
+		if tMax.Valid && tMin.Valid {
+			temps = append(temps, dailyTemp{time.Unix(ts, 0).In(from.Location()), tMax.Float64, tMin.Float64})
+		}
+	}
+	return temps, rows.Err()
+}
+
+// archiveDailyTemperatures liest die Tageswerte aus dem Zustandsspeicher. Dort fehlende Tage
+// (z.B. gestern vor dem ersten Speichern) werden direkt aus der archive-Tabelle berechnet.
+func archiveDailyTemperatures(db *sql.DB, config Config, from, to time.Time) ([]dailyTemp, error) {
+	stored := make(map[string]dailyTemp)
+	if store, err := sql.Open("sqlite3", config.StorePath); err == nil {
+		defer store.Close()
+		const q = `SELECT day, t_max, t_min FROM daily_stats WHERE day >= ? AND day <= ?;`
+		// Ohne Tabelle (noch kein Neuaufbau) wird alles aus archive berechnet
+		if rows, err := store.Query(q, from.Format("2006-01-02"), to.Format("2006-01-02")); err == nil {
+			for rows.Next() {
+				var day string
+				var tMax, tMin sql.NullFloat64
+				if err := rows.Scan(&day, &tMax, &tMin); err != nil {
+					rows.Close()
+					return nil, err
+				}
+				if tMax.Valid && tMin.Valid {
+					stored[day] = dailyTemp{tMax: tMax.Float64, tMin: tMin.Float64}
+				}
+			}
+			rows.Close()
+		}
+	}
+
+	var temps []dailyTemp
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		t, ok := stored[d.Format("2006-01-02")]
+		if !ok {
+			var err error
+			t.tMin, t.tMax, err = columnExtremes(db, "outTemp", d.Unix(), d.AddDate(0, 0, 1).Unix())
+			if err != nil {
+				return nil, err
+			}
+			if math.IsNaN(t.tMax) {
+				continue
+			}
+		}
+		t.day = d
+		temps = append(temps, t)
+	}
+	return temps, nil
+}
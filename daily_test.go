@@ -0,0 +1,39 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveDailyTemperatures(t *testing.T) {
+	loc := time.UTC
+	day := time.Date(2024, 7, 1, 0, 0, 0, 0, loc)
+	db := testArchive(t, map[time.Time]map[string]float64{
+		day.Add(4 * time.Hour):                   {"outTemp": 12.5},
+		day.Add(15 * time.Hour):                  {"outTemp": 28.0},
+		day.Add(22 * time.Hour):                  {"outTemp": 19.0},
+		day.AddDate(0, 0, 1).Add(5 * time.Hour):  {"outTemp": -1.5},
+		day.AddDate(0, 0, 1).Add(14 * time.Hour): {"outTemp": 3.0},
+	})
+	// Zustandsspeicher ohne daily_stats: alle Tage kommen aus archive
+	config := DefaultConfig()
+	config.StorePath = filepath.Join(t.TempDir(), "store.sdb")
+
+	temps, err := archiveDailyTemperatures(db, config, day, day.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []dailyTemp{
+		{day, 28.0, 12.5},
+		{day.AddDate(0, 0, 1), 3.0, -1.5},
+	}
+	if len(temps) != len(want) {
+		t.Fatalf("got %d days, want %d (%v)", len(temps), len(want), temps)
+	}
+	for i, w := range want {
+		if !temps[i].day.Equal(w.day) || temps[i].tMax != w.tMax || temps[i].tMin != w.tMin {
+			t.Errorf("day %d: got %+v, want %+v", i, temps[i], w)
+		}
+	}
+}
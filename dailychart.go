@@ -0,0 +1,245 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+	"time"
+)
+
+// dailyPanelHeight ist die Höhe eines Teildiagramms im kombinierten Tagesdiagramm als Anteil der Themenhöhe
+const dailyPanelHeight = 0.6
+
+// Teildiagramme des Tagesdiagramms
+const (
+	dailyPanelTemperature = "temperature"
+	dailyPanelRain        = "rain"
+	dailyPanelRadiation   = "radiation"
+	dailyPanelWind        = "wind"
+)
+
+// dailyPanelNames sind die Teildiagramme in der Reihenfolge des kombinierten Tagesdiagramms
+var dailyPanelNames = []string{dailyPanelTemperature, dailyPanelRain, dailyPanelRadiation, dailyPanelWind}
+
+// dailySeries sind die Datenreihen des kombinierten Tagesdiagramms; leere Reihen entfallen als Teildiagramm
+type dailySeries struct {
+	temperature []chartPoint // °C
+	rain        [24]float64  // mm je Stunde
+	rainValid   bool         // Niederschlagswerte vorhanden
+	radiation   []chartPoint // W/m², leer ohne Strahlungssensor
+	wind, gust  []chartPoint // km/h
+}
+
+// getDailySeries liest die Datenreihen des Tages day für das kombinierte Tagesdiagramm
+func getDailySeries(db *sql.DB, loc *time.Location, day time.Time) (dailySeries, error) {
+	var s dailySeries
+	start, end := day.Unix(), day.AddDate(0, 0, 1).Unix()
+	var err error
+	if s.temperature, err = getTemperatureSeries(db, start, end); err != nil {
+		return s, err
+	}
+	if s.rain, err = getHourlyRain(db, loc, start, end); err != nil {
+		return s, err
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(rain) FROM archive WHERE dateTime >= ? AND dateTime < ?;`, start, end).Scan(&count); err != nil {
+		return s, err
+	}
+	s.rainValid = count > 0
+	if column := optionalColumn(db, "radiation"); column != "" {
+		if s.radiation, err = getArchiveSeries(db, column, start, end); err != nil {
+			return s, err
+		}
+	}
+	if s.wind, err = getArchiveSeries(db, "windSpeed", start, end); err != nil {
+		return s, err
+	}
+	if s.gust, err = getArchiveSeries(db, "windGust", start, end); err != nil {
+		return s, err
+	}
+	return s, nil
+}
+
+// renderDailyChart zeichnet das kombinierte Tagesdiagramm als PNG: übereinander Temperatur, Niederschlag je
+// Stunde, Sonnenstrahlung und Wind mit gemeinsamer Zeitachse, ähnlich den Tagesdiagrammen von weewx.
+// Teildiagramme ohne Daten entfallen; Rekorde und Serien aus dem Post (annotations) sind im jeweiligen
+// Teildiagramm beschriftet.
+func renderDailyChart(config Config, theme chartTheme, day time.Time, s dailySeries, annotations []chartAnnotation) (*chartDrawing, error) {
+	var panels []func(c *chartCanvas) error
+	for _, name := range dailyPanelNames {
+		if render := dailyPanel(config, day, s, annotations, name); render != nil {
+			panels = append(panels, render)
+		}
+	}
+	if len(panels) == 0 {
+		return nil, fmt.Errorf("keine Messwerte")
+	}
+
+	// über den Teildiagrammen eine Zeile für den Titel des Gesamtbildes
+	scale := theme.fontScale
+	header := (glyphHeight + 6) * scale
+	height := int(float64(theme.height) * dailyPanelHeight)
+	d := newChartDrawing(theme, header+len(panels)*height)
+	d.text(2*scale, 3*scale, tr(config, "Wetter am %s", formatDate(config, day)), theme.text, scale)
+	for i, render := range panels {
+		bounds := image.Rect(0, header+i*height, theme.width, header+(i+1)*height)
+		if err := render(newChartPanel(d, theme, bounds)); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// dailyPanel liefert die Zeichenfunktion des Teildiagramms name; nil, wenn es dafür keine Messwerte gibt
+func dailyPanel(config Config, day time.Time, s dailySeries, annotations []chartAnnotation, name string) func(c *chartCanvas) error {
+	switch {
+	case name == dailyPanelTemperature && len(s.temperature) > 0:
+		return func(c *chartCanvas) error {
+			return drawTemperaturePanel(c, config, day, s.temperature, tr(config, "Temperatur"), annotations)
+		}
+	case name == dailyPanelRain && s.rainValid:
+		return func(c *chartCanvas) error {
+			return drawHourlyRainPanel(c, config, day, s.rain, annotationLabel(annotations, annotateRain))
+		}
+	case name == dailyPanelRadiation && len(s.radiation) > 0:
+		return func(c *chartCanvas) error {
+			return drawRadiationPanel(c, config, day, s.radiation, annotationLabel(annotations, annotateSun))
+		}
+	case name == dailyPanelWind && len(s.wind) > 0:
+		return func(c *chartCanvas) error { return drawWindPanel(c, config, day, s.wind, s.gust) }
+	}
+	return nil
+}
+
+// drawHourlyRainPanel zeichnet den Niederschlag je Stunde als Balken; note beschriftet die Tagessumme als Rekord
+// oder Serie
+func drawHourlyRainPanel(c *chartCanvas, config Config, day time.Time, hourly [24]float64, note string) error {
+	unit := unitOf(config, quantityRain)
+	hi := unit.convert(1)
+	for _, r := range hourly {
+		hi = math.Max(hi, unit.convert(r))
+	}
+	_, yMax, step := niceRange(0, hi, 4)
+	c.setRange(float64(day.Unix()), float64(day.AddDate(0, 0, 1).Unix()), 0, yMax)
+	c.title(tr(config, "Niederschlag"), unit.symbol)
+	c.yAxis(step, axisLabel(step))
+	c.xAxis(hourTicks(day))
+	for h, r := range hourly {
+		if r > 0 {
+			from := time.Date(day.Year(), day.Month(), day.Day(), h, 0, 0, 0, day.Location())
+			c.bar(float64(from.Unix()), float64(from.Add(time.Hour).Unix()), unit.convert(r), c.theme.bar)
+		}
+	}
+	if note != "" {
+		sum := 0.0
+		for _, r := range hourly {
+			sum += r
+		}
+		c.note(unit.format(sum, 1)+" "+note, c.theme.bar)
+	}
+	return nil
+}
+
+// drawRadiationPanel zeichnet die Globalstrahlung (W/m²) mit markiertem Höchstwert; note beschriftet die
+// Sonnenscheindauer als Rekord
+func drawRadiationPanel(c *chartCanvas, config Config, day time.Time, series []chartPoint, note string) error {
+	peak := seriesMax(series)
+	if peak < 0 {
+		return fmt.Errorf("keine Strahlungswerte")
+	}
+	_, yMax, step := niceRange(0, math.Max(series[peak].y, 100), 4)
+	c.setRange(float64(day.Unix()), float64(day.AddDate(0, 0, 1).Unix()), 0, yMax)
+	c.title(tr(config, "Sonnenstrahlung"), "W/m²")
+	c.yAxis(step, axisLabel(step))
+	c.xAxis(hourTicks(day))
+	c.polyline(series, c.theme.solar)
+	if series[peak].y > 0 {
+		c.marker(series[peak].x, series[peak].y, fmt.Sprintf("%.0f", series[peak].y), true, c.theme.solar)
+	}
+	if note != "" {
+		c.note(tr(config, "Sonnenschein")+" "+note, c.theme.solar)
+	}
+	return nil
+}
+
+// drawWindPanel zeichnet mittlere Windgeschwindigkeit und Böen mit markierter stärkster Böe
+func drawWindPanel(c *chartCanvas, config Config, day time.Time, wind, gust []chartPoint) error {
+	unit := unitOf(config, quantityWind)
+	convert := func(series []chartPoint) []chartPoint {
+		points := make([]chartPoint, len(series))
+		for i, p := range series {
+			points[i] = chartPoint{x: p.x, y: unit.convert(p.y)}
+		}
+		return points
+	}
+	peak := seriesMax(gust)
+	hi := unit.convert(10)
+	if peak >= 0 {
+		hi = math.Max(hi, unit.convert(gust[peak].y))
+	}
+	if i := seriesMax(wind); i >= 0 {
+		hi = math.Max(hi, unit.convert(wind[i].y))
+	}
+	_, yMax, step := niceRange(0, hi, 4)
+	c.setRange(float64(day.Unix()), float64(day.AddDate(0, 0, 1).Unix()), 0, yMax)
+	c.title(tr(config, "Wind und Böen"), unit.symbol)
+	c.yAxis(step, axisLabel(step))
+	c.xAxis(hourTicks(day))
+	c.polyline(convert(gust), c.theme.gust)
+	c.polyline(convert(wind), c.theme.wind)
+	if peak >= 0 && gust[peak].y > 0 {
+		c.marker(gust[peak].x, unit.convert(gust[peak].y), unit.number(gust[peak].y, 0), true, c.theme.high)
+	}
+	return nil
+}
+
+// seriesMax liefert den Index des größten Werts einer Datenreihe, -1 ohne Werte
+func seriesMax(series []chartPoint) int {
+	best := -1
+	for i, p := range series {
+		if !math.IsNaN(p.y) && (best < 0 || p.y > series[best].y) {
+			best = i
+		}
+	}
+	return best
+}
+
+// altTextDailyChart beschreibt das kombinierte Tagesdiagramm aus den Beschreibungen der Teildiagramme
+func altTextDailyChart(config Config, loc *time.Location, day time.Time, s dailySeries, hourlyTemperature [24]float64, annotations []chartAnnotation) string {
+	var parts []string
+	for _, name := range dailyPanelNames {
+		if text := altTextDailyPanel(config, loc, s, hourlyTemperature, annotations, name); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return tr(config, "Wetter am %s", formatDate(config, day)) + ": " + strings.Join(parts, "; ")
+}
+
+// altTextDailyPanel beschreibt das Teildiagramm name mit den Rekorden und Serien darin; leer ohne Messwerte
+func altTextDailyPanel(config Config, loc *time.Location, s dailySeries, hourlyTemperature [24]float64, annotations []chartAnnotation, name string) string {
+	switch name {
+	case dailyPanelTemperature:
+		if text := altTextTemperature(config, hourlyTemperature); text != "" {
+			return withAnnotations(config, text, annotations, annotateTMax, annotateTMin)
+		}
+	case dailyPanelRain:
+		if s.rainValid {
+			return withAnnotations(config, altTextRain(config, s.rain), annotations, annotateRain)
+		}
+	case dailyPanelRadiation:
+		if peak := seriesMax(s.radiation); peak >= 0 {
+			text := tr(config, "Sonnenstrahlung bis %.0f W/m² um %d Uhr",
+				s.radiation[peak].y, time.Unix(int64(s.radiation[peak].x), 0).In(loc).Hour())
+			return withAnnotations(config, text, annotations, annotateSun)
+		}
+	case dailyPanelWind:
+		if peak := seriesMax(s.gust); peak >= 0 {
+			unit := unitOf(config, quantityWind)
+			return tr(config, "Böen bis %s um %d Uhr",
+				unit.format(s.gust[peak].y, 0), time.Unix(int64(s.gust[peak].x), 0).In(loc).Hour())
+		}
+	}
+	return ""
+}
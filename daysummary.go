@@ -0,0 +1,99 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// DaySummaryRule ist eine Regel für den Satz, der den Tag charakterisiert ("Ein sonniger, trockener
+// Spätsommertag."). Sind Bedingung, weitere Bedingungen und Zeitraum erfüllt, trägt die Regel ihr Wort bei:
+// ein Adjektiv oder mit noun das Substantiv. Wie bei den Hinweisregeln gilt von mehreren zutreffenden Regeln
+// derselben Gruppe nur die letzte; die Adjektive erscheinen in der Reihenfolge ihrer Gruppen.
+type DaySummaryRule struct {
+	AdvisoryCondition                     // ohne metric immer erfüllt
+	And               []AdvisoryCondition `json:"and,omitempty"`
+	Season            []DateRange         `json:"season,omitempty"` // nur in diesen Zeiträumen (leer = ganzjährig)
+	Group             string              `json:"group,omitempty"`
+	Word              string              `json:"word"`           // Adjektiv mit Endung nach "Ein", z.B. "sonniger", bzw. Substantiv
+	Noun              bool                `json:"noun,omitempty"` // Substantiv statt Adjektiv; ohne passendes Substantiv "Tag"
+}
+
+// defaultDaySummaryRules sind die voreingestellten Regeln: Temperatur, Sonne, Niederschlag und Wind als Adjektive,
+// die Jahreszeit als Substantiv
+func defaultDaySummaryRules() []DaySummaryRule {
+	cond := func(metric, op string, value float64) AdvisoryCondition {
+		return AdvisoryCondition{Metric: metric, Op: op, Value: value}
+	}
+	season := func(from, to string) []DateRange { return []DateRange{{From: from, To: to}} }
+	return []DaySummaryRule{
+		{AdvisoryCondition: cond("t_min", "<", 0), Group: "temperatur", Word: "frostiger"},
+		{AdvisoryCondition: cond("t_max", "<", 0), Group: "temperatur", Word: "eisiger"},
+		{AdvisoryCondition: cond("t_max", ">=", 25), Group: "temperatur", Word: "warmer"},
+		{AdvisoryCondition: cond("t_max", ">=", 30), Group: "temperatur", Word: "heißer"},
+		{AdvisoryCondition: cond("sun_minutes", "<", 60), Group: "sonne", Word: "trüber"},
+		{AdvisoryCondition: cond("sun_minutes", ">=", 480), Group: "sonne", Word: "sonniger"},
+		{AdvisoryCondition: cond("rain_sum", "<=", 0), Group: "regen", Word: "trockener"},
+		{AdvisoryCondition: cond("rain_sum", ">=", 2), Group: "regen", Word: "regnerischer"},
+		{AdvisoryCondition: cond("rain_sum", ">=", 15), Group: "regen", Word: "nasser"},
+		{AdvisoryCondition: cond("gust_max", ">=", 50), Group: "wind", Word: "windiger"},
+		{AdvisoryCondition: cond("gust_max", ">=", 75), Group: "wind", Word: "stürmischer"},
+		{Season: season("12-01", "02-29"), Group: "jahreszeit", Noun: true, Word: "Wintertag"},
+		{Season: season("03-01", "05-31"), Group: "jahreszeit", Noun: true, Word: "Frühlingstag"},
+		{Season: season("06-01", "06-30"), Group: "jahreszeit", Noun: true, Word: "Frühsommertag"},
+		{Season: season("07-01", "08-15"), Group: "jahreszeit", Noun: true, Word: "Hochsommertag"},
+		{Season: season("08-16", "09-22"), Group: "jahreszeit", Noun: true, Word: "Spätsommertag"},
+		{Season: season("09-23", "11-30"), Group: "jahreszeit", Noun: true, Word: "Herbsttag"},
+	}
+}
+
+// matches prüft Zeitraum und Bedingungen einer Regel für den Tag day
+func (r DaySummaryRule) matches(s dayStats, day time.Time) bool {
+	if len(r.Season) > 0 && !inSeasons(r.Season, day) {
+		return false
+	}
+	conditions := r.And
+	if r.Metric != "" {
+		conditions = append([]AdvisoryCondition{r.AdvisoryCondition}, conditions...)
+	}
+	for _, c := range conditions {
+		if _, ok := c.check(s, nil); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// formatDaySummary erzeugt den Satz, der den Tag charakterisiert, z.B. "Ein sonniger, trockener Spätsommertag.".
+// Ohne zutreffendes Adjektiv bleibt er leer.
+func formatDaySummary(config Config, s dayStats, day time.Time) string {
+	var adjectives []string
+	groupIndex := make(map[string]int)
+	noun := "Tag"
+	for _, r := range config.DaySummaryRules {
+		if !r.matches(s, day) {
+			continue
+		}
+		if r.Noun {
+			noun = r.Word
+			continue
+		}
+		if i, seen := groupIndex[r.Group]; seen && r.Group != "" {
+			adjectives[i] = r.Word
+			continue
+		}
+		groupIndex[r.Group] = len(adjectives)
+		adjectives = append(adjectives, r.Word)
+	}
+	if len(adjectives) == 0 {
+		return ""
+	}
+	for i, a := range adjectives {
+		adjectives[i] = tr(config, a)
+	}
+	phrase := strings.Join(adjectives, ", ") + " " + tr(config, noun)
+	article := tr(config, "Ein")
+	if config.Language == languageEnglish && strings.ContainsRune("aeiouAEIOU", []rune(phrase)[0]) {
+		article = "An"
+	}
+	return article + " " + phrase + "."
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// deliveryError ist ein endgültiger Zustellfehler mit dem Verlauf aller Fehlversuche
+type deliveryError struct {
+	history []string
+}
+
+func (e *deliveryError) Error() string {
+	return e.history[len(e.history)-1]
+}
+
+// attemptLog sammelt die Fehlversuche einer Zustellung mit Zeitstempel
+type attemptLog []string
+
+func (l *attemptLog) add(err error) {
+	*l = append(*l, time.Now().Format("2006-01-02 15:04:05")+" "+err.Error())
+}
+
+// failed liefert den endgültigen Fehler mit allen bisherigen Fehlversuchen und err als letztem Eintrag
+func (l *attemptLog) failed(err error) error {
+	l.add(err)
+	return &deliveryError{history: *l}
+}
+
+// errorHistory liefert den Fehlerverlauf einer Zustellung, bei einfachen Fehlern nur den Fehler selbst
+func errorHistory(err error) []string {
+	if d, ok := err.(*deliveryError); ok {
+		return d.history
+	}
+	return []string{time.Now().Format("2006-01-02 15:04:05") + " " + err.Error()}
+}
+
+// deadLetter ist ein nicht zustellbarer Post mit vollständigem Inhalt und Fehlerverlauf
+type deadLetter struct {
+	Day            string            `json:"day"`
+	Platform       string            `json:"platform"`
+	Created        time.Time         `json:"created"`
+	Title          string            `json:"title,omitempty"`
+	Text           string            `json:"text"`
+	ContentWarning string            `json:"content_warning,omitempty"` // Inhaltswarnung (Mastodon)
+	Visibility     string            `json:"visibility,omitempty"`      // Sichtbarkeit (Mastodon)
+	Payload        json.RawMessage   `json:"payload,omitempty"`         // Webhook-Payload
+	Media          []deadLetterMedia `json:"media,omitempty"`           // Kopien der Bilder (Lemmy, Mastodon)
+	Errors         []string          `json:"errors"`
+}
+
+// deadLetterMedia ist ein Bild eines abgelegten Posts; die Datei liegt in deadLetterMediaDir
+type deadLetterMedia struct {
+	Path        string `json:"path"`
+	ContentType string `json:"content_type"`
+	Description string `json:"description,omitempty"`
+	Focus       string `json:"focus,omitempty"`
+	Link        string `json:"link,omitempty"`
+}
+
+// deadLetterMediaDir ist das Verzeichnis für die Bilder abgelegter Posts neben der Dead-Letter-Datei
+func deadLetterMediaDir(config Config) string {
+	return config.DeadLetterFile + ".media"
+}
+
+// keepDeadLetterMedia kopiert die temporären Bilder eines gescheiterten Posts nach deadLetterMediaDir, weil sie nach
+// dem Lauf gelöscht werden. Ein Bild, das nicht kopiert werden kann, entfällt mit Warnung.
+func keepDeadLetterMedia(config Config, media []mediaAttachment) []deadLetterMedia {
+	if len(media) == 0 {
+		return nil
+	}
+	dir := deadLetterMediaDir(config)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		log.Printf("Warnung: Bilder werden nicht abgelegt: %v", err)
+		return nil
+	}
+	var kept []deadLetterMedia
+	for _, m := range media {
+		data, err := os.ReadFile(m.Path)
+		if err == nil {
+			var f *os.File
+			if f, err = os.CreateTemp(dir, "*"+filepath.Ext(m.Path)); err == nil {
+				_, err = f.Write(data)
+				if cerr := f.Close(); err == nil {
+					err = cerr
+				}
+				if err != nil {
+					os.Remove(f.Name())
+				} else {
+					kept = append(kept, deadLetterMedia{Path: f.Name(), ContentType: m.ContentType, Description: m.Description, Focus: m.Focus, Link: m.Link})
+				}
+			}
+		}
+		if err != nil {
+			log.Printf("Warnung: Bild %s wird nicht abgelegt und fehlt beim erneuten Senden: %v", m.Path, err)
+		}
+	}
+	return kept
+}
+
+// attachments liefert die abgelegten Bilder als Anhänge; fehlende Dateien entfallen mit Warnung
+func (l deadLetter) attachments() []mediaAttachment {
+	var media []mediaAttachment
+	for _, m := range l.Media {
+		if _, err := os.Stat(m.Path); err != nil {
+			log.Printf("Warnung: Bild %s für %s (%s) fehlt – Post wird ohne dieses Bild gesendet", m.Path, l.Day, l.Platform)
+			continue
+		}
+		media = append(media, mediaAttachment{Path: m.Path, ContentType: m.ContentType, Description: m.Description, Focus: m.Focus, Link: m.Link})
+	}
+	return media
+}
+
+// removeDeadLetterMedia löscht die abgelegten Bilder eines Eintrags
+func removeDeadLetterMedia(l deadLetter) {
+	for _, m := range l.Media {
+		if err := os.Remove(m.Path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Warnung: Bilddatei %s konnte nicht gelöscht werden: %v", m.Path, err)
+		}
+	}
+}
+
+// readDeadLetters liest die Dead-Letter-Datei (JSON Lines); eine fehlende Datei ist leer
+func readDeadLetters(path string) ([]deadLetter, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var letters []deadLetter
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var l deadLetter
+		if err := json.Unmarshal([]byte(line), &l); err != nil {
+			return nil, fmt.Errorf("Dead-Letter-Datei %s: %v", path, err)
+		}
+		letters = append(letters, l)
+	}
+	return letters, scanner.Err()
+}
+
+// writeDeadLetters schreibt die Dead-Letter-Datei neu; ohne Einträge wird sie entfernt
+func writeDeadLetters(path string, letters []deadLetter) error {
+	if len(letters) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	for _, l := range letters {
+		if err := enc.Encode(l); err != nil {
+			return err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// saveDeadLetter legt einen endgültig gescheiterten Post in der Dead-Letter-Datei ab.
+// Ein älterer Eintrag für denselben Tag und dieselbe Plattform wird ersetzt, sein Fehlerverlauf bleibt erhalten.
+// media sind die Bilder des Posts; sie werden für -resend kopiert.
+func saveDeadLetter(config Config, letter deadLetter, media []mediaAttachment, err error) {
+	if config.DeadLetterFile == "" {
+		return
+	}
+	letter.Created = time.Now()
+	letter.Errors = errorHistory(err)
+	letter.Media = keepDeadLetterMedia(config, media)
+
+	letters, readErr := readDeadLetters(config.DeadLetterFile)
+	if readErr != nil {
+		log.Printf("Warnung: %v – Post wird nicht abgelegt", readErr)
+		removeDeadLetterMedia(letter)
+		return
+	}
+	var replaced []deadLetter
+	for i, l := range letters {
+		if l.Day == letter.Day && l.Platform == letter.Platform {
+			letter.Errors = append(l.Errors, letter.Errors...)
+			letters[i] = letter
+			replaced = append(replaced, l)
+		}
+	}
+	if len(replaced) == 0 {
+		letters = append(letters, letter)
+	}
+	if err := writeDeadLetters(config.DeadLetterFile, letters); err != nil {
+		log.Printf("Warnung: Dead-Letter-Datei %s konnte nicht geschrieben werden: %v", config.DeadLetterFile, err)
+		removeDeadLetterMedia(letter)
+		return
+	}
+	for _, l := range replaced {
+		removeDeadLetterMedia(l)
+	}
+	log.Printf("Post für %s (%s) in %s abgelegt, erneut senden mit -resend", letter.Day, letter.Platform, config.DeadLetterFile)
+}
+
+// resendLetter stellt einen abgelegten Post einmalig zu, ohne Wiederholungen; abgelegte Bilder werden erneut hochgeladen
+func resendLetter(store *sql.DB, config Config, l deadLetter) error {
+	switch l.Platform {
+	case platformLemmy:
+		return lemmyPostOnce(store, config, l.Title, l.Text, l.attachments())
+	case platformMastodon, platformMastodonAlert:
+		if config.MastodonServer == "" || config.MastodonToken == "" {
+			return fmt.Errorf("Mastodon ist nicht konfiguriert")
+		}
+		if err := spendAPICall(store, config, platformMastodon); err != nil {
+			return err
+		}
+		visibility := l.Visibility
+		if visibility == "" {
+			visibility = config.MastodonVisibility
+		}
+		mediaIDs := mastodonUploadAll(store, config, l.attachments())
+		return mastodonCreatePost(config.MastodonServer, config.MastodonToken, l.Text, l.ContentWarning, visibility, mastodonLanguage(config), mediaIDs)
+	case platformWebhook:
+		if config.WebhookURL == "" {
+			return fmt.Errorf("webhook_url ist nicht konfiguriert")
+		}
+		var payload webhookPayload
+		if err := json.Unmarshal(l.Payload, &payload); err != nil {
+			return fmt.Errorf("Webhook-Payload: %v", err)
+		}
+		if err := spendAPICall(store, config, platformWebhook); err != nil {
+			return err
+		}
+		return webhookPost(config.WebhookURL, payload)
+	}
+	return fmt.Errorf("unbekannte Plattform %q", l.Platform)
+}
+
+// resendDeadLetters versucht alle abgelegten Posts erneut zuzustellen. Erfolgreich zugestellte (oder inzwischen
+// anderweitig veröffentlichte) Posts werden aus der Datei entfernt, die übrigen behalten ihren Fehlerverlauf.
+// Im Test-Modus werden die Einträge nur angezeigt.
+func resendDeadLetters(config Config, testMode bool) error {
+	if config.DeadLetterFile == "" {
+		return fmt.Errorf("dead_letter_file ist nicht konfiguriert")
+	}
+	letters, err := readDeadLetters(config.DeadLetterFile)
+	if err != nil {
+		return err
+	}
+	if len(letters) == 0 {
+		log.Printf("Keine abgelegten Posts in %s", config.DeadLetterFile)
+		return nil
+	}
+
+	if testMode {
+		for _, l := range letters {
+			fmt.Printf("\n=== TEST-MODUS: Abgelegter Post %s (%s), %d Fehlversuche ===\n", l.Day, l.Platform, len(l.Errors))
+			if l.Title != "" {
+				fmt.Printf("Titel: %s\n", l.Title)
+			}
+			fmt.Printf("%s\n", l.Text)
+			for _, m := range l.Media {
+				fmt.Printf("Bild: %s (%s)\n", m.Path, m.Description)
+			}
+			// von Hand bearbeitete oder ältere Einträge haben ggf. keinen Fehlerverlauf
+			if len(l.Errors) > 0 {
+				fmt.Printf("Letzter Fehler: %s\n", l.Errors[len(l.Errors)-1])
+			}
+			fmt.Printf("=== ENDE ===\n")
+		}
+		return nil
+	}
+
+	store, err := openStore(config.StorePath)
+	if err != nil {
+		log.Printf("Warnung: %v – Veröffentlichungsstatus wird nicht aktualisiert", err)
+	} else {
+		defer store.Close()
+	}
+
+	var remaining []deadLetter
+	for _, l := range letters {
+		if isPublished(store, l.Day, l.Platform) {
+			log.Printf("Post für %s (%s) bereits veröffentlicht, wird entfernt", l.Day, l.Platform)
+			removeDeadLetterMedia(l)
+			continue
+		}
+		if err := resendLetter(store, config, l); err != nil {
+			log.Printf("Post für %s (%s) weiterhin nicht zustellbar: %v", l.Day, l.Platform, err)
+			l.Errors = append(l.Errors, errorHistory(err)...)
+			remaining = append(remaining, l)
+			continue
+		}
+		setPublishStatus(store, l.Day, l.Platform, nil)
+		removeDeadLetterMedia(l)
+		log.Printf("Post für %s (%s) erneut gesendet", l.Day, l.Platform)
+	}
+	log.Printf("%d von %d abgelegten Posts gesendet", len(letters)-len(remaining), len(letters))
+	return writeDeadLetters(config.DeadLetterFile, remaining)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveDeadLetterKeepsMedia(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultConfig()
+	config.DeadLetterFile = filepath.Join(dir, "deadletter.jsonl")
+
+	img := filepath.Join(dir, "chart.png")
+	if err := os.WriteFile(img, []byte("png"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	media := []mediaAttachment{{Path: img, ContentType: "image/png", Description: "Temperaturverlauf"}}
+	letter := deadLetter{Day: "2024-07-01", Platform: platformMastodon, Text: "Wetter"}
+
+	saveDeadLetter(config, letter, media, errors.New("HTTP 502"))
+	os.Remove(img) // temporäre Bilder werden nach dem Lauf gelöscht
+	letters, err := readDeadLetters(config.DeadLetterFile)
+	if err != nil || len(letters) != 1 || len(letters[0].Media) != 1 {
+		t.Fatalf("got %+v, %v", letters, err)
+	}
+	first := letters[0].Media[0]
+	if got := letters[0].attachments(); len(got) != 1 || got[0].Description != "Temperaturverlauf" {
+		t.Fatalf("attachments: got %+v", got)
+	}
+
+	// ein neuer Fehlschlag ersetzt den Eintrag samt Bildern
+	if err := os.WriteFile(img, []byte("png"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	saveDeadLetter(config, letter, media, errors.New("HTTP 503"))
+	letters, err = readDeadLetters(config.DeadLetterFile)
+	if err != nil || len(letters) != 1 || len(letters[0].Errors) != 2 || len(letters[0].Media) != 1 {
+		t.Fatalf("got %+v, %v", letters, err)
+	}
+	if _, err := os.Stat(first.Path); !os.IsNotExist(err) {
+		t.Errorf("Bild des ersetzten Eintrags nicht gelöscht: %v", err)
+	}
+
+	removeDeadLetterMedia(letters[0])
+	if got := letters[0].attachments(); len(got) != 0 {
+		t.Errorf("fehlende Bilder: got %+v", got)
+	}
+}
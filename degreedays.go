@@ -0,0 +1,140 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// parseMonthDay wandelt eine Angabe "MM-DD" in Monat und Tag um
+func parseMonthDay(s string) (time.Month, int, error) {
+	t, err := time.Parse("01-02", s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("ungültiges Datum %q (erwartet MM-DD): %v", s, err)
+	}
+	return t.Month(), t.Day(), nil
+}
+
+// seasonStart liefert den Beginn der Saison (MM-DD bis MM-DD), in der day liegt.
+// ok ist false, wenn day außerhalb der Saison liegt. Saisons dürfen über den Jahreswechsel gehen.
+func seasonStart(day time.Time, from, to string) (time.Time, bool) {
+	fromMonth, fromDay, err := parseMonthDay(from)
+	if err != nil {
+		return time.Time{}, false
+	}
+	toMonth, toDay, err := parseMonthDay(to)
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc := day.Location()
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	start := time.Date(day.Year(), fromMonth, fromDay, 0, 0, 0, 0, loc)
+	if day.Before(start) {
+		start = start.AddDate(-1, 0, 0)
+	}
+	end := time.Date(start.Year(), toMonth, toDay, 0, 0, 0, 0, loc)
+	if end.Before(start) {
+		end = end.AddDate(1, 0, 0)
+	}
+	if day.After(end) {
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// growingDegreeDays berechnet die Wachstumsgradtage eines Tages aus Höchst- und Tiefstwert
+func growingDegreeDays(tMax, tMin, base float64) float64 {
+	return math.Max(0, (tMax+tMin)/2-base)
+}
+
+// sumDegreeDays summiert Gradtage über die Tageswerte der Tage [from, to] (inklusive).
+// f berechnet die Gradtage eines Tages aus Höchst- und Tiefstwert.
+func sumDegreeDays(db *sql.DB, config Config, from, to time.Time, f func(tMax, tMin float64) float64) (float64, error) {
+	temps, err := dailyTemperatures(db, config, from, to)
+	if err != nil {
+		return 0, err
+	}
+	var sum float64
+	for _, t := range temps {
+		sum += f(t.tMax, t.tMin)
+	}
+	return sum, nil
+}
+
+// formatGrowingDegreeDays erzeugt die Zeile mit Wachstumsgradtagen des Tages und seit Saisonbeginn.
+// Außerhalb der Wachstumssaison oder wenn deaktiviert bleibt die Zeile leer.
+func formatGrowingDegreeDays(db *sql.DB, config Config, day time.Time, s dayStats) string {
+	if !config.GDDEnabled || math.IsNaN(s.tMax) || math.IsNaN(s.tMin) {
+		return ""
+	}
+	start, ok := seasonStart(day, config.GDDSeasonStart, config.GDDSeasonEnd)
+	if !ok {
+		return ""
+	}
+	base := config.GDDBaseTemp
+	unit := unitOf(config, quantityTemperature)
+	daily := growingDegreeDays(s.tMax, s.tMin, base)
+	season, err := sumDegreeDays(db, config, start, day, func(tMax, tMin float64) float64 {
+		return growingDegreeDays(tMax, tMin, base)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Wachstumsgradtage seit Saisonbeginn nicht verfügbar: %v\n", err)
+		return tr(config, "Wachstumsgradtage (Basis %s): %s", unit.format(base, 0), unit.deltaNumber(daily, 1))
+	}
+	return tr(config, "Wachstumsgradtage (Basis %s): %s (seit %s: %s)", unit.format(base, 0), unit.deltaNumber(daily, 1),
+		formatDayMonth(config, start), unit.deltaNumber(season, 0))
+}
+
+// heatingDegreeDays berechnet die Gradtagzahl (z.B. G20/15): Liegt das Tagesmittel unter der
+// Heizgrenze, zählt die Differenz zur Innentemperatur, sonst 0
+func heatingDegreeDays(tMax, tMin, indoor, heatingLimit float64) float64 {
+	mean := (tMax + tMin) / 2
+	if mean >= heatingLimit {
+		return 0
+	}
+	return indoor - mean
+}
+
+// baseHeatingDegreeDays berechnet Heizgradtage mit einfacher Basistemperatur (international übliche Definition)
+func baseHeatingDegreeDays(tMax, tMin, base float64) float64 {
+	return math.Max(0, base-(tMax+tMin)/2)
+}
+
+// coolingDegreeDays berechnet die Kühlgradtage eines Tages
+func coolingDegreeDays(tMax, tMin, base float64) float64 {
+	return math.Max(0, (tMax+tMin)/2-base)
+}
+
+// formatEnergyDegreeDays erzeugt die Zeilen mit Heiz- und Kühlgradtagen des Tages und seit Monatsbeginn.
+// Beide Angaben erscheinen nur in ihrer jeweils konfigurierten Saison.
+func formatEnergyDegreeDays(db *sql.DB, config Config, day time.Time, s dayStats) string {
+	if math.IsNaN(s.tMax) || math.IsNaN(s.tMin) {
+		return ""
+	}
+	monthStart := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	var lines []string
+
+	if _, ok := seasonStart(day, config.HDDSeasonStart, config.HDDSeasonEnd); config.HDDEnabled && ok {
+		indoor, limit := config.HDDIndoorTemp, config.HDDHeatingLimit
+		f := func(tMax, tMin float64) float64 { return heatingDegreeDays(tMax, tMin, indoor, limit) }
+		line := tr(config, "Heizgradtage (G%.0f/%.0f): %.1f", indoor, limit, f(s.tMax, s.tMin))
+		if month, err := sumDegreeDays(db, config, monthStart, day, f); err == nil {
+			line += tr(config, " (Monat bisher: %.1f)", month)
+		}
+		lines = append(lines, line)
+	}
+	if _, ok := seasonStart(day, config.CDDSeasonStart, config.CDDSeasonEnd); config.CDDEnabled && ok {
+		base := config.CDDBaseTemp
+		f := func(tMax, tMin float64) float64 { return coolingDegreeDays(tMax, tMin, base) }
+		unit := unitOf(config, quantityTemperature)
+		line := tr(config, "Kühlgradtage (Basis %s): %s", unit.format(base, 0), unit.deltaNumber(f(s.tMax, s.tMin), 1))
+		if month, err := sumDegreeDays(db, config, monthStart, day, f); err == nil {
+			line += tr(config, " (Monat bisher: %s)", unit.deltaNumber(month, 1))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSeasonStart(t *testing.T) {
+	loc := time.UTC
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, loc)
+	}
+	tests := []struct {
+		name     string
+		day      time.Time
+		from, to string
+		want     time.Time
+		ok       bool
+	}{
+		{"mitten in der Saison", date(2024, 6, 15), "04-01", "10-31", date(2024, 4, 1), true},
+		{"erster Tag", date(2024, 4, 1), "04-01", "10-31", date(2024, 4, 1), true},
+		{"letzter Tag", date(2024, 10, 31), "04-01", "10-31", date(2024, 4, 1), true},
+		{"vor der Saison", date(2024, 3, 31), "04-01", "10-31", time.Time{}, false},
+		{"nach der Saison", date(2024, 11, 1), "04-01", "10-31", time.Time{}, false},
+		{"über den Jahreswechsel, Herbst", date(2024, 11, 20), "10-01", "04-30", date(2024, 10, 1), true},
+		{"über den Jahreswechsel, Frühjahr", date(2025, 2, 10), "10-01", "04-30", date(2024, 10, 1), true},
+		{"über den Jahreswechsel, Sommer", date(2025, 7, 1), "10-01", "04-30", time.Time{}, false},
+		{"Uhrzeit wird ignoriert", date(2024, 10, 31).Add(23 * time.Hour), "04-01", "10-31", date(2024, 4, 1), true},
+		{"ungültige Angabe", date(2024, 6, 15), "4/1", "10-31", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := seasonStart(tt.day, tt.from, tt.to)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, %v, want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDegreeDays(t *testing.T) {
+	tests := []struct {
+		name       string
+		f          func(tMax, tMin float64) float64
+		tMax, tMin float64
+		want       float64
+	}{
+		{"GDD über der Basis", func(x, n float64) float64 { return growingDegreeDays(x, n, 10) }, 24, 12, 8},
+		{"GDD unter der Basis", func(x, n float64) float64 { return growingDegreeDays(x, n, 10) }, 12, 2, 0},
+		{"G20/15 unter der Heizgrenze", func(x, n float64) float64 { return heatingDegreeDays(x, n, 20, 15) }, 10, 2, 14},
+		{"G20/15 an der Heizgrenze", func(x, n float64) float64 { return heatingDegreeDays(x, n, 20, 15) }, 20, 10, 0},
+		{"HDD mit Basis", func(x, n float64) float64 { return baseHeatingDegreeDays(x, n, 18) }, 10, 2, 12},
+		{"HDD über der Basis", func(x, n float64) float64 { return baseHeatingDegreeDays(x, n, 18) }, 26, 14, 0},
+		{"CDD über der Basis", func(x, n float64) float64 { return coolingDegreeDays(x, n, 18) }, 32, 20, 8},
+		{"CDD unter der Basis", func(x, n float64) float64 { return coolingDegreeDays(x, n, 18) }, 20, 10, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.f(tt.tMax, tt.tMin); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"time"
+)
+
+// Abgeleitete Größen, die mehrere Sensoren kombinieren (z.B. Blattnässe und Temperatur)
+
+// getLeafWetHours summiert die Archivintervalle, in denen der Blattnässesensor mindestens die
+// Nässeschwelle anzeigt, zu Stunden. NaN ohne Blattnässesensor.
+func getLeafWetHours(db *sql.DB, config Config, start, end int64) (float64, error) {
+	column := optionalColumn(db, config.LeafWetColumn)
+	if column == "" {
+		return math.NaN(), nil
+	}
+	var minutes sql.NullFloat64
+	var count int
+	q := `SELECT SUM(CASE WHEN ` + column + ` >= ? THEN interval ELSE 0 END), COUNT(` + column + `)
+		FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	if err := db.QueryRow(q, config.LeafWetThreshold, start, end).Scan(&minutes, &count); err != nil {
+		return math.NaN(), err
+	}
+	if count == 0 {
+		return math.NaN(), nil
+	}
+	return minutes.Float64 / 60, nil
+}
+
+// isLateFrostRisk prüft auf Spätfrostgefahr: in der Spätfrostsaison ein Tiefstwert bis zur Spätfrostschwelle
+// bei nassen Blättern, die bei diesen Temperaturen bereifen oder gefrieren
+func isLateFrostRisk(s dayStats, config Config, day time.Time) bool {
+	if math.IsNaN(s.tMin) || math.IsNaN(s.leafWetHours) || s.leafWetHours <= 0 {
+		return false
+	}
+	if _, ok := seasonStart(day, config.LateFrostSeasonStart, config.LateFrostSeasonEnd); !ok {
+		return false
+	}
+	return s.tMin <= config.LateFrostTemp
+}
+
+// formatLeafWetness erzeugt die Zeile zur Blattnässe mit Spätfrost-Hinweis im Frühjahr; ohne Sensor leer
+func formatLeafWetness(s dayStats, config Config, day time.Time) string {
+	if math.IsNaN(s.leafWetHours) {
+		return ""
+	}
+	line := tr(config, "🍃 Blattnässe: %.1f h", s.leafWetHours)
+	if isLateFrostRisk(s, config, day) {
+		line += "\n" + tr(config, "❄️ Spätfrostgefahr: Tiefstwert %s bei nassen Blättern – Blüten und Jungpflanzen schützen", unitOf(config, quantityTemperature).format(s.tMin, 1))
+	}
+	return line
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+)
+
+// dewpointFromHumidity berechnet den Taupunkt (°C) nach der Magnus-Formel
+func dewpointFromHumidity(temp, humidity float64) float64 {
+	const b, c = 17.62, 243.12
+	gamma := math.Log(humidity/100) + b*temp/(c+temp)
+	return c * gamma / (b - gamma)
+}
+
+// getDewpointExtremes ermittelt minimalen und maximalen Taupunkt des Zeitraums.
+// Fehlt die dewpoint-Spalte oder ist ein Wert NULL, wird der Taupunkt aus outTemp und outHumidity berechnet.
+func getDewpointExtremes(db *sql.DB, start, end int64) (float64, float64, error) {
+	dewMin, dewMax := math.NaN(), math.NaN()
+
+	dewColumn := "NULL"
+	if archiveHasColumn(db, "dewpoint") {
+		dewColumn = "dewpoint"
+	}
+	q := `SELECT ` + dewColumn + `, outTemp, outHumidity FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	rows, err := db.Query(q, start, end)
+	if err != nil {
+		return dewMin, dewMax, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dew, temp, hum sql.NullFloat64
+		if err := rows.Scan(&dew, &temp, &hum); err != nil {
+			return dewMin, dewMax, err
+		}
+		var v float64
+		switch {
+		case dew.Valid:
+			v = dew.Float64
+		case temp.Valid && hum.Valid && hum.Float64 > 0:
+			v = dewpointFromHumidity(temp.Float64, hum.Float64)
+		default:
+			continue
+		}
+		if math.IsNaN(dewMin) || v < dewMin {
+			dewMin = v
+		}
+		if math.IsNaN(dewMax) || v > dewMax {
+			dewMax = v
+		}
+	}
+	return dewMin, dewMax, rows.Err()
+}
+
+// formatDewpoint erzeugt die Taupunktzeile mit Schwüle-Hinweis, leer wenn keine Daten vorliegen
+func formatDewpoint(s dayStats, config Config) string {
+	if math.IsNaN(s.dewMin) || math.IsNaN(s.dewMax) {
+		return ""
+	}
+	unit := unitOf(config, quantityTemperature)
+	line := tr(config, "Taupunkt: %s bis %s %s", unit.number(s.dewMin, 1), unit.number(s.dewMax, 1), unit.symbol)
+	if s.dewMax >= config.MuggyDewpoint {
+		line += "\n" + tr(config, "Es war schwül (Taupunkt bis %s).", unit.format(s.dewMax, 1))
+	}
+	return line
+}
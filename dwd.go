@@ -0,0 +1,140 @@
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dwdDay sind die Tageswerte einer DWD-Station (KL-Datensatz), NaN für fehlende Werte
+type dwdDay struct {
+	tMax, rainSum float64
+}
+
+// dwdKLURL liefert die Adresse des Archivs mit den aktuellen Tageswerten (KL, recent) einer Station
+func dwdKLURL(config Config) string {
+	return strings.TrimSuffix(config.DWDBaseURL, "/") + "/tageswerte_KL_" + config.DWDStationID + "_akt.zip"
+}
+
+// fetchDWDProduct lädt das ZIP-Archiv der Station und liefert den Inhalt der Datei produkt_klima_tag_*.txt
+func fetchDWDProduct(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		return nil, fmt.Errorf("ZIP-Archiv: %v", err)
+	}
+	for _, f := range archive.File {
+		if !strings.HasPrefix(f.Name, "produkt_klima_tag_") {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	return nil, fmt.Errorf("keine Datei produkt_klima_tag_* im Archiv")
+}
+
+// parseDWDDay sucht in den KL-Tageswerten (Semikolon-getrennt, Kopfzeile mit Spaltennamen) den Tag day.
+// ok ist false, wenn der Tag (noch) nicht enthalten ist.
+func parseDWDDay(product []byte, day time.Time) (dwdDay, bool) {
+	want := day.Format("20060102")
+	scanner := bufio.NewScanner(bytes.NewReader(product))
+	columns := make(map[string]int)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ";")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if len(columns) == 0 {
+			for i, name := range fields {
+				columns[name] = i
+			}
+			continue
+		}
+		if i, ok := columns["MESS_DATUM"]; !ok || i >= len(fields) || fields[i] != want {
+			continue
+		}
+		value := func(name string) float64 {
+			i, ok := columns[name]
+			if !ok || i >= len(fields) {
+				return math.NaN()
+			}
+			v, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil || v == -999 {
+				return math.NaN()
+			}
+			return v
+		}
+		return dwdDay{tMax: value("TXK"), rainSum: value("RSK")}, true
+	}
+	return dwdDay{}, false
+}
+
+// getDWDDay liefert die Tageswerte der DWD-Station für day. Das Archiv wird im Zustandsspeicher (external_cache)
+// zwischengespeichert und nur neu geladen, wenn der Stand älter als external_data_cache_minutes ist oder den Tag
+// noch nicht enthält; ist der DWD nicht erreichbar, wird der letzte Stand verwendet.
+func getDWDDay(store *sql.DB, config Config, day time.Time) (dwdDay, bool) {
+	url := dwdKLURL(config)
+	product, fetched, cached := cachedExternalData(store, url)
+	if cached && time.Since(fetched) < time.Duration(config.ExternalDataCacheMinutes)*time.Minute {
+		if d, ok := parseDWDDay(product, day); ok {
+			return d, true
+		}
+	}
+	fresh, err := fetchDWDProduct(url)
+	if err != nil {
+		log.Printf("Warnung: DWD-Station %s nicht abrufbar: %v", config.DWDStationID, err)
+	} else {
+		product = fresh
+		storeExternalData(store, url, product)
+	}
+	return parseDWDDay(product, day)
+}
+
+// formatDWDComparison erzeugt die Vergleichszeile mit der DWD-Station,
+// z.B. "🏛️ Köln/Bonn (DWD): 24.1 °C / 3.0 mm (hier 23.5 °C / 2.1 mm)"
+func formatDWDComparison(store *sql.DB, config Config, day time.Time, s dayStats) string {
+	if config.DWDStationID == "" {
+		return ""
+	}
+	d, ok := getDWDDay(store, config, day)
+	if !ok || math.IsNaN(d.tMax) {
+		return ""
+	}
+	name := config.DWDStationName
+	if name == "" {
+		name = "Station " + config.DWDStationID
+	}
+	temp, rain := unitOf(config, quantityTemperature), unitOf(config, quantityRain)
+	line := fmt.Sprintf("🏛️ %s (DWD): %s", name, temp.format(d.tMax, 1))
+	here := temp.format(s.tMax, 1)
+	if !math.IsNaN(d.rainSum) {
+		line += " / " + rain.format(d.rainSum, 1)
+		here += " / " + rain.format(s.rainSum, 1)
+	}
+	return line + tr(config, " (hier %s)", here)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// extraterrestrialRadiation berechnet die extraterrestrische Strahlung Ra (MJ/m²/Tag) nach FAO-56
+func extraterrestrialRadiation(latitude float64, dayOfYear int) float64 {
+	const gsc = 0.0820 // Solarkonstante MJ/m²/min
+	phi := latitude * math.Pi / 180
+	j := float64(dayOfYear)
+	dr := 1 + 0.033*math.Cos(2*math.Pi*j/365)
+	delta := 0.409 * math.Sin(2*math.Pi*j/365-1.39)
+	ws := math.Acos(math.Max(-1, math.Min(1, -math.Tan(phi)*math.Tan(delta))))
+	return 24 * 60 / math.Pi * gsc * dr * (ws*math.Sin(phi)*math.Sin(delta) + math.Cos(phi)*math.Cos(delta)*math.Sin(ws))
+}
+
+// hargreavesET berechnet die Referenzverdunstung (mm/Tag) nach Hargreaves aus Höchst- und Tiefstwert
+func hargreavesET(tMax, tMin, latitude float64, dayOfYear int) float64 {
+	ra := extraterrestrialRadiation(latitude, dayOfYear) * 0.408 // in mm Verdunstungsäquivalent
+	return 0.0023 * ra * ((tMax+tMin)/2 + 17.8) * math.Sqrt(math.Max(0, tMax-tMin))
+}
+
+// dailyET liefert die Verdunstung eines Tages in mm. Bevorzugt wird die von weewx gefüllte ET-Spalte,
+// sonst wird nach Hargreaves aus den Tageswerten der Temperatur gerechnet. NaN, wenn beides nicht möglich ist.
+func dailyET(db *sql.DB, config Config, day time.Time) (float64, error) {
+	start, end := day.Unix(), day.AddDate(0, 0, 1).Unix()
+	if archiveHasColumn(db, "ET") {
+		var sum sql.NullFloat64
+		if err := db.QueryRow(`SELECT SUM(ET) FROM archive WHERE dateTime >= ? AND dateTime < ?;`, start, end).Scan(&sum); err != nil {
+			return math.NaN(), err
+		}
+		if sum.Valid {
+			// Korrektur wie beim Regen: Werte in cm
+			return sum.Float64 * 10.0, nil
+		}
+	}
+
+	temps, err := dailyTemperatures(db, config, day, day)
+	if err != nil {
+		return math.NaN(), err
+	}
+	if len(temps) == 0 {
+		return math.NaN(), nil
+	}
+	return hargreavesET(temps[0].tMax, temps[0].tMin, config.Latitude, day.YearDay()), nil
+}
+
+// monthWaterBalance summiert Niederschlag und Verdunstung vom Monatsersten bis einschließlich day (mm)
+func monthWaterBalance(db *sql.DB, loc *time.Location, config Config, day time.Time) (float64, float64, error) {
+	var rain, et float64
+	for d := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc); !d.After(day); d = d.AddDate(0, 0, 1) {
+		if r, ok := rainOnDay(db, loc, config, d); ok {
+			rain += r
+		}
+		e, err := dailyET(db, config, d)
+		if err != nil {
+			return rain, et, err
+		}
+		if !math.IsNaN(e) {
+			et += e
+		}
+	}
+	return rain, et, nil
+}
+
+// formatET erzeugt die Zeile mit Tagesverdunstung und Wasserbilanz (Regen minus ET) des Monats
+func formatET(db *sql.DB, loc *time.Location, config Config, day time.Time, s dayStats) string {
+	if math.IsNaN(s.et) {
+		return ""
+	}
+	unit := unitOf(config, quantityRain)
+	line := tr(config, "Verdunstung (ET): %s", unit.format(s.et, 1))
+	rain, et, err := monthWaterBalance(db, loc, config, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Wasserbilanz des Monats nicht verfügbar: %v\n", err)
+		return line
+	}
+	return line + tr(config, ", Wasserbilanz im Monat: %s (Regen %s − ET %s)", unit.delta(rain-et, 1, true), unit.format(rain, 1), unit.format(et, 1))
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// exporters sind die verfügbaren Exportformate für -export
+var exporters = map[string]func(w io.Writer, db *sql.DB, loc *time.Location, config Config, from, to time.Time) error{
+	"degree-days": exportDegreeDays,
+	"daily-stats": exportDailyStats,
+}
+
+// parseDateRange wertet -from/-to (YYYY-MM-DD) aus. Ohne Angaben gilt der Zeitraum vom Jahresbeginn bis gestern.
+func parseDateRange(loc *time.Location, from, to string) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	start := time.Date(yesterday.Year(), 1, 1, 0, 0, 0, 0, loc)
+	end := yesterday
+	var err error
+	if from != "" {
+		if start, err = time.ParseInLocation("2006-01-02", from, loc); err != nil {
+			return start, end, fmt.Errorf("ungültiges Startdatum %q: %v", from, err)
+		}
+	}
+	if to != "" {
+		if end, err = time.ParseInLocation("2006-01-02", to, loc); err != nil {
+			return start, end, fmt.Errorf("ungültiges Enddatum %q: %v", to, err)
+		}
+	}
+	if end.Before(start) {
+		return start, end, fmt.Errorf("Enddatum %s liegt vor Startdatum %s", end.Format("2006-01-02"), start.Format("2006-01-02"))
+	}
+	return start, end, nil
+}
+
+// runExport schreibt den Export kind für den Zeitraum [from, to] nach out (leer oder "-" = stdout)
+func runExport(dbPath string, config Config, kind, from, to, out string) error {
+	exporter, ok := exporters[kind]
+	if !ok {
+		return fmt.Errorf("unbekanntes Exportformat %q", kind)
+	}
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("timezone: %v", err)
+	}
+	start, end, err := parseDateRange(loc, from, to)
+	if err != nil {
+		return err
+	}
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return fmt.Errorf("open DB: %v", err)
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if out != "" && out != "-" {
+		f, err := os.Create(out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := exporter(w, db, loc, config, start, end); err != nil {
+		return err
+	}
+	if out != "" && out != "-" {
+		log.Printf("Export %s für %s bis %s nach %s geschrieben", kind, start.Format("02.01.2006"), end.Format("02.01.2006"), out)
+	}
+	return nil
+}
+
+// formatCSVFloat formatiert einen Wert mit einer Nachkommastelle, fehlende Werte bleiben leer
+func formatCSVFloat(v float64, ok bool) string {
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', 1, 64)
+}
+
+// exportDegreeDays schreibt Heiz- und Kühlgradtage pro Tag als CSV (Date,HDD,CDD), wie es gängige
+// Energie-Monitoring-Werkzeuge importieren. HDD = max(0, Basis − Tagesmittel), CDD = max(0, Tagesmittel − Basis).
+func exportDegreeDays(w io.Writer, db *sql.DB, loc *time.Location, config Config, from, to time.Time) error {
+	temps, err := dailyTemperatures(db, config, from, to)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"Date", "HDD", "CDD"}); err != nil {
+		return err
+	}
+	for _, t := range temps {
+		record := []string{
+			t.day.In(loc).Format("2006-01-02"),
+			formatCSVFloat(baseHeatingDegreeDays(t.tMax, t.tMin, config.ExportHDDBaseTemp), true),
+			formatCSVFloat(coolingDegreeDays(t.tMax, t.tMin, config.CDDBaseTemp), true),
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// formatCSVValue formatiert einen Wert aus dailyStatsColumns, fehlende Werte bleiben leer
+func formatCSVValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// exportDailyStats schreibt die berechneten Tageswerte pro Tag als CSV: die Spalten aus daily_stats (metrisch,
+// Uhrzeiten als Unix-Zeit), die Serien bis zum jeweiligen Tag und die Einordnungen des Tages (Kenntage, Tropennacht,
+// Sturm- und Gewittertag). Die Serien setzen die Zählung vom Tag vor from fort.
+func exportDailyStats(w io.Writer, db *sql.DB, loc *time.Location, config Config, from, to time.Time) error {
+	header := []string{"date"}
+	for _, c := range dailyStatsColumns {
+		header = append(header, c.name)
+	}
+	header = append(header, "dry_days", "rain_days", "frost_nights", "heat_days",
+		"climate_days", "tropical_night", "storm_day", "lightning_day")
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	previous := from.AddDate(0, 0, -1)
+	dryDays := countDaysSinceRain(db, loc, config, from)
+	rainDays := countConsecutiveRainDays(db, loc, config, from)
+	frostNights := countFrostStreak(db, config, previous)
+	heatDays := countHeatStreak(db, config, previous)
+	streak := func(n int, ok bool) int {
+		if ok {
+			return n + 1
+		}
+		return 0
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		s, err := getStats(db, loc, config, day.Unix(), day.AddDate(0, 0, 1).Unix())
+		if err != nil {
+			return fmt.Errorf("Tageswerte %s: %v", day.Format("02.01.2006"), err)
+		}
+		dryDays = streak(dryDays, s.rainSum == 0)
+		rainDays = streak(rainDays, s.rainSum > 0)
+		frostNights = streak(frostNights, s.tMin < 0)
+		heatDays = streak(heatDays, s.tMax >= config.HeatStreakThreshold)
+
+		var climateDays []string
+		for _, name := range classifyDay(s.tMax, s.tMin) {
+			climateDays = append(climateDays, tr(config, name))
+		}
+		record := []string{day.Format("2006-01-02")}
+		for _, c := range dailyStatsColumns {
+			record = append(record, formatCSVValue(c.value(s)))
+		}
+		record = append(record, strconv.Itoa(dryDays), strconv.Itoa(rainDays), strconv.Itoa(frostNights), strconv.Itoa(heatDays),
+			strings.Join(climateDays, ";"), strconv.FormatBool(isTropicalNight(s, config)),
+			strconv.FormatBool(isStormDay(s, config)), strconv.FormatBool(hasLightning(s, config)))
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// fetchExternalData lädt die konfigurierten externen JSON-Quellen (Name → URL) für die Templates.
+// Antworten werden im Zustandsspeicher zwischengespeichert; schlägt ein Abruf fehl, wird die letzte
+// gespeicherte Antwort verwendet, damit eine ausgefallene Quelle den Post nicht verhindert.
+func fetchExternalData(store *sql.DB, sources map[string]string, ttl time.Duration) map[string]interface{} {
+	data := make(map[string]interface{})
+	for name, url := range sources {
+		body, fetched, cached := cachedExternalData(store, url)
+		if !cached || time.Since(fetched) >= ttl {
+			fresh, err := httpGetJSON(url)
+			if err == nil {
+				body = fresh
+				cached = true
+				storeExternalData(store, url, body)
+			} else if cached {
+				log.Printf("Warnung: Externe Daten %s nicht abrufbar, verwende Stand vom %s: %v", name, fetched.Format("02.01.2006 15:04"), err)
+			} else {
+				log.Printf("Warnung: Externe Daten %s nicht abrufbar: %v", name, err)
+				continue
+			}
+		}
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			log.Printf("Warnung: Externe Daten %s sind kein gültiges JSON: %v", name, err)
+			continue
+		}
+		data[name] = value
+	}
+	return data
+}
+
+// httpGetJSON ruft eine URL ab und prüft, dass die Antwort gültiges JSON ist
+func httpGetJSON(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if !json.Valid(body) {
+		return nil, fmt.Errorf("Antwort ist kein gültiges JSON")
+	}
+	return body, nil
+}
+
+// cachedExternalData liefert die zwischengespeicherte Antwort einer URL
+func cachedExternalData(store *sql.DB, url string) ([]byte, time.Time, bool) {
+	if store == nil {
+		return nil, time.Time{}, false
+	}
+	var body string
+	var fetched int64
+	if err := store.QueryRow(`SELECT body, fetched FROM external_cache WHERE url = ?;`, url).Scan(&body, &fetched); err != nil {
+		return nil, time.Time{}, false
+	}
+	return []byte(body), time.Unix(fetched, 0), true
+}
+
+// storeExternalData speichert eine abgerufene Antwort im Zwischenspeicher
+func storeExternalData(store *sql.DB, url string, body []byte) {
+	if store == nil {
+		return
+	}
+	if _, err := store.Exec(`INSERT OR REPLACE INTO external_cache (url, body, fetched) VALUES (?, ?, ?);`, url, string(body), time.Now().Unix()); err != nil {
+		log.Printf("Warnung: Externe Daten konnten nicht zwischengespeichert werden: %v", err)
+	}
+}
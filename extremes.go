@@ -0,0 +1,48 @@
+package main
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// getExtremeTimes ermittelt die Zeitpunkte von Höchst- und Tiefstwert der Temperatur (Unix-Zeit, 0 = unbekannt).
+// Mit daily_source "weewx" stammen sie aus maxtime/mintime von archive_day_outTemp, sonst aus der archive-Tabelle.
+// Tritt ein Extremwert mehrmals auf, zählt der erste Zeitpunkt.
+func getExtremeTimes(db *sql.DB, config Config, dayStart, start, end int64) (int64, int64, error) {
+	if config.DailySource != dailySourceArchive {
+		var maxTime, minTime sql.NullInt64
+		err := db.QueryRow(`SELECT maxtime, mintime FROM archive_day_outTemp WHERE dateTime = ?;`, dayStart).Scan(&maxTime, &minTime)
+		if err == nil && maxTime.Valid && minTime.Valid {
+			return maxTime.Int64, minTime.Int64, nil
+		}
+	}
+	const q = `SELECT
+		(SELECT dateTime FROM archive WHERE dateTime >= ?1 AND dateTime < ?2 AND outTemp IS NOT NULL ORDER BY outTemp DESC, dateTime LIMIT 1),
+		(SELECT dateTime FROM archive WHERE dateTime >= ?1 AND dateTime < ?2 AND outTemp IS NOT NULL ORDER BY outTemp ASC, dateTime LIMIT 1);`
+	var maxTime, minTime sql.NullInt64
+	if err := db.QueryRow(q, start, end).Scan(&maxTime, &minTime); err != nil {
+		return 0, 0, err
+	}
+	return maxTime.Int64, minTime.Int64, nil
+}
+
+// formatClock formatiert einen Zeitpunkt als Uhrzeit "16:10", leer wenn unbekannt
+func formatClock(ts int64, loc *time.Location) string {
+	if ts == 0 {
+		return ""
+	}
+	return time.Unix(ts, 0).In(loc).Format("15:04")
+}
+
+// formatExtremeTimes erzeugt die Zeile mit den Uhrzeiten von Höchst- und Tiefstwert
+func formatExtremeTimes(s dayStats, loc *time.Location, config Config) string {
+	var parts []string
+	if t := formatClock(s.tMaxTime, loc); t != "" {
+		parts = append(parts, tr(config, "Höchstwert um %s Uhr", t))
+	}
+	if t := formatClock(s.tMinTime, loc); t != "" {
+		parts = append(parts, tr(config, "Tiefstwert um %s Uhr", t))
+	}
+	return strings.Join(parts, ", ")
+}
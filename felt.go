@@ -0,0 +1,88 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+)
+
+// heatIndex berechnet den Hitzeindex (°C) nach Rothfusz; unter 27 °C entspricht er der Lufttemperatur
+func heatIndex(temp, humidity float64) float64 {
+	if temp < 27 || humidity < 40 {
+		return temp
+	}
+	t := temp*9/5 + 32
+	r := humidity
+	hi := -42.379 + 2.04901523*t + 10.14333127*r - 0.22475541*t*r - 0.00683783*t*t -
+		0.05481717*r*r + 0.00122874*t*t*r + 0.00085282*t*r*r - 0.00000199*t*t*r*r
+	return (hi - 32) * 5 / 9
+}
+
+// windChill berechnet die Windchill-Temperatur (°C) nach JAG/TI; Windgeschwindigkeit in km/h.
+// Außerhalb des Gültigkeitsbereichs (über 10 °C oder unter 4.8 km/h) entspricht sie der Lufttemperatur.
+func windChill(temp, windSpeed float64) float64 {
+	if temp > 10 || windSpeed < 4.8 {
+		return temp
+	}
+	v := math.Pow(windSpeed, 0.16)
+	return 13.12 + 0.6215*temp - 11.37*v + 0.3965*temp*v
+}
+
+// getApparentExtremes ermittelt den höchsten Hitzeindex und die niedrigste Windchill-Temperatur.
+// Vorhandene weewx-Spalten heatindex/windchill werden bevorzugt, sonst wird aus outTemp,
+// outHumidity und windSpeed gerechnet.
+func getApparentExtremes(db *sql.DB, start, end int64) (float64, float64, error) {
+	heatMax, chillMin := math.NaN(), math.NaN()
+
+	columns, err := archiveColumns(db, "archive")
+	if err != nil {
+		return heatMax, chillMin, err
+	}
+	selectColumn := func(name string) string {
+		if columns[strings.ToLower(name)] {
+			return name
+		}
+		return "NULL"
+	}
+	q := `SELECT ` + selectColumn("heatindex") + `, ` + selectColumn("windchill") + `, outTemp, ` +
+		selectColumn("outHumidity") + `, ` + selectColumn("windSpeed") + `
+		FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	rows, err := db.Query(q, start, end)
+	if err != nil {
+		return heatMax, chillMin, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hi, wc, temp, hum, wind sql.NullFloat64
+		if err := rows.Scan(&hi, &wc, &temp, &hum, &wind); err != nil {
+			return heatMax, chillMin, err
+		}
+		if !hi.Valid && temp.Valid && hum.Valid {
+			hi = sql.NullFloat64{Float64: heatIndex(temp.Float64, hum.Float64), Valid: true}
+		}
+		if !wc.Valid && temp.Valid && wind.Valid {
+			wc = sql.NullFloat64{Float64: windChill(temp.Float64, wind.Float64), Valid: true}
+		}
+		if hi.Valid && (math.IsNaN(heatMax) || hi.Float64 > heatMax) {
+			heatMax = hi.Float64
+		}
+		if wc.Valid && (math.IsNaN(chillMin) || wc.Float64 < chillMin) {
+			chillMin = wc.Float64
+		}
+	}
+	return heatMax, chillMin, rows.Err()
+}
+
+// formatFeltTemperature erzeugt "gefühlt"-Hinweise, wenn Hitzeindex oder Windchill um mehr als
+// felt_temp_delta von Höchst- bzw. Tiefsttemperatur abweichen
+func formatFeltTemperature(s dayStats, config Config) string {
+	var parts []string
+	if !math.IsNaN(s.heatIndexMax) && s.heatIndexMax-s.tMax > config.FeltTempDelta {
+		parts = append(parts, tr(config, "Höchstwert gefühlt bis %s (Hitzeindex)", unitOf(config, quantityTemperature).format(s.heatIndexMax, 1)))
+	}
+	if !math.IsNaN(s.windChillMin) && s.tMin-s.windChillMin > config.FeltTempDelta {
+		parts = append(parts, tr(config, "Tiefstwert gefühlt bis %s (Windchill)", unitOf(config, quantityTemperature).format(s.windChillMin, 1)))
+	}
+	return strings.Join(parts, ", ")
+}
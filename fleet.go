@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+// FleetStation ist eine Station im Fleet: eigene Konfiguration, eigene weewx-Datenbank
+type FleetStation struct {
+	Name   string `json:"name"`
+	Config string `json:"config"`         // Pfad zur config.json der Station
+	DB     string `json:"db"`             // Pfad zur weewx.sdb der Station
+	NOAA   string `json:"noaa,omitempty"` // optionaler NOAA-Report zum Regenvergleich
+}
+
+// Fleet beschreibt mehrere Stationen, die gemeinsam ausgeführt werden
+type Fleet struct {
+	Parallel               int            `json:"parallel"`                  // Anzahl gleichzeitig laufender Stationen (1 = nacheinander)
+	MinPostIntervalSeconds int            `json:"min_post_interval_seconds"` // Mindestabstand zwischen zwei Posts an denselben Server
+	Stations               []FleetStation `json:"stations"`
+
+	runMinute int // Uhrzeit des täglichen Laufs im Loop-Modus (Minuten nach Mitternacht), die späteste der Stationen
+}
+
+// loadFleet lädt die Fleet-Datei und prüft, dass sich die Stationen weder Zustandsspeicher noch Dead-Letter-Datei
+// teilen
+func loadFleet(path string) (Fleet, error) {
+	fleet := Fleet{Parallel: 1}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fleet, fmt.Errorf("Fleet-Datei %s kann nicht gelesen werden: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &fleet); err != nil {
+		return fleet, fmt.Errorf("Fehler beim Parsen der Fleet-Datei %s: %v", path, err)
+	}
+	if len(fleet.Stations) == 0 {
+		return fleet, fmt.Errorf("Fleet-Datei %s enthält keine Stationen", path)
+	}
+	if fleet.Parallel < 1 {
+		fleet.Parallel = 1
+	}
+
+	stores, deadLetters := make(map[string]string), make(map[string]string)
+	for i, st := range fleet.Stations {
+		if st.Name == "" {
+			fleet.Stations[i].Name = st.Config
+		}
+		if st.Config == "" || st.DB == "" {
+			return fleet, fmt.Errorf("Station %d in %s: config und db müssen angegeben sein", i+1, path)
+		}
+		config, err := loadConfig(st.Config)
+		if err != nil {
+			return fleet, fmt.Errorf("Station %s: %v", fleet.Stations[i].Name, err)
+		}
+		// Gemeinsamer Speicher würde Veröffentlichungsstatus und Rekorde der Stationen vermischen
+		if other, ok := stores[config.StorePath]; ok {
+			return fleet, fmt.Errorf("Stationen %s und %s verwenden denselben store_path %s", other, fleet.Stations[i].Name, config.StorePath)
+		}
+		stores[config.StorePath] = fleet.Stations[i].Name
+		// Die Stationen schreiben parallel in die Datei, und -resend sendet jeden Eintrag mit den Zugangsdaten der
+		// Station, die die Datei liest
+		if config.DeadLetterFile != "" {
+			if other, ok := deadLetters[config.DeadLetterFile]; ok {
+				return fleet, fmt.Errorf("Stationen %s und %s verwenden dieselbe dead_letter_file %s", other, fleet.Stations[i].Name, config.DeadLetterFile)
+			}
+			deadLetters[config.DeadLetterFile] = fleet.Stations[i].Name
+		}
+		if m := dailyRunMinute(config); m > fleet.runMinute {
+			fleet.runMinute = m
+		}
+	}
+	return fleet, nil
+}
+
+// postThrottle sorgt für einen Mindestabstand zwischen Posts an denselben Server,
+// auch wenn mehrere Stationen gleichzeitig laufen
+type postThrottle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     map[string]time.Time
+}
+
+// throttle ist die gemeinsame Ratenbegrenzung aller Stationen, nil ohne Fleet
+var throttle *postThrottle
+
+func newPostThrottle(interval time.Duration) *postThrottle {
+	return &postThrottle{interval: interval, next: make(map[string]time.Time)}
+}
+
+// wait blockiert, bis an den Server von target wieder gepostet werden darf
+func (t *postThrottle) wait(target string) {
+	if t == nil || t.interval <= 0 {
+		return
+	}
+	host := target
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	slot := t.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	t.next[host] = slot.Add(t.interval)
+	t.mu.Unlock()
+
+	if d := slot.Sub(now); d > 0 {
+		log.Printf("Ratenbegrenzung: warte %v vor dem Post an %s", d.Round(100*time.Millisecond), host)
+		time.Sleep(d)
+	}
+}
+
+// runFleet führt den Tageslauf für alle Stationen aus, mit höchstens fleet.Parallel gleichzeitig.
+// Im Test-Modus laufen die Stationen nacheinander, damit sich die Ausgaben nicht vermischen.
+// Fehler einer Station brechen die anderen nicht ab.
+func runFleet(fleet Fleet, testMode, loopMode bool) {
+	throttle = newPostThrottle(time.Duration(fleet.MinPostIntervalSeconds) * time.Second)
+
+	parallel := fleet.Parallel
+	if testMode {
+		parallel = 1
+	}
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for _, st := range fleet.Stations {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(st FleetStation) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := runFleetStation(st, testMode, loopMode); err != nil {
+				log.Printf("Station %s: %v", st.Name, err)
+			}
+		}(st)
+	}
+	wg.Wait()
+}
+
+// runFleetStation lädt die Konfiguration einer Station und führt ihren Tageslauf aus
+func runFleetStation(st FleetStation, testMode, loopMode bool) error {
+	log.Printf("Station %s: starte Lauf", st.Name)
+	config, err := loadConfig(st.Config)
+	if err != nil {
+		return fmt.Errorf("Fehler beim Laden der Konfiguration: %v", err)
+	}
+	if err := saveConfig(config, st.Config); err != nil {
+		log.Printf("Warnung: Konfiguration von Station %s konnte nicht gespeichert werden: %v", st.Name, err)
+	}
+	if err := runWeatherPosting(st.DB, config, testMode, loopMode, st.NOAA); err != nil {
+		return err
+	}
+	log.Printf("Station %s: Lauf beendet", st.Name)
+	return nil
+}
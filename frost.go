@@ -0,0 +1,88 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// frostSeason sind erster und letzter Frosttag (Tiefstwert unter 0 °C) einer Frostsaison bis zu einem Stichtag,
+// Nullwerte ohne Frost
+type frostSeason struct {
+	first, last time.Time
+	days        int // Tage mit Temperaturdaten
+}
+
+// getFrostSeason ermittelt ersten und letzten Frost der Saison ab start bis einschließlich to
+func getFrostSeason(db *sql.DB, config Config, start, to time.Time) (frostSeason, error) {
+	var f frostSeason
+	temps, err := dailyTemperatures(db, config, start, to)
+	if err != nil {
+		return f, err
+	}
+	f.days = len(temps)
+	for _, t := range temps {
+		if t.tMin >= 0 {
+			continue
+		}
+		if f.first.IsZero() {
+			f.first = t.day
+		}
+		f.last = t.day
+	}
+	return f, nil
+}
+
+// formatFrostDates meldet den ersten Frost der Saison im Herbst und, am Stichtag last_frost_announce_date,
+// den letzten Frost des Frühjahrs, jeweils mit dem Datum der vorigen Saison,
+// z.B. "❄️ Erster Frost der Saison (Vorjahr: 23.10.2025)" oder "🌱 Letzter Frost des Frühjahrs am 14.04.2026 (Vorjahr: 02.05.2025)".
+// Die Frostsaison beginnt am frost_season_start, damit ein Winter nicht am Jahreswechsel geteilt wird.
+func formatFrostDates(db *sql.DB, config Config, day time.Time, s dayStats) string {
+	if !config.FrostDatesEnabled {
+		return ""
+	}
+	start, ok := seasonStart(day, config.FrostSeasonStart, previousMonthDay(config.FrostSeasonStart))
+	if !ok {
+		return ""
+	}
+	announceMonth, announceDay, err := parseMonthDay(config.LastFrostAnnounceDate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: last_frost_announce_date: %v\n", err)
+		return ""
+	}
+	firstFrost := s.tMin < 0
+	lastFrost := day.Month() == announceMonth && day.Day() == announceDay
+	if !firstFrost && !lastFrost {
+		return ""
+	}
+
+	current, err := getFrostSeason(db, config, start, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Frostdaten nicht verfügbar: %v\n", err)
+		return ""
+	}
+	previous, err := getFrostSeason(db, config, start.AddDate(-1, 0, 0), start.AddDate(0, 0, -1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Frostdaten der Vorsaison nicht verfügbar: %v\n", err)
+	}
+	// Vergleich nur mit einer zu mindestens 90 % erfassten Vorsaison
+	seasonDays := int(start.Sub(start.AddDate(-1, 0, 0)).Hours()/24 + 0.5)
+	comparison := func(t time.Time) string {
+		switch {
+		case previous.days*10 < seasonDays*9:
+			return ""
+		case t.IsZero():
+			return tr(config, " (Vorjahr: kein Frost)")
+		}
+		return tr(config, " (Vorjahr: %s)", formatDate(config, t))
+	}
+
+	if firstFrost && current.first.Format("2006-01-02") == day.Format("2006-01-02") {
+		return tr(config, "❄️ Erster Frost der Saison") + comparison(previous.first)
+	}
+	if lastFrost && !current.last.IsZero() && current.last.Year() == day.Year() {
+		return tr(config, "🌱 Letzter Frost des Frühjahrs am %s", formatDate(config, current.last)) + comparison(previous.last)
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GrafanaPanel ist ein Diagramm, das über die Render-API von Grafana als Bild abgerufen und an Posts gehängt wird,
+// z.B. {"url": "https://grafana.example.org/render/d-solo/abc/wetter?panelId=2&from={from}&to={to}&width={width}&height={height}"}
+type GrafanaPanel struct {
+	URL         string   `json:"url"`         // {from}, {to} (Unix-Millisekunden), {width}, {height} werden ersetzt
+	Description string   `json:"description"` // Alt-Text; leer = allgemeine Beschreibung mit Zeitraum
+	Posts       []string `json:"posts"`       // Post-Arten (daily, range, year_review); leer = alle
+}
+
+// grafanaPostTypes sind die Post-Arten mit Bildern, an die Grafana-Diagramme gehängt werden können
+var grafanaPostTypes = []string{postTypeDaily, postTypeRange, postTypeYearReview}
+
+// validateGrafanaPanels prüft grafana_panels
+func validateGrafanaPanels(config Config) error {
+	for _, panel := range config.GrafanaPanels {
+		u, err := url.Parse(panel.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("grafana_panels: ungültige URL %q", panel.URL)
+		}
+		for _, postType := range panel.Posts {
+			if !(GrafanaPanel{}).forPost(postType) {
+				return fmt.Errorf("grafana_panels: Post-Art %q hat keine Bilder (%s)", postType, strings.Join(grafanaPostTypes, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// forPost prüft, ob das Diagramm an Posts der Art postType gehängt wird
+func (panel GrafanaPanel) forPost(postType string) bool {
+	posts := panel.Posts
+	if len(posts) == 0 {
+		posts = grafanaPostTypes
+	}
+	for _, p := range posts {
+		if p == postType {
+			return true
+		}
+	}
+	return false
+}
+
+// grafanaURL setzt den Zeitraum [from, to) und die Diagrammgröße in die URL eines Grafana-Diagramms ein
+func grafanaURL(config Config, panel GrafanaPanel, from, to time.Time) string {
+	theme := chartThemeFor(config)
+	return strings.NewReplacer(
+		"{from}", strconv.FormatInt(from.UnixNano()/int64(time.Millisecond), 10),
+		"{to}", strconv.FormatInt(to.UnixNano()/int64(time.Millisecond), 10),
+		"{width}", strconv.Itoa(theme.width),
+		"{height}", strconv.Itoa(theme.height),
+	).Replace(panel.URL)
+}
+
+// fetchGrafanaImage ruft ein Diagramm über die Render-API ab und liefert Bilddaten und Bildformat. Grafana
+// antwortet bei fehlender Anmeldung teils mit der Login-Seite, daher wird das Bildformat am Inhalt geprüft.
+func fetchGrafanaImage(config Config, imageURL string) ([]byte, string, error) {
+	req, err := http.NewRequest("GET", imageURL, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if config.GrafanaAPIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+config.GrafanaAPIKey)
+	}
+	// Das Rendern dauert bei Grafana mitunter mehrere Sekunden
+	resp, err := (&http.Client{Timeout: 60 * time.Second}).Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	contentType := http.DetectContentType(body)
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, "", fmt.Errorf("Antwort ist kein Bild (%s)", contentType)
+	}
+	return body, contentType, nil
+}
+
+// grafanaMedia ruft die Grafana-Diagramme für die Post-Art postType und den Zeitraum [from, to) ab; nicht
+// abrufbare Diagramme entfallen mit einer Warnung
+func grafanaMedia(config Config, postType string, from, to time.Time) []mediaAttachment {
+	var media []mediaAttachment
+	for i, panel := range config.GrafanaPanels {
+		if !panel.forPost(postType) {
+			continue
+		}
+		data, contentType, err := fetchGrafanaImage(config, grafanaURL(config, panel, from, to))
+		if err != nil {
+			log.Printf("Warnung: Grafana-Diagramm %d nicht verfügbar: %v", i+1, err)
+			continue
+		}
+		description := panel.Description
+		if description == "" {
+			description = tr(config, "Grafana-Diagramm vom %s bis %s", formatDate(config, from), formatDate(config, to.AddDate(0, 0, -1)))
+			if to.Equal(from.AddDate(0, 0, 1)) {
+				description = tr(config, "Grafana-Diagramm vom %s", formatDate(config, from))
+			}
+		}
+		ext := strings.TrimPrefix(contentType, "image/")
+		m, err := writeMediaFile(fmt.Sprintf("grafana-%d-*.%s", i+1, ext), data, contentType, description)
+		if err != nil {
+			log.Printf("Warnung: Grafana-Diagramm %d nicht verfügbar: %v", i+1, err)
+			continue
+		}
+		media = append(media, m)
+	}
+	return media
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"time"
+	"unicode/utf8"
+)
+
+// Messgröße des Jahreskalenders (year_heatmap)
+const (
+	heatmapTemperature = "temperature" // Tagesmitteltemperatur
+	heatmapRain        = "rain"        // Tagesniederschlag
+)
+
+// heatmapDay sind Tagesmitteltemperatur (°C, Mittel aus Höchst- und Tiefstwert) und Niederschlag (mm) eines
+// Tages; NaN ohne Daten
+type heatmapDay struct {
+	day  time.Time
+	temp float64
+	rain float64
+}
+
+// getYearDays liefert die Tageswerte aller Tage des Jahres year
+func getYearDays(db *sql.DB, loc *time.Location, config Config, year int) ([]heatmapDay, error) {
+	from, to := time.Date(year, 1, 1, 0, 0, 0, 0, loc), time.Date(year, 12, 31, 0, 0, 0, 0, loc)
+	temps, err := dailyTemperatures(db, config, from, to)
+	if err != nil {
+		return nil, err
+	}
+	means := make(map[string]float64, len(temps))
+	for _, t := range temps {
+		means[t.day.Format("2006-01-02")] = (t.tMax + t.tMin) / 2
+	}
+	rain := getDailyRain(db, loc, config, from, to)
+	var days []heatmapDay
+	for i, day := 0, from; !day.After(to); i, day = i+1, day.AddDate(0, 0, 1) {
+		d := heatmapDay{day: day, temp: math.NaN(), rain: rain[i]}
+		if mean, ok := means[day.Format("2006-01-02")]; ok {
+			d.temp = mean
+		}
+		days = append(days, d)
+	}
+	return days, nil
+}
+
+// heatmapValue liefert den Wert eines Tages für die Messgröße kind
+func heatmapValue(d heatmapDay, kind string) float64 {
+	if kind == heatmapRain {
+		return d.rain
+	}
+	return d.temp
+}
+
+// mixColor mischt die Farben a und b im Verhältnis t (0 = a, 1 = b)
+func mixColor(a, b color.RGBA, t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t))
+	mix := func(x, y uint8) uint8 { return uint8(math.Round(float64(x) + (float64(y)-float64(x))*t)) }
+	return color.RGBA{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), 0xff}
+}
+
+// renderYearHeatmap zeichnet einen Jahreskalender wie bei GitHub: je Woche eine Spalte, je Wochentag (Montag
+// oben) eine Zeile, die Farbe zeigt die Tagesmitteltemperatur (kalt über warm nach heiß) bzw. den Niederschlag
+// (trocken bis nass, Wurzelskala, damit auch kleine Mengen sichtbar sind). Tage ohne Daten bleiben leer.
+func renderYearHeatmap(config Config, theme chartTheme, days []heatmapDay, kind string) (*chartDrawing, error) {
+	lo, hi := math.NaN(), math.NaN()
+	for _, d := range days {
+		if v := heatmapValue(d, kind); !math.IsNaN(v) {
+			if math.IsNaN(lo) || v < lo {
+				lo = v
+			}
+			if math.IsNaN(hi) || v > hi {
+				hi = v
+			}
+		}
+	}
+	if len(days) == 0 || math.IsNaN(lo) {
+		return nil, fmt.Errorf("keine Tageswerte")
+	}
+	unit := unitOf(config, quantityTemperature)
+	title := tr(config, "Tagesmitteltemperatur %d", days[0].day.Year())
+	if kind == heatmapRain {
+		unit = unitOf(config, quantityRain)
+		title = tr(config, "Niederschlag %d", days[0].day.Year())
+		lo, hi = 0, math.Max(hi, 1)
+	} else {
+		lo, hi = math.Floor(lo/5)*5, math.Ceil(hi/5)*5
+		if hi-lo < 5 {
+			hi = lo + 5
+		}
+	}
+	colorOf := func(v float64) color.RGBA {
+		if kind == heatmapRain {
+			if v <= 0 {
+				return theme.grid
+			}
+			return mixColor(theme.grid, theme.bar, 0.15+0.85*math.Sqrt(v/hi))
+		}
+		t := (v - lo) / (hi - lo)
+		if t < 0.5 {
+			return mixColor(theme.low, theme.solar, t*2)
+		}
+		return mixColor(theme.solar, theme.high, t*2-1)
+	}
+
+	scale := theme.fontScale
+	line := (glyphHeight + 4) * scale
+	weekdays := []string{tr(config, "Mo"), tr(config, "Mi"), tr(config, "Fr")}
+	left := 0
+	for _, w := range weekdays {
+		if tw := textWidth(w, scale); tw > left {
+			left = tw
+		}
+	}
+	left += 4 * scale
+	offset := (int(days[0].day.Weekday()) + 6) % 7 // Montag = 0
+	weeks := (offset + len(days) + 6) / 7
+	cell := (theme.width - left - 4*scale) / weeks
+	gap := cell / 8
+	if gap < 1 {
+		gap = 1
+	}
+	top := 2*line + line
+	d := newChartDrawing(theme, top+7*cell+3*line)
+
+	d.text(left, 2*scale, title, theme.text, scale)
+	for i, w := range weekdays {
+		row := 2 * i
+		d.text(2*scale, top+row*cell+(cell-glyphHeight*scale)/2, w, theme.text, scale)
+	}
+	for i, day := range days {
+		pos := offset + i
+		x, y := left+(pos/7)*cell, top+(pos%7)*cell
+		if day.day.Day() == 1 {
+			name := monthName(config, day.day.Month())
+			if utf8.RuneCountInString(name) > 3 {
+				name = string([]rune(name)[:3])
+			}
+			d.text(x, top-line, name, theme.text, scale)
+		}
+		if v := heatmapValue(day, kind); !math.IsNaN(v) {
+			d.rect(image.Rect(x, y, x+cell-gap, y+cell-gap), colorOf(v))
+		}
+	}
+
+	// Legende: Farbverlauf mit kleinstem und größtem Wert
+	ly := top + 7*cell + line
+	lx := left
+	label := unit.format(lo, 0)
+	d.text(lx, ly+(cell-glyphHeight*scale)/2, label, theme.text, scale)
+	lx += textWidth(label, scale) + 2*scale
+	const steps = 10
+	for i := 0; i < steps; i++ {
+		v := lo + (hi-lo)*float64(i)/float64(steps-1)
+		d.rect(image.Rect(lx, ly, lx+cell-gap, ly+cell-gap), colorOf(v))
+		lx += cell
+	}
+	d.text(lx+2*scale, ly+(cell-glyphHeight*scale)/2, unit.format(hi, 0), theme.text, scale)
+	return d, nil
+}
+
+// altTextYearHeatmap beschreibt den Jahreskalender, z.B. "Kalender der Tagesmitteltemperatur 2024: wärmster Tag
+// 12.07. (24.3 °C), kältester Tag 15.01. (-8.2 °C)" bzw. "Kalender des Niederschlags 2024: 142 Tage mit
+// Niederschlag, nassester Tag 02.10. (31.4 mm)"
+func altTextYearHeatmap(config Config, days []heatmapDay, kind string) string {
+	if len(days) == 0 {
+		return ""
+	}
+	year := days[0].day.Year()
+	lo, hi, wet := -1, -1, 0
+	for i, d := range days {
+		v := heatmapValue(d, kind)
+		if math.IsNaN(v) {
+			continue
+		}
+		if v > 0 {
+			wet++
+		}
+		if lo < 0 || v < heatmapValue(days[lo], kind) {
+			lo = i
+		}
+		if hi < 0 || v > heatmapValue(days[hi], kind) {
+			hi = i
+		}
+	}
+	if kind == heatmapRain {
+		text := tr(config, "Kalender des Niederschlags %d", year)
+		if hi < 0 || days[hi].rain <= 0 {
+			return text + tr(config, ": kein Niederschlag")
+		}
+		return text + tr(config, ": %d Tage mit Niederschlag, nassester Tag %s (%s)", wet,
+			formatDayMonth(config, days[hi].day), unitOf(config, quantityRain).format(days[hi].rain, 1))
+	}
+	text := tr(config, "Kalender der Tagesmitteltemperatur %d", year)
+	if hi < 0 {
+		return text
+	}
+	unit := unitOf(config, quantityTemperature)
+	return text + tr(config, ": wärmster Tag %s (%s), kältester Tag %s (%s)",
+		formatDayMonth(config, days[hi].day), unit.format(days[hi].temp, 1),
+		formatDayMonth(config, days[lo].day), unit.format(days[lo].temp, 1))
+}
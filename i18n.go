@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Sprachen der Posts (language)
+const (
+	languageGerman  = "de"
+	languageEnglish = "en"
+)
+
+// messageCatalogs enthält die Übersetzungen der Post-Texte je Sprache. Schlüssel ist der deutsche Text bzw. das
+// deutsche Format, Deutsch braucht daher keinen eigenen Katalog. Fehlt ein Eintrag, bleibt der deutsche Text stehen.
+var messageCatalogs = map[string]map[string]string{
+	languageEnglish: englishMessages,
+}
+
+// englishMonths enthält die englischen Monatsnamen, Index 0 = January
+var englishMonths = [...]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+
+// validLanguage prüft, ob es für die Sprache Post-Texte gibt
+func validLanguage(language string) bool {
+	_, ok := messageCatalogs[language]
+	return ok || language == languageGerman
+}
+
+// tr übersetzt einen Textbaustein in die Sprache der Konfiguration und füllt ihn wie fmt.Sprintf mit args.
+// Auch selbst konfigurierte Texte (z.B. Hinweisregeln) laufen hier durch: Die deutschen Standardtexte werden
+// so übersetzt, eigene Texte bleiben unverändert.
+func tr(config Config, format string, args ...interface{}) string {
+	if translated, ok := messageCatalogs[config.Language][format]; ok {
+		format = translated
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// monthName liefert den Monatsnamen in der Sprache der Konfiguration
+func monthName(config Config, m time.Month) string {
+	if config.Language == languageEnglish {
+		return englishMonths[m-1]
+	}
+	return germanMonths[m-1]
+}
+
+// formatDate formatiert ein Datum für Posts, z.B. "17.10.2026" bzw. "Oct 17, 2026"
+func formatDate(config Config, t time.Time) string {
+	if config.Language == languageEnglish {
+		return t.Format("Jan 2, 2006")
+	}
+	return t.Format("02.01.2006")
+}
+
+// formatDayMonth formatiert ein Datum ohne Jahr, z.B. "17.10." bzw. "Oct 17"
+func formatDayMonth(config Config, t time.Time) string {
+	if config.Language == languageEnglish {
+		return t.Format("Jan 2")
+	}
+	return t.Format("02.01.")
+}
+
+// ordinal liefert die Ordnungszahl, z.B. "3." bzw. "3rd"
+func ordinal(config Config, n int) string {
+	if config.Language != languageEnglish {
+		return fmt.Sprintf("%d.", n)
+	}
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageLimit begrenzt die Bilder für eine Plattform; 0 = keine Grenze
+type ImageLimit struct {
+	MaxBytes  int64 `json:"max_bytes"`
+	MaxWidth  int   `json:"max_width"`
+	MaxHeight int   `json:"max_height"`
+}
+
+// exceeded prüft, ob ein Bild der Größe size Bytes und w×h Pixel die Grenze überschreitet
+func (l ImageLimit) exceeded(size int64, w, h int) bool {
+	return (l.MaxBytes > 0 && size > l.MaxBytes) || (l.MaxWidth > 0 && w > l.MaxWidth) || (l.MaxHeight > 0 && h > l.MaxHeight)
+}
+
+// validateImageLimits prüft image_limits
+func validateImageLimits(config Config) error {
+	for platform, l := range config.ImageLimits {
+		if platform != platformLemmy && platform != platformMastodon {
+			return fmt.Errorf("image_limits: unbekannte Plattform %q (lemmy, mastodon)", platform)
+		}
+		if l.MaxBytes < 0 || l.MaxWidth < 0 || l.MaxHeight < 0 {
+			return fmt.Errorf("image_limits: ungültige Grenze für %s (>= 0, 0 = keine)", platform)
+		}
+		if l.MaxBytes > 0 && l.MaxBytes < 10*1024 {
+			return fmt.Errorf("image_limits: max_bytes für %s ist zu klein (mindestens 10240)", platform)
+		}
+	}
+	return nil
+}
+
+// fitImage passt ein Bild an die Grenzen der Plattform an: zu große Bilder werden verkleinert und, wenn das als
+// PNG nicht genügt, als JPEG mit abnehmender Qualität gespeichert, notfalls weiter verkleinert. Liegt das Bild
+// innerhalb der Grenzen, wird es unverändert geliefert, sonst eine neue temporäre Datei, die der Aufrufer löscht.
+// Von animierten GIFs bleibt dabei nur das erste Bild.
+func fitImage(config Config, platform string, m mediaAttachment) (mediaAttachment, error) {
+	limit, ok := config.ImageLimits[platform]
+	if !ok {
+		return m, nil
+	}
+	data, err := os.ReadFile(m.Path)
+	if err != nil {
+		return m, err
+	}
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return m, fmt.Errorf("Bild %s nicht lesbar: %v", m.Path, err)
+	}
+	if !limit.exceeded(int64(len(data)), cfg.Width, cfg.Height) {
+		return m, nil
+	}
+	var src image.Image
+	if format == "gif" {
+		// erstes Bild eines (animierten) GIFs
+		var g *gif.GIF
+		if g, err = gif.DecodeAll(bytes.NewReader(data)); err == nil {
+			src = g.Image[0]
+		}
+	} else {
+		src, _, err = image.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return m, fmt.Errorf("Bild %s nicht lesbar: %v", m.Path, err)
+	}
+
+	w, h := fitSize(cfg.Width, cfg.Height, limit)
+	for {
+		scaled := scaleImage(src, w, h)
+		out, contentType, err := encodeWithin(scaled, limit.MaxBytes)
+		if err != nil {
+			return m, err
+		}
+		if out != nil {
+			log.Printf("Bild %s für %s angepasst: %d×%d → %d×%d Pixel, %d → %d Bytes", filepath.Base(m.Path), platform,
+				cfg.Width, cfg.Height, w, h, len(data), len(out))
+			ext := ".png"
+			if contentType == "image/jpeg" {
+				ext = ".jpg"
+			}
+			name := strings.TrimPrefix(strings.TrimSuffix(filepath.Base(m.Path), filepath.Ext(m.Path)), "weewxstats2social-")
+			fitted, err := writeMediaFile(platform+"-"+name+"-*"+ext, out, contentType, m.Description)
+			if err != nil {
+				return m, err
+			}
+			fitted.Focus, fitted.Link = m.Focus, m.Link
+			return fitted, nil
+		}
+		if w < 200 || h < 200 {
+			return m, fmt.Errorf("Bild %s lässt sich nicht auf %d Bytes verkleinern", m.Path, limit.MaxBytes)
+		}
+		w, h = w*3/4, h*3/4
+	}
+}
+
+// fitSize liefert die Bildgröße innerhalb von max_width und max_height bei gleichem Seitenverhältnis
+func fitSize(w, h int, limit ImageLimit) (int, int) {
+	f := 1.0
+	if limit.MaxWidth > 0 && w > limit.MaxWidth {
+		f = float64(limit.MaxWidth) / float64(w)
+	}
+	if limit.MaxHeight > 0 && h > limit.MaxHeight {
+		f = math.Min(f, float64(limit.MaxHeight)/float64(h))
+	}
+	if f >= 1 {
+		return w, h
+	}
+	return int(math.Max(1, math.Floor(float64(w)*f))), int(math.Max(1, math.Floor(float64(h)*f)))
+}
+
+// encodeWithin speichert das Bild als PNG oder, wenn das größer als maxBytes ist, als JPEG mit abnehmender
+// Qualität. Passt keine Variante, ist das Ergebnis nil.
+func encodeWithin(img *image.RGBA, maxBytes int64) ([]byte, string, error) {
+	data, err := encodePNG(img)
+	if err != nil {
+		return nil, "", err
+	}
+	if maxBytes <= 0 || int64(len(data)) <= maxBytes {
+		return data, "image/png", nil
+	}
+	// JPEG kennt keine Transparenz: auf weißem Grund
+	opaque := image.NewRGBA(img.Bounds())
+	draw.Draw(opaque, opaque.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+	draw.Draw(opaque, opaque.Bounds(), img, img.Bounds().Min, draw.Over)
+	for _, quality := range []int{90, 80, 65, 50} {
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, opaque, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, "", err
+		}
+		if int64(buf.Len()) <= maxBytes {
+			return buf.Bytes(), "image/jpeg", nil
+		}
+	}
+	return nil, "", nil
+}
+
+// scaleImage verkleinert ein Bild auf w×h Pixel; jedes Zielpixel ist der Mittelwert der überdeckten Quellpixel
+func scaleImage(src image.Image, w, h int) *image.RGBA {
+	b := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(b)
+		draw.Draw(rgba, b, src, b.Min, draw.Src)
+	}
+	if w == b.Dx() && h == b.Dy() {
+		return rgba
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := b.Min.Y+y*b.Dy()/h, b.Min.Y+(y+1)*b.Dy()/h
+		if y1 == y0 {
+			y1++
+		}
+		for x := 0; x < w; x++ {
+			x0, x1 := b.Min.X+x*b.Dx()/w, b.Min.X+(x+1)*b.Dx()/w
+			if x1 == x0 {
+				x1++
+			}
+			var r, g, bl, a, n int
+			for sy := y0; sy < y1; sy++ {
+				i := rgba.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(rgba.Pix[i])
+					g += int(rgba.Pix[i+1])
+					bl += int(rgba.Pix[i+2])
+					a += int(rgba.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / n), uint8(g / n), uint8(bl / n), uint8(a / n)})
+		}
+	}
+	return dst
+}
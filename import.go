@@ -0,0 +1,148 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// importResult ist das Ergebnis eines Workers für einen einzelnen Tag
+type importResult struct {
+	day   string
+	stats dayStats
+	err   error
+}
+
+// importHistory verarbeitet das gesamte weewx-Archiv tageweise in den Zustandsspeicher.
+// Bereits importierte Tage werden übersprungen, so dass ein abgebrochener Import einfach
+// erneut gestartet werden kann. Die Tage werden von einem Worker-Pool berechnet, geschrieben
+// wird nur von einer Goroutine, um Sperrkonflikte in SQLite zu vermeiden.
+// Mit rebuild werden alle Tage ohne die Tageszusammenfassungen von weewx (archive_day_*) allein aus der
+// archive-Tabelle neu berechnet und überschrieben; danach kann daily_source auf "archive" stehen.
+func importHistory(dbPath string, config Config, workers int, rebuild bool) error {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("timezone: %v", err)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	chunkRows := scanChunkRows(config)
+
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return fmt.Errorf("open DB: %v", err)
+	}
+	defer db.Close()
+
+	store, err := openStore(config.StorePath)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	var first, last sql.NullInt64
+	if err := db.QueryRow(`SELECT MIN(dateTime), MAX(dateTime) FROM archive;`).Scan(&first, &last); err != nil {
+		return fmt.Errorf("Archivzeitraum: %v", err)
+	}
+	if !first.Valid || !last.Valid {
+		return fmt.Errorf("archive-Tabelle ist leer")
+	}
+
+	done := make(map[string]bool)
+	if rebuild {
+		config.DailySource = dailySourceArchive
+		log.Printf("Neuaufbau: alle Tageswerte werden aus der archive-Tabelle neu berechnet")
+	} else if done, err = storedDays(store); err != nil {
+		return fmt.Errorf("bereits importierte Tage: %v", err)
+	}
+
+	// Nur vollständige Tage bis einschließlich gestern importieren
+	firstDay := time.Unix(first.Int64, 0).In(loc)
+	firstDay = time.Date(firstDay.Year(), firstDay.Month(), firstDay.Day(), 0, 0, 0, 0, loc)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	// Tage ohne Archivdaten (Ausfall der Station) werden nicht importiert
+	withData, err := archiveDays(db, loc, firstDay.Unix(), today.Unix(), chunkRows)
+	if err != nil {
+		return fmt.Errorf("Archivtage: %v", err)
+	}
+
+	var pending []time.Time
+	total, empty := 0, 0
+	for d := firstDay; d.Before(today); d = d.AddDate(0, 0, 1) {
+		key := d.Format("2006-01-02")
+		if !withData[key] {
+			empty++
+			continue
+		}
+		total++
+		if !done[key] {
+			pending = append(pending, d)
+		}
+	}
+	log.Printf("Import: %d Tage im Archiv (%d ohne Daten), %d bereits importiert, %d ausstehend (%d Worker, Blockgröße %d Zeilen)",
+		total, empty, total-len(pending), len(pending), workers, chunkRows)
+	if len(pending) == 0 {
+		return nil
+	}
+
+	jobs := make(chan time.Time)
+	results := make(chan importResult)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range jobs {
+				s, err := getStats(db, loc, config, d.Unix(), d.AddDate(0, 0, 1).Unix())
+				results <- importResult{day: d.Format("2006-01-02"), stats: s, err: err}
+			}
+		}()
+	}
+	go func() {
+		for _, d := range pending {
+			jobs <- d
+		}
+		close(jobs)
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	imported, failed := 0, 0
+	started := time.Now()
+	lastReport := started
+	for r := range results {
+		if r.err != nil {
+			failed++
+			log.Printf("Import: Fehler für %s: %v", r.day, r.err)
+			continue
+		}
+		if err := saveDayStats(store, r.day, r.stats); err != nil {
+			failed++
+			log.Printf("Import: Speichern für %s fehlgeschlagen: %v", r.day, err)
+			continue
+		}
+		imported++
+		if time.Since(lastReport) >= 5*time.Second || imported+failed == len(pending) {
+			lastReport = time.Now()
+			log.Printf("Import: %d/%d Tage (%.0f %%), %d Fehler, %v vergangen",
+				imported+failed, len(pending), 100*float64(imported+failed)/float64(len(pending)), failed,
+				time.Since(started).Round(time.Second))
+		}
+	}
+
+	log.Printf("Import abgeschlossen: %d Tage importiert, %d Fehler", imported, failed)
+	if err := updateRecords(store, config.RecordMinHistoryDays); err != nil {
+		return fmt.Errorf("Rekorde: %v", err)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d Tage konnten nicht importiert werden, erneuter Aufruf setzt den Import fort", failed)
+	}
+	return nil
+}
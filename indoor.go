@@ -0,0 +1,57 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+)
+
+// getIndoorStats ermittelt die Extremwerte von Innentemperatur (°C) und Luftfeuchte innen (%).
+// Fehlende Spalten ergeben NaN.
+func getIndoorStats(db *sql.DB, start, end int64) (tempMin, tempMax, humMin, humMax float64, err error) {
+	tempMin, tempMax, err = columnExtremes(db, "inTemp", start, end)
+	if err != nil {
+		return
+	}
+	humMin, humMax, err = columnExtremes(db, "inHumidity", start, end)
+	return
+}
+
+// indoorEnabled prüft, ob der Innenraum-Abschnitt für eine Plattform freigegeben ist
+func indoorEnabled(config Config, platform string) bool {
+	for _, p := range config.IndoorPublishers {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+// formatIndoor erzeugt den Innenraum-Abschnitt; ohne Innensensor bleibt er leer
+func formatIndoor(s dayStats, config Config) string {
+	if math.IsNaN(s.inTempMin) && math.IsNaN(s.inHumMin) {
+		return ""
+	}
+	line := tr(config, "🏠 Innen:")
+	if !math.IsNaN(s.inTempMin) {
+		unit := unitOf(config, quantityTemperature)
+		line += tr(config, " %s bis %s %s", unit.number(s.inTempMin, 1), unit.number(s.inTempMax, 1), unit.symbol)
+	}
+	if !math.IsNaN(s.inHumMin) {
+		if !math.IsNaN(s.inTempMin) {
+			line += ","
+		}
+		line += tr(config, " Luftfeuchte %.0f bis %.0f %%", s.inHumMin, s.inHumMax)
+	}
+	return line
+}
+
+// withIndoor hängt den Innenraum-Abschnitt an den Post-Text an, sofern er für die Plattform freigegeben ist
+func withIndoor(text string, s dayStats, config Config, platform string) string {
+	if !indoorEnabled(config, platform) {
+		return text
+	}
+	if line := formatIndoor(s, config); line != "" {
+		return text + "\n" + line
+	}
+	return text
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+)
+
+// getLightningStats ermittelt Anzahl der Blitze und die geringste Entfernung (km) des Zeitraums.
+// Ohne Blitzsensor-Spalten sind beide Werte NaN.
+func getLightningStats(db *sql.DB, config Config, start, end int64) (float64, float64, error) {
+	strikes, distance := math.NaN(), math.NaN()
+	countColumn := optionalColumn(db, config.LightningCountColumn)
+	if countColumn == "" {
+		return strikes, distance, nil
+	}
+	distanceColumn := optionalColumn(db, config.LightningDistanceColumn)
+	distanceExpr := "NULL"
+	if distanceColumn != "" {
+		// Entfernung nur aus Intervallen mit Blitzen, sonst steht dort oft der letzte Wert
+		distanceExpr = `MIN(CASE WHEN ` + countColumn + ` > 0 THEN ` + distanceColumn + ` END)`
+	}
+	var count, dist sql.NullFloat64
+	q := `SELECT SUM(` + countColumn + `), ` + distanceExpr + ` FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	if err := db.QueryRow(q, start, end).Scan(&count, &dist); err != nil {
+		return strikes, distance, err
+	}
+	if count.Valid {
+		strikes = count.Float64
+	}
+	if dist.Valid {
+		distance = dist.Float64
+	}
+	return strikes, distance, nil
+}
+
+// hasLightning prüft, ob genug Blitze für eine Erwähnung erfasst wurden
+func hasLightning(s dayStats, config Config) bool {
+	return !math.IsNaN(s.lightningStrikes) && s.lightningStrikes > 0 && s.lightningStrikes >= float64(config.LightningMinStrikes)
+}
+
+// formatLightning erzeugt die Gewitterzeile, leer unterhalb der Mindestanzahl an Blitzen
+func formatLightning(s dayStats, config Config) string {
+	if !hasLightning(s, config) {
+		return ""
+	}
+	line := tr(config, "⛈️ Blitze: %.0f erfasst", s.lightningStrikes)
+	if !math.IsNaN(s.lightningDistance) {
+		line += tr(config, ", nächster in %s Entfernung", unitOf(config, quantityDistance).format(s.lightningDistance, 0))
+	}
+	return line
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"database/sql"
 	"encoding/json"
 	"flag"
@@ -10,18 +11,17 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"os/signal"
+	"runtime"
+	"runtime/pprof"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
-// Konfiguration der Schwellwerte
-const (
-	sunThreshold      = 120.0 // W/m² – Strahlung ab dem eine Stunde als Sonnenstunde zählt
-	drySpellThreshold = 3     // Anzahl Tage ohne Regen für Hinweis im Post
-)
-
 // Config enthält die Konfiguration für das Programm
 type Config struct {
 	LemmyServer    string    `json:"lemmy_server"`
@@ -34,6 +34,271 @@ type Config struct {
 	MastodonServer     string `json:"mastodon_server"`
 	MastodonToken      string `json:"mastodon_token"`
 	MastodonVisibility string `json:"mastodon_visibility"`
+	// Sichtbarkeit je Post-Art, z.B. {"year_review": "public"}; ohne Eintrag gilt mastodon_visibility
+	MastodonVisibilities map[string]string `json:"mastodon_visibilities"`
+	// Sprache der Mastodon-Posts (ISO 639, z.B. "de"); leer = language
+	MastodonLanguage string `json:"mastodon_language"`
+	// Höchstdauer in Sekunden, die auf die Verarbeitung hochgeladener Bilder gewartet wird
+	MastodonMediaTimeout int `json:"mastodon_media_timeout"`
+	// Grenzen für Bilder je Plattform, z.B. {"mastodon": {"max_bytes": 8388608}}; größere Bilder werden verkleinert
+	ImageLimits map[string]ImageLimit `json:"image_limits"`
+	// Inhaltswarnung (Template) je Post-Art, z.B. {"top_list": "Bestenliste"}; ohne Eintrag keine Inhaltswarnung
+	ContentWarnings map[string]string `json:"content_warnings"`
+
+	// Optionales Template (Go text/template) für den Titel des Tagesposts; leer = Standardtitel
+	TitleTemplate string `json:"title_template"`
+	// Eigene Templates je Plattform (lemmy, mastodon, webhook), z.B. ausführlich für Lemmy und knapp für Mastodon
+	PublisherTemplates map[string]PublisherTemplate `json:"publisher_templates"`
+	// Abschnitte des Tagesposts und ihre Reihenfolge: Gruppen (core, streaks, records, astronomy, comparisons)
+	// oder einzelne Abschnitte; leer = alle Abschnitte in der Standardreihenfolge
+	Sections []string `json:"sections"`
+	// Höchstzahl an Zeichen je Plattform (Titel und Text); längere Posts werden gekürzt, 0 = unbegrenzt
+	CharLimits map[string]int `json:"char_limits"`
+	// Textformat je Plattform: "markdown" (z.B. Tabellen für Lemmy) oder "plain"; ohne Eintrag reiner Text
+	TextFormats map[string]string `json:"text_formats"`
+	// Sprache der Posts: "de" (Deutsch) oder "en" (Englisch)
+	Language string `json:"language"`
+	// Einheiten der Posts: "metric" oder "imperial", einzelne Messgrößen abweichend, z.B. {"rain": "mm"}
+	Units         string            `json:"units"`
+	UnitOverrides map[string]string `json:"unit_overrides"`
+	// Konsolenausgabe des Tageslaufs: "text" (Übersicht) oder "json" (alle berechneten Werte auf stdout)
+	OutputFormat string `json:"output_format"`
+
+	// Bestenlisten ("Messgröße:Zeitraum[:Anzahl]"), die am Monatsersten veröffentlicht werden, und ihr Template
+	TopLists        []string `json:"top_lists"`
+	TopListTemplate string   `json:"top_list_template"`
+
+	// Externe JSON-Datenquellen (Name → URL) für Templates und ihre Cache-Dauer in Minuten
+	ExternalData             map[string]string `json:"external_data"`
+	ExternalDataCacheMinutes int               `json:"external_data_cache_minutes"`
+
+	// Vergleich mit einer DWD-Station (Open Data, Tageswerte KL): Stationskennung (z.B. "02667"), Anzeigename, Basis-URL
+	DWDStationID   string `json:"dwd_station_id"`
+	DWDStationName string `json:"dwd_station_name"`
+	DWDBaseURL     string `json:"dwd_base_url"`
+
+	// Webhook: URL für JSON-Payload und festgelegte Schema-Version (0 = aktuelle Version)
+	WebhookURL           string `json:"webhook_url"`
+	WebhookSchemaVersion int    `json:"webhook_schema_version"`
+
+	// Lokaler Zustandsspeicher (SQLite) für QC-Ereignisse und Berichte
+	StorePath string `json:"store_path"`
+	// Speicherlimit (MB, 0 = unbegrenzt) und Blockgröße (Archivzeilen, 0 = aus dem Limit abgeleitet)
+	// für Import und Auswertungen über das gesamte Archiv
+	MemoryLimitMB int `json:"memory_limit_mb"`
+	ScanChunkRows int `json:"scan_chunk_rows"`
+	// Mindestlänge der Aufzeichnung in Tagen, bevor Stationsrekorde gezählt werden
+	RecordMinHistoryDays int `json:"record_min_history_days"`
+	// Mindestzahl an Jahren mit Daten für Rekorde am selben Kalendertag bzw. im selben Kalendermonat, 0 = aus
+	CalendarDayRecordMinYears   int `json:"calendar_day_record_min_years"`
+	CalendarMonthRecordMinYears int `json:"calendar_month_record_min_years"`
+	// Admin-Kanal: Mastodon-Account, der Betriebsberichte als Direktnachricht erhält
+	AdminMastodonAccount string `json:"admin_mastodon_account"`
+
+	// Name und Lage der Station für Titel und Templates, z.B. "Overath" und "Bergisches Land"
+	StationName     string `json:"station_name"`
+	StationLocation string `json:"station_location"`
+	// Links auf die Detailseiten je Post-Art (daily, weekly, monthly, yearly); leer = kein Link
+	DetailURLs map[string]string `json:"detail_urls"`
+
+	// Geographische Breite der Station (Grad), z.B. für die Verdunstungsberechnung
+	Latitude float64 `json:"latitude"`
+	// Geographische Länge der Station (Grad, Osten positiv) für Sonnenauf- und -untergang
+	Longitude float64 `json:"longitude"`
+	// Sonnenaufgang, -untergang und Tageslänge im Vergleich zur Vorwoche im Post
+	SunTimesEnabled bool `json:"sun_times_enabled"`
+	// Temperaturdiagramm des Tages als Bild am Tagespost (Lemmy und Mastodon)
+	TemperatureChart bool `json:"temperature_chart"`
+	// Balkendiagramm des täglichen Niederschlags als Bild an Zeitraum-Zusammenfassungen (-from/-to mit -post)
+	RainChart bool `json:"rain_chart"`
+	// Windrose des Tages als Bild am Tagespost, sofern die Windabschnitte im Post erscheinen (sections)
+	WindRose bool `json:"wind_rose"`
+	// Ein kombiniertes Tagesdiagramm (Temperatur, Niederschlag, Sonnenstrahlung, Wind) statt der Einzelbilder
+	CombinedChart bool `json:"combined_chart"`
+	// Klimadiagramm nach Walter und Lieth am Jahresrückblick und an Zeitraum-Zusammenfassungen (letzte 12 Monate)
+	ClimateDiagram bool `json:"climate_diagram"`
+	// Verzeichnis, in das die Diagramme zusätzlich als SVG geschrieben werden (z.B. für eine Webseite); leer = keine SVG
+	ChartDir string `json:"chart_dir"`
+	// Jahreskalender am Jahresrückblick: "temperature" (Tagesmitteltemperatur), "rain" (Niederschlag), leer = keiner
+	YearHeatmap string `json:"year_heatmap"`
+	// Aussehen der Diagramme: hell, dunkel oder automatisch, Größe, Schrift und Farben
+	ChartStyle ChartStyle `json:"chart_style"`
+	// Bilder des weewx-Report-Generators statt der eigenen Tagesdiagramme, sofern sie aktuell sind (Minuten)
+	SkinImageDir    string      `json:"skin_image_dir"`
+	SkinImages      []SkinImage `json:"skin_images"`
+	SkinImageMaxAge int         `json:"skin_image_max_age"`
+	// Diagramme aus Grafana (Render-API) für Tagespost, Zeitraum-Zusammenfassung und Jahresrückblick
+	GrafanaPanels []GrafanaPanel `json:"grafana_panels"`
+	GrafanaAPIKey string         `json:"grafana_api_key"`
+	// Übersichtskarte mit den Tageswerten in großen Zahlen als Vorschaubild des Lemmy-Tagesposts
+	SummaryCard bool `json:"summary_card"`
+	// Animiertes Tagesdiagramm (ein Teildiagramm je Bild) statt der Tagesdiagramme für diese Plattformen ("lemmy",
+	// "mastodon"); Teildiagramme in der Reihenfolge der Bilder und Anzeigedauer je Bild (Sekunden)
+	AnimatedChartPublishers []string `json:"animated_chart_publishers"`
+	AnimatedChartFrames     []string `json:"animated_chart_frames"`
+	AnimatedChartDelay      float64  `json:"animated_chart_delay"`
+	// Aktuelle Mondphase mit nächstem Vollmond und Neumond im Post
+	MoonPhaseEnabled bool `json:"moon_phase_enabled"`
+
+	// Luftdruck im Tagesverlauf: Vergleich der Mittelwerte im Morgen- und Abendfenster (Stunden, Ortszeit, Ende
+	// ausschließlich) und Tendenz über die letzten Stunden des Tages
+	PressureMorningFrom     int     `json:"pressure_morning_from"`
+	PressureMorningTo       int     `json:"pressure_morning_to"`
+	PressureEveningFrom     int     `json:"pressure_evening_from"`
+	PressureEveningTo       int     `json:"pressure_evening_to"`
+	PressureTendencyHours   int     `json:"pressure_tendency_hours"`
+	PressureSteadyThreshold float64 `json:"pressure_steady_threshold"` // hPa – kleinere Änderungen gelten als gleichbleibend
+	// 72-Stunden-Luftdruckdiagramm am Tagespost bei Sturmtagen oder einer Luftdruckänderung ab der Schwelle (hPa)
+	PressureChart          bool    `json:"pressure_chart"`
+	PressureChartThreshold float64 `json:"pressure_chart_threshold"`
+
+	SunThreshold       float64 `json:"sun_threshold"`        // W/m² – Strahlung ab der ein Intervall bzw. eine Stunde als sonnig zählt
+	DrySpellThreshold  int     `json:"dry_spell_threshold"`  // Tage ohne Regen bzw. mit Regen in Folge für einen Hinweis im Post
+	HeavyRainThreshold float64 `json:"heavy_rain_threshold"` // mm – Tagesniederschlag ab dem ein Tag als Starkregentag erwähnt wird, 0 = aus
+
+	MuggyDewpoint float64 `json:"muggy_dewpoint"`  // °C – Taupunkt ab dem ein Tag als schwül gilt
+	FeltTempDelta float64 `json:"felt_temp_delta"` // °C – Mindestabweichung der gefühlten Temperatur für einen Hinweis
+
+	// Böenfaktor: Mindestwindgeschwindigkeit (km/h) für eine Angabe und Schwelle für "ungewöhnlich böig"
+	GustFactorMinWind   float64 `json:"gust_factor_min_wind"`
+	TurbulentGustFactor float64 `json:"turbulent_gust_factor"`
+	StormBeaufort       int     `json:"storm_beaufort"` // Windstärke der Spitzenböe, ab der ein Tag als Sturmtag gilt (7–12)
+
+	// Tropennacht: Nachtfenster (Stunden, Vortag bis Tag) und Mindesttemperatur
+	TropicalNightFrom int     `json:"tropical_night_from"`
+	TropicalNightTo   int     `json:"tropical_night_to"`
+	TropicalNightTemp float64 `json:"tropical_night_temp"`
+
+	// Schnee: Spalten für Schneehöhe und Neuschnee (cm) in archive, Beginn der Schneesaison (MM-DD)
+	SnowDepthColumn string `json:"snow_depth_column"`
+	SnowfallColumn  string `json:"snowfall_column"`
+	SnowSeasonStart string `json:"snow_season_start"`
+
+	// Blitzsensor: Spalten für Anzahl und Entfernung (km), Mindestanzahl für eine Erwähnung
+	LightningCountColumn    string `json:"lightning_count_column"`
+	LightningDistanceColumn string `json:"lightning_distance_column"`
+	LightningMinStrikes     int    `json:"lightning_min_strikes"`
+
+	// Bodensensor: Spalten für Bodentemperatur (°C) und Bodenfeuchte (cb) in archive
+	SoilTempColumn  string `json:"soil_temp_column"`
+	SoilMoistColumn string `json:"soil_moist_column"`
+
+	// Blattnässesensor: Spalte in archive (0 = trocken bis 15 = nass) und Schwelle, ab der Blätter als nass gelten
+	LeafWetColumn    string  `json:"leaf_wet_column"`
+	LeafWetThreshold float64 `json:"leaf_wet_threshold"`
+
+	// Spätfrostgefahr: Saison (MM-DD) und Tiefstwert in °C, bis zu dem nasse Blätter gefährdet sind
+	LateFrostSeasonStart string  `json:"late_frost_season_start"`
+	LateFrostSeasonEnd   string  `json:"late_frost_season_end"`
+	LateFrostTemp        float64 `json:"late_frost_temp"`
+
+	// Feinstaubsensor: Spalten für PM2.5 und PM10 (µg/m³) in archive
+	PM25Column string `json:"pm25_column"`
+	PM10Column string `json:"pm10_column"`
+
+	// Quelle der Tageswerte: "weewx" (archive_day_*) oder "archive" (neu berechnet, siehe -rebuild-daily)
+	DailySource string `json:"daily_source"`
+
+	// Mindestabstand in Sekunden zwischen Posts an denselben Server beim Nachholen (-backfill)
+	BackfillIntervalSeconds int `json:"backfill_interval_seconds"`
+
+	// Höchstzahl an API-Aufrufen je Plattform ("lemmy", "mastodon", "webhook") und Kalendertag, 0 = unbegrenzt
+	APIBudget map[string]int `json:"api_budget"`
+	// Datei für endgültig gescheiterte Posts (JSON Lines), erneut senden mit -resend; leer = keine Ablage
+	DeadLetterFile string `json:"dead_letter_file"`
+
+	// Geschätzte Wolkenbasis aus dem Spread zwischen Temperatur und Taupunkt im Post angeben
+	CloudBaseEnabled bool `json:"cloud_base_enabled"`
+	// Temperaturverlauf des Tages als Sparkline aus Blockzeichen (24 Stundenmittel) im Post
+	SparklineEnabled bool `json:"sparkline_enabled"`
+
+	// Jahresrückblick auf das Vorjahr am 1. Januar posten
+	YearReviewEnabled bool `json:"year_review_enabled"`
+
+	// Rückblick auf jede Jahreszeit an deren Ende: "meteorological" oder "astronomical",
+	// season_starts (MM-DD für Frühling, Sommer, Herbst, Winter) hat Vorrang
+	SeasonSummaryEnabled bool     `json:"season_summary_enabled"`
+	SeasonType           string   `json:"season_type"`
+	SeasonStarts         []string `json:"season_starts"`
+	// Sonderpost zu Tagundnachtgleichen und Sonnenwenden mit Rückblick auf das Vierteljahr
+	CardinalPointPostsEnabled bool `json:"cardinal_point_posts_enabled"`
+
+	// Sonnenschein wie früher als Stunden mit Sonnenschein statt als Sonnenscheindauer angeben (zur Vergleichbarkeit)
+	SunshineLegacyHours bool `json:"sunshine_legacy_hours"`
+
+	// Plattformen ("lemmy", "mastodon", "webhook"), deren Posts einen Abschnitt zum Innenraumklima erhalten
+	IndoorPublishers []string `json:"indoor_publishers"`
+
+	// Hinweisregeln (z.B. UV-Warnung): Messgröße, Vergleich, Schwelle und Text
+	AdvisoryRules []AdvisoryRule `json:"advisory_rules"`
+	// Regeln für den Satz, der den Tag am Anfang des Posts charakterisiert; leere Liste = kein Satz
+	DaySummaryRules []DaySummaryRule `json:"day_summary_rules"`
+
+	RainDayStartHour int    `json:"rain_day_start_hour"` // Beginn des Niederschlagstags (0 = Mitternacht, 9 = Beobachtungstag 09–09 Uhr)
+	RainDayCredit    string `json:"rain_day_credit"`     // Tag, dem ein Beobachtungstag zählt: "start" (wie beim DWD) oder "end"
+
+	// Hinweis zur Niederschlagsmessung im Winter: in festen Zeiträumen und/oder bei Tiefstwerten unter einer Temperatur
+	RainCaveatText        string      `json:"rain_caveat_text"`
+	RainCaveatPeriods     []DateRange `json:"rain_caveat_periods"`
+	RainCaveatTempEnabled bool        `json:"rain_caveat_temp_enabled"`
+	RainCaveatBelowTemp   float64     `json:"rain_caveat_below_temp"`
+
+	// Laufende Monatswerte (Niederschlag, Sonnenschein, Mitteltemperatur) im Post
+	MonthToDateEnabled bool `json:"month_to_date_enabled"`
+	// Rückblick auf denselben Kalendertag der Vorjahre ab so vielen Jahren mit Daten, 0 = aus
+	OnThisDayMinYears int `json:"on_this_day_min_years"`
+	// Am Monatsende Rang des Monats unter denselben Monaten der Vorjahre ab so vielen Jahren mit Daten, 0 = aus
+	MonthRankingMinYears int `json:"month_ranking_min_years"`
+	// Wärmster und kältester Tag des laufenden Monats mit Datum im Post
+	MonthExtremesEnabled bool `json:"month_extremes_enabled"`
+	// Referenz-Klimatologie: Datei mit Monatsmitteln der Temperatur (z.B. DWD 1991–2020) und Stationskennung darin
+	NormalsFile    string `json:"normals_file"`
+	NormalsStation string `json:"normals_station"`
+	// Tagesmittel im Vergleich zum Mittel der vorangegangenen Tage, 0 = aus
+	AnomalyDays int `json:"anomaly_days"`
+	// Einordnung von Höchstwert und Niederschlag unter alle Tage des Kalendermonats: nur oberste/unterste X %, 0 = aus
+	PercentileThreshold float64 `json:"percentile_threshold"`
+	// Niederschlag seit Jahresbeginn mit Vergleich zum Vorjahr im Post
+	YearToDateRainEnabled bool `json:"year_to_date_rain_enabled"`
+	// Beginn des hydrologischen Jahres (MM-DD) für die Niederschlagssumme im Abflussjahr, leer = aus
+	RainYearStart string `json:"rain_year_start"`
+	// Hitzeserie: Hinweis "X. Hitzetag in Folge" ab zwei Tagen mit Höchstwert von mindestens heat_streak_threshold °C
+	HeatStreakEnabled   bool    `json:"heat_streak_enabled"`
+	HeatStreakThreshold float64 `json:"heat_streak_threshold"`
+	// Frostserie: Hinweis "Es friert seit X Nächten" ab so vielen Tagen in Folge mit Tiefstwert unter 0 °C, 0 = aus
+	FrostStreakThreshold int `json:"frost_streak_threshold"`
+	// Erster Frost im Herbst und letzter Frost im Frühjahr (gemeldet am Stichtag MM-DD), Beginn der Frostsaison (MM-DD)
+	FrostDatesEnabled     bool   `json:"frost_dates_enabled"`
+	FrostSeasonStart      string `json:"frost_season_start"`
+	LastFrostAnnounceDate string `json:"last_frost_announce_date"`
+	// Höchstens so viele Tage rückwärts nach dem letzten Regen suchen, 0 = unbegrenzt
+	DrySpellMaxDays int `json:"dry_spell_max_days"`
+
+	// Wachstumsgradtage: Basistemperatur und Wachstumssaison (MM-DD)
+	GDDEnabled     bool    `json:"gdd_enabled"`
+	GDDBaseTemp    float64 `json:"gdd_base_temp"`
+	GDDSeasonStart string  `json:"gdd_season_start"`
+	GDDSeasonEnd   string  `json:"gdd_season_end"`
+
+	// Kältestunden für Obstbau: Temperaturbereich und Ruheperiode (MM-DD)
+	ChillHoursEnabled bool    `json:"chill_hours_enabled"`
+	ChillMinTemp      float64 `json:"chill_min_temp"`
+	ChillMaxTemp      float64 `json:"chill_max_temp"`
+	ChillSeasonStart  string  `json:"chill_season_start"`
+	ChillSeasonEnd    string  `json:"chill_season_end"`
+
+	// Heizgradtage (Gradtagzahl Innentemperatur/Heizgrenze) und Kühlgradtage, jeweils mit Saison (MM-DD)
+	HDDEnabled      bool    `json:"hdd_enabled"`
+	HDDIndoorTemp   float64 `json:"hdd_indoor_temp"`
+	HDDHeatingLimit float64 `json:"hdd_heating_limit"`
+	HDDSeasonStart  string  `json:"hdd_season_start"`
+	HDDSeasonEnd    string  `json:"hdd_season_end"`
+	CDDEnabled      bool    `json:"cdd_enabled"`
+	CDDBaseTemp     float64 `json:"cdd_base_temp"`
+	CDDSeasonStart  string  `json:"cdd_season_start"`
+	CDDSeasonEnd    string  `json:"cdd_season_end"`
+	// Basistemperatur der Heizgradtage im CSV-Export (-export degree-days)
+	ExportHDDBaseTemp float64 `json:"export_hdd_base_temp"`
 }
 
 // LemmyLoginResponse ist die Antwortstruktur für den Lemmy-Login
@@ -45,9 +310,42 @@ type LemmyLoginResponse struct {
 type dayStats struct {
 	tMax, tMin, rainSum float64
 	sunHours            int
+	pressure            pressureStats
+	dewMin, dewMax      float64
+	heatIndexMax        float64
+	windChillMin        float64
+	windRun             float64 // km
+	gustMax, windMean   float64 // km/h
+	gustTime            int64   // Zeitpunkt der Spitzenböe (Unix-Zeit, 0 = unbekannt)
+	leafWetHours        float64 // h mit nassen Blättern, NaN ohne Blattnässesensor
+	nightMin            float64 // Tiefstwert der Nacht zum Tag (Tropennacht)
+	snowFall, snowDepth float64 // cm, NaN ohne Schneesensor
+	et                  float64 // mm, Verdunstung
+	lightningStrikes    float64 // Anzahl, NaN ohne Blitzsensor
+	lightningDistance   float64 // km, geringste Entfernung
+	soilTempMin         float64 // °C, NaN ohne Bodensensor
+	soilTempMax         float64
+	soilMoistMin        float64 // cb (Saugspannung)
+	soilMoistMax        float64
+	pm25Mean, pm25Max   float64 // µg/m³, NaN ohne Feinstaubsensor
+	pm10Mean, pm10Max   float64 // µg/m³
+	rainRateMax         float64 // mm/h, NaN ohne rainRate
+	heaviestHour        int     // Stunde mit dem meisten Regen (Beginn, Ortszeit), -1 ohne Regen
+	heaviestHourRain    float64 // mm in dieser Stunde
+	rainHours           int     // Anzahl der Stunden mit Regen
+	rainMinutes         float64 // Regendauer in Minuten
+	uvMax               float64 // höchster UV-Index, NaN ohne UV-Sensor
+	tMaxTime, tMinTime  int64   // Zeitpunkte von Höchst- und Tiefstwert (Unix-Zeit, 0 = unbekannt)
+	inTempMin           float64 // °C innen, NaN ohne Innensensor
+	inTempMax           float64
+	inHumMin, inHumMax  float64 // % innen
+	cloudBaseMin        float64 // m, geschätzte Wolkenbasis (125 m je K Spread)
+	cloudBaseMax        float64
+	solarEnergy         float64 // kWh/m², eingestrahlte Sonnenenergie
+	sunMinutes          float64 // Sonnenscheindauer in Minuten, NaN ohne Strahlungswerte
 }
 
-func getStats(db *sql.DB, loc *time.Location, start, end int64) (dayStats, error) {
+func getStats(db *sql.DB, loc *time.Location, config Config, start, end int64) (dayStats, error) {
 	var s dayStats
 
 	// 1) Tagesmax/min
@@ -78,29 +376,41 @@ func getStats(db *sql.DB, loc *time.Location, start, end int64) (dayStats, error
 	dayStart := time.Unix(start, 0).In(loc)
 	dayStart = time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), 0, 0, 0, 0, loc)
 	dayStartUnix := dayStart.Unix()
-	
-	const qRain = `SELECT sum FROM archive_day_rain WHERE dateTime = ?;`
-	var rainSum sql.NullFloat64
-	if err := db.QueryRow(qRain, dayStartUnix).Scan(&rainSum); err != nil {
-		// Fallback: Versuche alternative Abfrage mit Zeitraum
-		const qRainFallback = `SELECT sum FROM archive_day_rain WHERE dateTime >= ? AND dateTime < ?;`
-		if err := db.QueryRow(qRainFallback, start, end).Scan(&rainSum); err != nil {
-			s.rainSum = 0
-			fmt.Fprintf(os.Stderr, "Warnung: Tagesregenmenge (archive_day_rain.sum) nicht gefunden für Tag %s (Zeitstempel: %d)\n", dayStart.Format("2006-01-02"), dayStartUnix)
+
+	if config.DailySource != dailySourceArchive {
+		const qRain = `SELECT sum FROM archive_day_rain WHERE dateTime = ?;`
+		var rainSum sql.NullFloat64
+		if err := db.QueryRow(qRain, dayStartUnix).Scan(&rainSum); err != nil {
+			// Fallback: Versuche alternative Abfrage mit Zeitraum
+			const qRainFallback = `SELECT sum FROM archive_day_rain WHERE dateTime >= ? AND dateTime < ?;`
+			if err := db.QueryRow(qRainFallback, start, end).Scan(&rainSum); err != nil {
+				s.rainSum = 0
+				fmt.Fprintf(os.Stderr, "Warnung: Tagesregenmenge (archive_day_rain.sum) nicht gefunden für Tag %s (Zeitstempel: %d)\n", dayStart.Format("2006-01-02"), dayStartUnix)
+			} else if rainSum.Valid {
+				// Korrektur: Regenmengen müssen mit 10 multipliziert werden
+				s.rainSum = rainSum.Float64 * 10.0
+			} else {
+				s.rainSum = 0
+				fmt.Fprintf(os.Stderr, "Warnung: Tagesregenmenge (archive_day_rain.sum) ist NULL für Tag %s\n", dayStart.Format("2006-01-02"))
+			}
 		} else if rainSum.Valid {
 			// Korrektur: Regenmengen müssen mit 10 multipliziert werden
+			// Das Rain Gauge misst in 0.1mm Schritten, aber die DB speichert in 0.01mm
 			s.rainSum = rainSum.Float64 * 10.0
 		} else {
 			s.rainSum = 0
 			fmt.Fprintf(os.Stderr, "Warnung: Tagesregenmenge (archive_day_rain.sum) ist NULL für Tag %s\n", dayStart.Format("2006-01-02"))
 		}
-	} else if rainSum.Valid {
-		// Korrektur: Regenmengen müssen mit 10 multipliziert werden
-		// Das Rain Gauge misst in 0.1mm Schritten, aber die DB speichert in 0.01mm
-		s.rainSum = rainSum.Float64 * 10.0
-	} else {
-		s.rainSum = 0
-		fmt.Fprintf(os.Stderr, "Warnung: Tagesregenmenge (archive_day_rain.sum) ist NULL für Tag %s\n", dayStart.Format("2006-01-02"))
+	}
+
+	// Abweichender Beobachtungstag (z.B. 09–09 Uhr) oder ohne weewx-Tageswerte: Summe direkt aus archive
+	if config.RainDayStartHour != 0 || config.DailySource == dailySourceArchive {
+		rainStart, rainEnd := rainDayWindow(loc, config, dayStart)
+		rain, err := archiveRainSum(db, rainStart, rainEnd)
+		if err != nil {
+			return s, err
+		}
+		s.rainSum = rain
 	}
 
 	// 3) Sonnenstunden: Berechne durchschnittliche Sonneneinstrahlung pro Stunde
@@ -116,6 +426,7 @@ func getStats(db *sql.DB, loc *time.Location, start, end int64) (dayStats, error
 
 	// Sammle alle Messwerte pro Stunde
 	hourlyData := make(map[int][]float64)
+	hourlyRain := make(map[int]float64)
 
 	for rows.Next() {
 		var ts int64
@@ -125,6 +436,9 @@ func getStats(db *sql.DB, loc *time.Location, start, end int64) (dayStats, error
 			return s, err
 		}
 		h := time.Unix(ts, 0).In(loc).Hour()
+		if rain.Valid {
+			hourlyRain[h] += rain.Float64 * 10.0 // cm → mm
+		}
 		if maxSolarRad.Valid {
 			hourlyData[h] = append(hourlyData[h], maxSolarRad.Float64)
 		}
@@ -143,28 +457,349 @@ func getStats(db *sql.DB, loc *time.Location, start, end int64) (dayStats, error
 				sum += val
 			}
 			avg := sum / float64(len(values))
-			if avg >= sunThreshold {
+			if avg >= config.SunThreshold {
 				sunHours++
 			}
 		}
 	}
 
 	s.sunHours = sunHours
+
+	// Regenreichste Stunde (bei gleicher Menge die frühere) und Anzahl der Stunden mit Regen
+	s.heaviestHour, s.heaviestHourRain = -1, 0
+	for h := 0; h < 24; h++ {
+		if hourlyRain[h] > 0 {
+			s.rainHours++
+		}
+		if hourlyRain[h] > s.heaviestHourRain {
+			s.heaviestHour, s.heaviestHourRain = h, hourlyRain[h]
+		}
+	}
+
+	// 4) Luftdruck und Tendenz
+	pressure, err := getPressureStats(db, loc, config, start, end)
+	if err != nil {
+		return s, err
+	}
+	s.pressure = pressure
+
+	// 5) Taupunkt-Extremwerte
+	s.dewMin, s.dewMax, err = getDewpointExtremes(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 6) Gefühlte Temperatur (Hitzeindex/Windchill)
+	s.heatIndexMax, s.windChillMin, err = getApparentExtremes(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 7) Windweg
+	s.windRun, err = getWindRun(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 8) Spitzenböe und mittlerer Wind (Böenfaktor)
+	s.gustMax, s.windMean, err = getWindExtremes(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 9) Tiefstwert der vorangegangenen Nacht
+	s.nightMin, err = getNightMin(db, loc, config, time.Unix(start, 0).In(loc))
+	if err != nil {
+		return s, err
+	}
+
+	// 10) Neuschnee und Schneehöhe (nur mit entsprechenden Spalten)
+	s.snowFall, s.snowDepth, err = getSnowStats(db, config, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 11) Verdunstung (ET)
+	s.et, err = dailyET(db, config, time.Unix(start, 0).In(loc))
+	if err != nil {
+		return s, err
+	}
+
+	// 12) Blitze (nur mit Blitzsensor)
+	s.lightningStrikes, s.lightningDistance, err = getLightningStats(db, config, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 13) Boden (nur mit Bodensensor)
+	s.soilTempMin, s.soilTempMax, s.soilMoistMin, s.soilMoistMax, err = getSoilStats(db, config, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 14) Feinstaub (nur mit Feinstaubsensor)
+	s.pm25Mean, s.pm25Max, s.pm10Mean, s.pm10Max, err = getAirQuality(db, config, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 15) Maximale Regenrate
+	s.rainRateMax, err = getRainRateMax(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 16) Sonnenenergie
+	s.solarEnergy, err = getSolarEnergy(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 17) Sonnenscheindauer aus den Archivintervallen
+	s.sunMinutes, err = getSunshineMinutes(db, config, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 18) Regendauer aus den Archivintervallen
+	s.rainMinutes, err = getRainMinutes(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 19) UV-Index (nur mit UV-Sensor)
+	_, s.uvMax, err = columnExtremes(db, "UV", start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 20) Uhrzeiten von Höchst- und Tiefstwert
+	s.tMaxTime, s.tMinTime, err = getExtremeTimes(db, config, dayStartUnix, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 21) Innenraum
+	s.inTempMin, s.inTempMax, s.inHumMin, s.inHumMax, err = getIndoorStats(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 22) Geschätzte Wolkenbasis
+	s.cloudBaseMin, s.cloudBaseMax, err = getCloudBase(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 23) Zeitpunkt der Spitzenböe
+	s.gustTime, err = getGustTime(db, start, end)
+	if err != nil {
+		return s, err
+	}
+
+	// 24) Blattnässe
+	s.leafWetHours, err = getLeafWetHours(db, config, start, end)
+	if err != nil {
+		return s, err
+	}
 	return s, nil
 }
 
 // DefaultConfig gibt die Standard-Konfiguration zurück
 func DefaultConfig() Config {
 	return Config{
-		LemmyServer:        "https://natur.23.nu",
-		LemmyCommunity:     "wetter",
-		LemmyUsername:      "wetterbot",
-		LemmyPassword:      "CHANGEME",
-		LemmyToken:         "",
-		LemmyTokenExp:      time.Time{},
-		MastodonServer:     "",
-		MastodonToken:      "",
-		MastodonVisibility: "unlisted",
+		LemmyServer:          "https://natur.23.nu",
+		LemmyCommunity:       "wetter",
+		LemmyUsername:        "wetterbot",
+		LemmyPassword:        "CHANGEME",
+		LemmyToken:           "",
+		LemmyTokenExp:        time.Time{},
+		MastodonServer:       "",
+		MastodonToken:        "",
+		MastodonVisibility:   "unlisted",
+		MastodonVisibilities: map[string]string{},
+		MastodonLanguage:     "",
+		MastodonMediaTimeout: 60,
+		ImageLimits: map[string]ImageLimit{
+			platformMastodon: {MaxBytes: 8 << 20, MaxWidth: 3840, MaxHeight: 3840},
+			platformLemmy:    {MaxBytes: 10 << 20, MaxWidth: 10000, MaxHeight: 10000},
+		},
+		ContentWarnings:    map[string]string{},
+		TitleTemplate:      "",
+		PublisherTemplates: map[string]PublisherTemplate{},
+		Sections:           nil,
+		CharLimits:         map[string]int{platformMastodon: 500},
+		TextFormats:        map[string]string{platformLemmy: textFormatMarkdown, platformMastodon: textFormatPlain},
+		Language:           languageGerman,
+		Units:              unitsMetric,
+		UnitOverrides:      map[string]string{},
+		OutputFormat:       outputFormatText,
+
+		TopLists:        []string{},
+		TopListTemplate: "",
+
+		ExternalData:             map[string]string{},
+		ExternalDataCacheMinutes: 360,
+
+		DWDBaseURL: "https://opendata.dwd.de/climate_environment/CDC/observations_germany/climate/daily/kl/recent/",
+
+		WebhookURL:           "",
+		WebhookSchemaVersion: 0,
+
+		BackfillIntervalSeconds: 60,
+
+		APIBudget:      defaultAPIBudget(),
+		DeadLetterFile: "deadletter.jsonl",
+
+		StorePath:            "wetterstatistik.sdb",
+		AdminMastodonAccount: "",
+		RecordMinHistoryDays: 365,
+		MemoryLimitMB:        0,
+		ScanChunkRows:        0,
+
+		CalendarDayRecordMinYears:   10,
+		CalendarMonthRecordMinYears: 3,
+
+		StationName:     "Overath",
+		StationLocation: "",
+		DetailURLs:      map[string]string{detailDaily: "https://groloe.wetter.foxel.org/week.html"},
+
+		Latitude:         50.93,
+		Longitude:        7.27,
+		SunTimesEnabled:  true,
+		MoonPhaseEnabled: true,
+		TemperatureChart: false,
+		RainChart:        false,
+		WindRose:         false,
+		CombinedChart:    false,
+		ClimateDiagram:   false,
+		ChartDir:         "",
+		YearHeatmap:      "",
+		ChartStyle: ChartStyle{
+			Mode: chartModeLight, Width: 800, Height: 400, FontScale: 2, FontFamily: "monospace",
+			Colors: map[string]string{}, DarkColors: map[string]string{},
+		},
+		SkinImageDir:    "/var/www/html/weewx",
+		SkinImages:      []SkinImage{},
+		SkinImageMaxAge: 60,
+		GrafanaPanels:   []GrafanaPanel{},
+		GrafanaAPIKey:   "",
+		SummaryCard:     false,
+
+		AnimatedChartPublishers: []string{},
+		AnimatedChartFrames:     []string{dailyPanelTemperature, dailyPanelRain, dailyPanelWind},
+		AnimatedChartDelay:      3,
+
+		PressureMorningFrom:     6,
+		PressureMorningTo:       9,
+		PressureEveningFrom:     18,
+		PressureEveningTo:       21,
+		PressureTendencyHours:   3,
+		PressureSteadyThreshold: 1.0,
+		PressureChart:           false,
+		PressureChartThreshold:  10,
+
+		SunThreshold:       120.0,
+		DrySpellThreshold:  3,
+		HeavyRainThreshold: 20.0,
+
+		MuggyDewpoint: 18.0,
+		FeltTempDelta: 2.0,
+
+		GustFactorMinWind:   5.0,
+		TurbulentGustFactor: 3.0,
+		StormBeaufort:       8,
+
+		TropicalNightFrom: 18,
+		TropicalNightTo:   6,
+		TropicalNightTemp: 20.0,
+
+		SnowDepthColumn: "snowDepth",
+		SnowfallColumn:  "snow",
+		SnowSeasonStart: "07-01",
+
+		LightningCountColumn:    "lightning_strike_count",
+		LightningDistanceColumn: "lightning_distance",
+		LightningMinStrikes:     5,
+
+		SoilTempColumn:  "soilTemp1",
+		SoilMoistColumn: "soilMoist1",
+
+		LeafWetColumn:    "leafWet1",
+		LeafWetThreshold: 8,
+
+		LateFrostSeasonStart: "03-15",
+		LateFrostSeasonEnd:   "05-31",
+		LateFrostTemp:        2.0,
+
+		PM25Column: "pm2_5",
+		PM10Column: "pm10_0",
+
+		DailySource: dailySourceWeewx,
+
+		YearReviewEnabled: true,
+
+		SeasonSummaryEnabled: true,
+		SeasonType:           seasonsMeteorological,
+
+		CardinalPointPostsEnabled: true,
+
+		SunshineLegacyHours: false,
+
+		IndoorPublishers: []string{},
+
+		AdvisoryRules:   defaultAdvisoryRules(),
+		DaySummaryRules: defaultDaySummaryRules(),
+
+		RainDayStartHour: 0,
+		RainDayCredit:    rainDayCreditStart,
+
+		RainCaveatText:        "Schnee wird vom unbeheizten Messer nicht erfasst.",
+		RainCaveatPeriods:     []DateRange{},
+		RainCaveatTempEnabled: false,
+		RainCaveatBelowTemp:   1.0,
+
+		MonthToDateEnabled:    true,
+		YearToDateRainEnabled: true,
+		AnomalyDays:           30,
+
+		PercentileThreshold: 5,
+
+		HeatStreakEnabled:   true,
+		HeatStreakThreshold: 30,
+
+		RainYearStart: "11-01",
+
+		FrostStreakThreshold: 3,
+		OnThisDayMinYears:    5,
+		MonthRankingMinYears: 3,
+
+		FrostDatesEnabled:     true,
+		FrostSeasonStart:      "07-01",
+		LastFrostAnnounceDate: "05-15",
+
+		GDDEnabled:     true,
+		GDDBaseTemp:    10.0,
+		GDDSeasonStart: "04-01",
+		GDDSeasonEnd:   "10-31",
+
+		ChillHoursEnabled: false,
+		ChillMinTemp:      0.0,
+		ChillMaxTemp:      7.2,
+		ChillSeasonStart:  "11-01",
+		ChillSeasonEnd:    "03-15",
+
+		HDDEnabled:      true,
+		HDDIndoorTemp:   20.0,
+		HDDHeatingLimit: 15.0,
+		HDDSeasonStart:  "10-01",
+		HDDSeasonEnd:    "04-30",
+		CDDEnabled:      false,
+		CDDBaseTemp:     18.0,
+		CDDSeasonStart:  "06-01",
+		CDDSeasonEnd:    "08-31",
+
+		ExportHDDBaseTemp: 15.5,
 	}
 }
 
@@ -175,10 +810,76 @@ func loadConfig(configFile string) (Config, error) {
 	if configFile != "" {
 		data, err := os.ReadFile(configFile)
 		if err == nil {
+			// Vorbelegte Listen leeren: json.Unmarshal würde sonst Felder der Standardeinträge übernehmen
+			config.AdvisoryRules = nil
+			config.DaySummaryRules = nil
 			err = json.Unmarshal(data, &config)
 			if err != nil {
 				return config, fmt.Errorf("Fehler beim Parsen der Konfigurationsdatei: %v", err)
 			}
+			if config.AdvisoryRules == nil {
+				config.AdvisoryRules = defaultAdvisoryRules()
+			}
+			if config.DaySummaryRules == nil {
+				config.DaySummaryRules = defaultDaySummaryRules()
+			}
+			if !validLanguage(config.Language) {
+				return config, fmt.Errorf("unbekannte Sprache %q (language: de, en)", config.Language)
+			}
+			if err := validateUnits(config); err != nil {
+				return config, err
+			}
+			if err := validateTextFormats(config); err != nil {
+				return config, err
+			}
+			if err := validateSections(config); err != nil {
+				return config, err
+			}
+			if err := validateDetailURLs(config); err != nil {
+				return config, err
+			}
+			if config.SunThreshold <= 0 || config.DrySpellThreshold < 1 || config.HeavyRainThreshold < 0 {
+				return config, fmt.Errorf("ungültige Schwellwerte (sun_threshold > 0, dry_spell_threshold >= 1, heavy_rain_threshold >= 0)")
+			}
+			if err := validateMastodonSettings(config); err != nil {
+				return config, err
+			}
+			if err := validateChartStyle(config); err != nil {
+				return config, err
+			}
+			if err := validateSkinImages(config); err != nil {
+				return config, err
+			}
+			if err := validateGrafanaPanels(config); err != nil {
+				return config, err
+			}
+			if err := validateImageLimits(config); err != nil {
+				return config, err
+			}
+			if err := validateAnimatedChart(config); err != nil {
+				return config, err
+			}
+			if config.RainDayStartHour < 0 || config.RainDayStartHour > 23 {
+				return config, fmt.Errorf("ungültiger Beginn des Niederschlagstags (rain_day_start_hour 0–23)")
+			}
+			if config.RainDayCredit != rainDayCreditStart && config.RainDayCredit != rainDayCreditEnd {
+				return config, fmt.Errorf("unbekannte Zuordnung des Niederschlagstags %q (rain_day_credit: start, end)", config.RainDayCredit)
+			}
+			if err := validatePressureWindows(config); err != nil {
+				return config, err
+			}
+			if config.PressureChartThreshold <= 0 {
+				return config, fmt.Errorf("ungültige Schwelle für das Luftdruckdiagramm (pressure_chart_threshold > 0)")
+			}
+			if err := validatePostTypes("content_warnings", config.ContentWarnings); err != nil {
+				return config, err
+			}
+			if config.OutputFormat != outputFormatText && config.OutputFormat != outputFormatJSON {
+				return config, fmt.Errorf("unbekanntes Ausgabeformat %q (output_format: text, json)", config.OutputFormat)
+			}
+			if config.YearHeatmap != "" && config.YearHeatmap != heatmapTemperature && config.YearHeatmap != heatmapRain {
+				return config, fmt.Errorf("unbekannte Messgröße %q (year_heatmap: temperature, rain)", config.YearHeatmap)
+			}
 		}
 	}
 
@@ -187,12 +888,16 @@ func loadConfig(configFile string) (Config, error) {
 
 // saveConfig speichert die Konfiguration in eine JSON-Datei
 func saveConfig(config Config, configFile string) error {
-	data, err := json.MarshalIndent(config, "", "  ")
-	if err != nil {
+	// Ohne HTML-Escaping, damit Vergleichsoperatoren der Hinweisregeln (">=") lesbar bleiben
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(config); err != nil {
 		return fmt.Errorf("Fehler beim Marshalling der Konfiguration: %v", err)
 	}
 
-	return os.WriteFile(configFile, data, 0644)
+	return os.WriteFile(configFile, buf.Bytes(), 0644)
 }
 
 func lemmyLogin(serverURL, username, password string) (string, error) {
@@ -253,14 +958,28 @@ func lemmyGetCommunityID(serverURL, jwt, communityName string) (int, error) {
 	return respData.CommunityView.Community.Id, nil
 }
 
-func lemmyCreatePost(serverURL, jwt string, communityID int, title, body string) error {
+// lemmyCreatePost erstellt einen Lemmy-Post. Das Bild imageURL wird der Link des Posts oder, mit link, dessen
+// Vorschaubild (custom_thumbnail).
+func lemmyCreatePost(serverURL, jwt string, communityID int, title, body, imageURL, altText, link string) error {
 	postUrl := serverURL + "/api/v3/post"
 	payload := map[string]interface{}{
 		"name":         title,
 		"body":         body,
 		"community_id": communityID,
 	}
+	if link != "" {
+		payload["url"] = link
+		if imageURL != "" {
+			payload["custom_thumbnail"] = imageURL
+		}
+	} else if imageURL != "" {
+		payload["url"] = imageURL
+	}
+	if imageURL != "" && altText != "" {
+		payload["alt_text"] = altText
+	}
 	data, _ := json.Marshal(payload)
+	throttle.wait(serverURL)
 	client := &http.Client{}
 	req, err := http.NewRequest("POST", postUrl, strings.NewReader(string(data)))
 	if err != nil {
@@ -281,14 +1000,26 @@ func lemmyCreatePost(serverURL, jwt string, communityID int, title, body string)
 	return nil
 }
 
-// mastodonCreatePost postet einen Status zu Mastodon
-func mastodonCreatePost(server, token, text, visibility string) error {
+// mastodonCreatePost postet einen Status zu Mastodon, mit spoilerText hinter einer Inhaltswarnung und language als
+// Sprache des Status (ISO 639, leer = Voreinstellung des Kontos). mediaIDs sind zuvor hochgeladene Bilder
+// (mastodonUploadAll).
+func mastodonCreatePost(server, token, text, spoilerText, visibility, language string, mediaIDs []string) error {
 	url := server + "/api/v1/statuses"
 	payload := map[string]interface{}{
 		"status":     text,
 		"visibility": visibility,
 	}
+	if spoilerText != "" {
+		payload["spoiler_text"] = spoilerText
+	}
+	if language != "" {
+		payload["language"] = language
+	}
+	if len(mediaIDs) > 0 {
+		payload["media_ids"] = mediaIDs
+	}
 	data, _ := json.Marshal(payload)
+	throttle.wait(server)
 	client := &http.Client{}
 	req, err := http.NewRequest("POST", url, strings.NewReader(string(data)))
 	if err != nil {
@@ -309,72 +1040,164 @@ func mastodonCreatePost(server, token, text, visibility string) error {
 	return nil
 }
 
-// lemmyPostWithRetry versucht einen Post an Lemmy zu senden und wiederholt alle 30 Minuten bei Fehlern
-func lemmyPostWithRetry(config Config, title, weatherText string, loopMode bool) {
-	const retryInterval = 30 * time.Minute
-	const maxRetries = 48 // Maximal 24 Stunden (48 * 30 Minuten) in Loop-Modus
+// lemmyPostOnce sendet einen Post ohne Wiederholung an Lemmy (Login, Community-ID, Bild, Post). Vom Bild wird wie bei
+// lemmyPostWithRetry nur das erste hochgeladen; schlägt das fehl, erscheint der Post ohne Bild.
+func lemmyPostOnce(store *sql.DB, config Config, title, weatherText string, media []mediaAttachment) error {
+	if err := spendAPICall(store, config, platformLemmy); err != nil {
+		return err
+	}
+	jwt, err := lemmyLogin(config.LemmyServer, config.LemmyUsername, config.LemmyPassword)
+	if err != nil {
+		return err
+	}
+	if err := spendAPICall(store, config, platformLemmy); err != nil {
+		return err
+	}
+	communityID, err := lemmyGetCommunityID(config.LemmyServer, jwt, config.LemmyCommunity)
+	if err != nil {
+		return fmt.Errorf("Community-ID: %v", err)
+	}
+	imageURL, altText, link := "", "", ""
+	if len(media) > 0 {
+		if err := spendAPICall(store, config, platformLemmy); err != nil {
+			return err
+		}
+		img, err := fitImage(config, platformLemmy, media[0])
+		if err != nil {
+			log.Printf("Warnung: Bild für Lemmy nicht angepasst: %v", err)
+		} else if img.Path != media[0].Path {
+			defer removeMedia([]mediaAttachment{img})
+		}
+		if url, err := lemmyUploadImage(config.LemmyServer, jwt, img); err != nil {
+			log.Printf("Warnung: Bild-Upload zu Lemmy fehlgeschlagen: %v", err)
+		} else {
+			imageURL, altText, link = url, media[0].Description, media[0].Link
+		}
+	}
+	if err := spendAPICall(store, config, platformLemmy); err != nil {
+		return err
+	}
+	return lemmyCreatePost(config.LemmyServer, jwt, communityID, title, weatherText, imageURL, altText, link)
+}
+
+// retryPolicy legt fest, wie oft und in welchem Abstand ein fehlgeschlagener Lemmy-Post wiederholt wird
+type retryPolicy struct {
+	interval   time.Duration
+	maxRetries int // 0 = unbegrenzt
+}
 
+// backfillRetry wiederholt beim Nachholen (-backfill) nur kurz, damit eine nicht erreichbare Plattform die übrigen
+// Tage nicht aufhält; gescheiterte Posts landen in der Dead-Letter-Datei
+var backfillRetry = retryPolicy{interval: 5 * time.Minute, maxRetries: 3}
+
+// retryPolicyFor liefert die Wiederholung für den täglichen Lauf: im Loop-Modus höchstens 24 Stunden
+// (48 * 30 Minuten), sonst unbegrenzt alle 30 Minuten
+func retryPolicyFor(loopMode bool) retryPolicy {
+	if loopMode {
+		return retryPolicy{interval: 30 * time.Minute, maxRetries: 48}
+	}
+	return retryPolicy{interval: 30 * time.Minute}
+}
+
+// lemmyPostWithRetry versucht einen Post an Lemmy zu senden und wiederholt bei Fehlern nach policy.
+// Ein Fehler wird nur zurückgegeben, wenn die maximale Anzahl von Wiederholungen erreicht wurde.
+// Jeder API-Aufruf wird auf das Tagesbudget angerechnet; ist es aufgebraucht, wird sofort abgebrochen.
+func lemmyPostWithRetry(store *sql.DB, config Config, title, weatherText string, media []mediaAttachment, policy retryPolicy) error {
 	retryCount := 0
+	var attempts attemptLog // Fehlerverlauf für die Dead-Letter-Datei
+	imageURL, altText, link := "", "", ""
+	uploaded := len(media) == 0 // Lemmy-Posts haben höchstens ein Bild; hochgeladen wird nur beim ersten Versuch
 
 	for {
 		log.Printf("Versuche Post an Lemmy zu senden...")
 
 		// Login bei Lemmy
+		if err := spendAPICall(store, config, platformLemmy); err != nil {
+			return attempts.failed(err)
+		}
 		jwt, err := lemmyLogin(config.LemmyServer, config.LemmyUsername, config.LemmyPassword)
 		if err != nil {
 			log.Printf("Fehler beim Lemmy-Login: %v", err)
-			if loopMode {
+			if policy.maxRetries > 0 {
 				retryCount++
-				if retryCount >= maxRetries {
-					log.Printf("Maximale Anzahl von Wiederholungen erreicht (%d). Beende Retry-Versuch.", maxRetries)
-					return
+				if retryCount >= policy.maxRetries {
+					log.Printf("Maximale Anzahl von Wiederholungen erreicht (%d). Beende Retry-Versuch.", policy.maxRetries)
+					return attempts.failed(fmt.Errorf("maximale Anzahl von Wiederholungen erreicht (%d): %v", policy.maxRetries, err))
 				}
-				log.Printf("Wiederhole in %v... (Versuch %d/%d)", retryInterval, retryCount, maxRetries)
+				log.Printf("Wiederhole in %v... (Versuch %d/%d)", policy.interval, retryCount, policy.maxRetries)
 			} else {
-				log.Printf("Wiederhole in %v...", retryInterval)
+				log.Printf("Wiederhole in %v...", policy.interval)
 			}
-			time.Sleep(retryInterval)
+			attempts.add(err)
+			time.Sleep(policy.interval)
 			continue
 		}
 
 		// Community-ID holen
+		if err := spendAPICall(store, config, platformLemmy); err != nil {
+			return attempts.failed(err)
+		}
 		communityID, err := lemmyGetCommunityID(config.LemmyServer, jwt, config.LemmyCommunity)
 		if err != nil {
 			log.Printf("Fehler beim Holen der Community-ID: %v", err)
-			if loopMode {
+			if policy.maxRetries > 0 {
 				retryCount++
-				if retryCount >= maxRetries {
-					log.Printf("Maximale Anzahl von Wiederholungen erreicht (%d). Beende Retry-Versuch.", maxRetries)
-					return
+				if retryCount >= policy.maxRetries {
+					log.Printf("Maximale Anzahl von Wiederholungen erreicht (%d). Beende Retry-Versuch.", policy.maxRetries)
+					return attempts.failed(fmt.Errorf("maximale Anzahl von Wiederholungen erreicht (%d): %v", policy.maxRetries, err))
 				}
-				log.Printf("Wiederhole in %v... (Versuch %d/%d)", retryInterval, retryCount, maxRetries)
+				log.Printf("Wiederhole in %v... (Versuch %d/%d)", policy.interval, retryCount, policy.maxRetries)
 			} else {
-				log.Printf("Wiederhole in %v...", retryInterval)
+				log.Printf("Wiederhole in %v...", policy.interval)
 			}
-			time.Sleep(retryInterval)
+			attempts.add(err)
+			time.Sleep(policy.interval)
 			continue
 		}
 
+		// Bild hochladen; schlägt das fehl, erscheint der Post ohne Bild
+		if !uploaded {
+			uploaded = true
+			if err := spendAPICall(store, config, platformLemmy); err != nil {
+				return attempts.failed(err)
+			}
+			img, err := fitImage(config, platformLemmy, media[0])
+			if err != nil {
+				log.Printf("Warnung: Bild für Lemmy nicht angepasst: %v", err)
+			} else if img.Path != media[0].Path {
+				defer removeMedia([]mediaAttachment{img})
+			}
+			if url, err := lemmyUploadImage(config.LemmyServer, jwt, img); err != nil {
+				log.Printf("Warnung: Bild-Upload zu Lemmy fehlgeschlagen: %v", err)
+			} else {
+				imageURL, altText, link = url, media[0].Description, media[0].Link
+			}
+		}
+
 		// Post erstellen
-		err = lemmyCreatePost(config.LemmyServer, jwt, communityID, title, weatherText)
+		if err := spendAPICall(store, config, platformLemmy); err != nil {
+			return attempts.failed(err)
+		}
+		err = lemmyCreatePost(config.LemmyServer, jwt, communityID, title, weatherText, imageURL, altText, link)
 		if err != nil {
 			log.Printf("Fehler beim Erstellen des Posts: %v", err)
-			if loopMode {
+			if policy.maxRetries > 0 {
 				retryCount++
-				if retryCount >= maxRetries {
-					log.Printf("Maximale Anzahl von Wiederholungen erreicht (%d). Beende Retry-Versuch.", maxRetries)
-					return
+				if retryCount >= policy.maxRetries {
+					log.Printf("Maximale Anzahl von Wiederholungen erreicht (%d). Beende Retry-Versuch.", policy.maxRetries)
+					return attempts.failed(fmt.Errorf("maximale Anzahl von Wiederholungen erreicht (%d): %v", policy.maxRetries, err))
 				}
-				log.Printf("Wiederhole in %v... (Versuch %d/%d)", retryInterval, retryCount, maxRetries)
+				log.Printf("Wiederhole in %v... (Versuch %d/%d)", policy.interval, retryCount, policy.maxRetries)
 			} else {
-				log.Printf("Wiederhole in %v...", retryInterval)
+				log.Printf("Wiederhole in %v...", policy.interval)
 			}
-			time.Sleep(retryInterval)
+			attempts.add(err)
+			time.Sleep(policy.interval)
 			continue
 		}
 
 		log.Printf("Wetterstatistik erfolgreich an Lemmy gepostet!")
-		return // Erfolgreich - beende die Schleife
+		return nil // Erfolgreich - beende die Schleife
 	}
 }
 
@@ -425,21 +1248,60 @@ func main() {
 	var testMode = flag.Bool("test", false, "Run in test mode - don't post to Lemmy, just show what would be posted")
 	var configFile = flag.String("config", "config.json", "Configuration file path")
 	var loopMode = flag.Bool("loop", false, "Run in continuous monitoring mode - posts daily at 4:00 AM")
-	var noaaFile = flag.String("noaa", "", "NOAA report file for test comparison")
+	var importFlag = flag.Bool("import-history", false, "Import the whole weewx archive into the local stats store (resumable) and exit")
+	var rebuildFlag = flag.Bool("rebuild-daily", false, "Recompute all daily aggregates from the archive table into the local stats store and exit")
+	var workers = flag.Int("workers", runtime.NumCPU(), "Number of parallel workers for -import-history")
+	var serveAddr = flag.String("serve", "", "Serve public HTTP endpoints (JSON badges) on this address, e.g. :8080; combinable with -loop")
+	var exportKind = flag.String("export", "", "Export computed data as CSV and exit (formats: degree-days, daily-stats)")
+	var fromDate = flag.String("from", "", "Start date YYYY-MM-DD for -export or a summary of the period (default: January 1st)")
+	var toDate = flag.String("to", "", "End date YYYY-MM-DD for -export or a summary of the period (default: yesterday)")
+	var postRange = flag.Bool("post", false, "With -from/-to or -top: also publish the summary or ranked list on Lemmy and Mastodon")
+	var outFile = flag.String("out", "", "Output file for -export (default: stdout)")
+	var profileCPU = flag.String("profile-cpu", "", "Write a CPU profile (pprof) to this file")
+	var profileMem = flag.String("profile-mem", "", "Write a heap profile (pprof) to this file on exit")
+	var noaaFile = flag.String("noaa", "", "NOAA report file for rain comparison (mismatches are reported in the monthly QC digest)")
+	var simulateDays = flag.Int("simulate", 0, "Replay the last N days through the post pipeline and print what would have been posted, then exit")
+	var backfillDays = flag.Int("backfill", 0, "Post the days among the last N that were never published (state store), oldest first, then exit")
+	var resendFlag = flag.Bool("resend", false, "Resend posts from the dead-letter file (dead_letter_file) and exit; with -test only list them")
+	var topList = flag.String("top", "", "Print a ranked list of days up to yesterday and exit, e.g. warm:year:5 or rain:all:10 (metrics: warm, cold, rain; periods: month, year, all); with -post also publish it")
+	var yearReview = flag.Int("year-review", 0, "Print the year-in-review post for this year (e.g. 2025) and exit")
+	var outputFormat = flag.String("format", "", "Console output of the daily run: text or json (all computed stats as JSON on stdout); overrides output_format")
+	var fleetFile = flag.String("fleet", "", "Fleet file listing several station configs and databases to run together (combinable with -test and -loop)")
 	flag.Parse()
 
-	if len(flag.Args()) != 1 {
+	needsDB := *fleetFile == "" && !*resendFlag
+	if needsDB != (len(flag.Args()) == 1) {
 		fmt.Fprintf(os.Stderr, "Usage: %s [flags] /path/to/weewx.sdb\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [-test] [-loop] -fleet fleet.json\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "       %s [-test] [-config config.json] -resend\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
-	dbPath := flag.Args()[0]
+
+	// Profiling (optional), auswertbar mit "go tool pprof"
+	if err := startProfiles(*profileCPU, *profileMem); err != nil {
+		log.Fatalf("CPU-Profil: %v", err)
+	}
+	defer stopProfiles()
+
+	// Mehrere Stationen: jede mit eigener Konfiguration und Datenbank, gemeinsame Ratenbegrenzung
+	if *fleetFile != "" {
+		fleet, err := loadFleet(*fleetFile)
+		if err != nil {
+			fatalf("Fleet: %v", err)
+		}
+		if *testMode {
+			log.Printf("🧪 TEST-MODUS: Keine Posts werden an Lemmy gesendet!")
+		}
+		runScheduled(*loopMode, fleet.runMinute, func() { runFleet(fleet, *testMode, *loopMode) })
+		return
+	}
 
 	// Konfiguration laden
 	config, err := loadConfig(*configFile)
 	if err != nil {
-		log.Fatalf("Fehler beim Laden der Konfiguration: %v", err)
+		fatalf("Fehler beim Laden der Konfiguration: %v", err)
 	}
 
 	// Konfiguration speichern (falls sie nicht existierte)
@@ -448,42 +1310,220 @@ func main() {
 		log.Printf("Warnung: Konfiguration konnte nicht gespeichert werden: %v", err)
 	}
 
-	if *testMode {
-		log.Printf("🧪 TEST-MODUS: Keine Posts werden an Lemmy gesendet!")
+	// Speicherlimit für Import und alle Auswertungen
+	applyMemoryLimit(config)
+
+	switch *outputFormat {
+	case "":
+	case outputFormatText, outputFormatJSON:
+		config.OutputFormat = *outputFormat
+	default:
+		fatalf("Unbekanntes Ausgabeformat %q (-format: text, json)", *outputFormat)
 	}
 
-	if *loopMode {
-		log.Printf("🔄 LOOP-MODUS: Starte kontinuierliche Überwachung...")
-		log.Printf("Posts werden täglich um 4:00 Uhr erstellt")
+	if *resendFlag {
+		if err := resendDeadLetters(config, *testMode); err != nil {
+			fatalf("Erneutes Senden fehlgeschlagen: %v", err)
+		}
+		return
+	}
+	dbPath := flag.Args()[0]
 
-		// Kontinuierliche Überwachung
-		for {
-			runWeatherPosting(dbPath, config, *testMode, true, *noaaFile)
+	if *importFlag || *rebuildFlag {
+		if err := importHistory(dbPath, config, *workers, *rebuildFlag); err != nil {
+			fatalf("Import fehlgeschlagen: %v", err)
+		}
+		return
+	}
 
-			// Berechne nächsten Lauf um 4:00 Uhr
-			now := time.Now()
-			nextRun := time.Date(now.Year(), now.Month(), now.Day(), 4, 0, 0, 0, now.Location())
-			if now.After(nextRun) {
-				nextRun = nextRun.AddDate(0, 0, 1) // Morgen um 4:00 Uhr
+	if *exportKind != "" {
+		if err := runExport(dbPath, config, *exportKind, *fromDate, *toDate, *outFile); err != nil {
+			fatalf("Export fehlgeschlagen: %v", err)
+		}
+		return
+	}
+
+	if *fromDate != "" || *toDate != "" {
+		if err := runRangeReport(dbPath, config, *fromDate, *toDate, *postRange, *testMode); err != nil {
+			fatalf("Zusammenfassung fehlgeschlagen: %v", err)
+		}
+		return
+	}
+
+	if *topList != "" {
+		if err := runTopList(dbPath, config, *topList, *postRange, *testMode); err != nil {
+			fatalf("Bestenliste fehlgeschlagen: %v", err)
+		}
+		return
+	}
+
+	if *yearReview > 0 {
+		if err := printYearReview(dbPath, config, *yearReview); err != nil {
+			fatalf("Jahresrückblick fehlgeschlagen: %v", err)
+		}
+		return
+	}
+
+	if *backfillDays > 0 {
+		if err := runBackfill(dbPath, config, *backfillDays, *testMode, *noaaFile); err != nil {
+			fatalf("Nachholen fehlgeschlagen: %v", err)
+		}
+		return
+	}
+
+	if *simulateDays > 0 {
+		if err := runSimulation(dbPath, config, *simulateDays); err != nil {
+			fatalf("Simulation fehlgeschlagen: %v", err)
+		}
+		return
+	}
+
+	if *serveAddr != "" {
+		if !*loopMode {
+			if err := serveHTTP(*serveAddr, dbPath, config); err != nil {
+				fatalf("HTTP-Server: %v", err)
 			}
+			return
+		}
+		go func() {
+			if err := serveHTTP(*serveAddr, dbPath, config); err != nil {
+				fatalf("HTTP-Server: %v", err)
+			}
+		}()
+	}
+
+	if *testMode {
+		log.Printf("🧪 TEST-MODUS: Keine Posts werden an Lemmy gesendet!")
+	}
 
-			sleepDuration := nextRun.Sub(now)
-			log.Printf("Nächster Lauf um %s (in %v)", nextRun.Format("02.01.2006 15:04:05"), sleepDuration)
-			time.Sleep(sleepDuration)
+	runScheduled(*loopMode, dailyRunMinute(config), func() {
+		if err := runWeatherPosting(dbPath, config, *testMode, *loopMode, *noaaFile); err != nil {
+			fatalf("%v", err)
 		}
-	} else {
+	})
+}
+
+// dailyRunMinute liefert die Uhrzeit des täglichen Laufs im Loop-Modus in Minuten nach Mitternacht: 4:00 Uhr oder,
+// wenn der Niederschlagstag des Vortags erst später endet (rain_day_credit "start"), 5 Minuten nach seinem Ende
+func dailyRunMinute(config Config) int {
+	if config.RainDayStartHour >= 4 && config.RainDayCredit == rainDayCreditStart {
+		return config.RainDayStartHour*60 + 5
+	}
+	return 4 * 60
+}
+
+// runScheduled führt run einmal aus oder im Loop-Modus täglich zur Uhrzeit runMinute (Minuten nach Mitternacht)
+func runScheduled(loopMode bool, runMinute int, run func()) {
+	if !loopMode {
 		// Einmalige Ausführung
-		runWeatherPosting(dbPath, config, *testMode, false, *noaaFile)
+		run()
+		return
+	}
+
+	log.Printf("🔄 LOOP-MODUS: Starte kontinuierliche Überwachung...")
+	log.Printf("Posts werden täglich um %d:%02d Uhr erstellt", runMinute/60, runMinute%60)
+
+	// Kontinuierliche Überwachung
+	for {
+		run()
+
+		// Berechne nächsten Lauf
+		now := time.Now()
+		nextRun := time.Date(now.Year(), now.Month(), now.Day(), runMinute/60, runMinute%60, 0, 0, now.Location())
+		if now.After(nextRun) {
+			nextRun = nextRun.AddDate(0, 0, 1) // morgen zur selben Uhrzeit
+		}
+
+		sleepDuration := nextRun.Sub(now)
+		log.Printf("Nächster Lauf um %s (in %v)", nextRun.Format("02.01.2006 15:04:05"), sleepDuration)
+		time.Sleep(sleepDuration)
+	}
+}
+
+// stopProfiles beendet die mit startProfiles gestarteten Profile und schreibt sie; weitere Aufrufe tun nichts
+var stopProfiles = func() {}
+
+// startProfiles startet das CPU-Profil (cpuPath, leer: keins) und merkt das Heap-Profil (memPath) für das Programmende
+// vor. Weil log.Fatalf, -loop und -serve main nicht regulär verlassen, schreiben fatalf sowie SIGINT und SIGTERM die
+// Profile vor dem Beenden.
+func startProfiles(cpuPath, memPath string) error {
+	if cpuPath == "" && memPath == "" {
+		return nil
+	}
+	var cpu *os.File
+	if cpuPath != "" {
+		f, err := os.Create(cpuPath)
+		if err != nil {
+			return err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return err
+		}
+		cpu = f
+	}
+	var once sync.Once
+	stopProfiles = func() {
+		once.Do(func() {
+			if cpu != nil {
+				pprof.StopCPUProfile()
+				cpu.Close()
+			}
+			if memPath != "" {
+				writeMemProfile(memPath)
+			}
+		})
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Printf("%v empfangen, Profile werden geschrieben", sig)
+		stopProfiles()
+		os.Exit(1)
+	}()
+	return nil
+}
+
+// fatalf schreibt laufende Profile und beendet das Programm wie log.Fatalf
+func fatalf(format string, v ...interface{}) {
+	stopProfiles()
+	log.Fatalf(format, v...)
+}
+
+// writeMemProfile schreibt ein Heap-Profil in die angegebene Datei
+func writeMemProfile(path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		log.Printf("Speicherprofil: %v", err)
+		return
+	}
+	defer f.Close()
+	runtime.GC() // aktuelle Statistik der Allokationen
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		log.Printf("Speicherprofil: %v", err)
 	}
 }
 
-func runWeatherPosting(dbPath string, config Config, testMode bool, loopMode bool, noaaFile string) {
+func runWeatherPosting(dbPath string, config Config, testMode bool, loopMode bool, noaaFile string) error {
+	return runWeatherPostingAt(dbPath, config, testMode, loopMode, false, noaaFile, time.Now())
+}
+
+// runWeatherPostingAt erstellt und veröffentlicht den Post für den Vortag von now. Beim Nachholen (backfill) entfällt
+// alles außer dem Tagespost (Warn-Posts, QC-Zusammenfassung, Rückblicke, Bestenlisten), und ein gescheiterter
+// Lemmy-Post wird nur kurz wiederholt, bevor er in der Dead-Letter-Datei landet.
+func runWeatherPostingAt(dbPath string, config Config, testMode, loopMode, backfill bool, noaaFile string, now time.Time) error {
 	loc, err := time.LoadLocation("Europe/Berlin")
 	if err != nil {
-		log.Fatalf("timezone: %v", err)
+		return fmt.Errorf("timezone: %v", err)
+	}
+	retry := retryPolicyFor(loopMode)
+	if backfill {
+		retry = backfillRetry
 	}
 
-	now := time.Now().In(loc)
+	now = now.In(loc)
 	yesterday := now.AddDate(0, 0, -1)
 	dayBefore := now.AddDate(0, 0, -2)
 
@@ -493,73 +1533,439 @@ func runWeatherPosting(dbPath string, config Config, testMode bool, loopMode boo
 	startDayBefore := time.Date(dayBefore.Year(), dayBefore.Month(), dayBefore.Day(), 0, 0, 0, 0, loc)
 	endDayBefore := startDayBefore.AddDate(0, 0, 1)
 
+	// Mit rain_day_credit "start" endet der Niederschlagstag des Vortags erst am Morgen; bis dahin wäre die
+	// Regenmenge unvollständig, der Tagespost wird zurückgehalten
+	rainStart, rainEnd := rainDayWindow(loc, config, startYesterday)
+	if rainEnd > now.Unix() {
+		ends := time.Unix(rainEnd, 0).In(loc).Format("15:04")
+		if !testMode {
+			log.Printf("Tagespost für den %s zurückgehalten: Der Niederschlagstag endet erst um %s Uhr (rain_day_credit), bitte danach erneut starten",
+				startYesterday.Format("02.01.2006"), ends)
+			return nil
+		}
+		log.Printf("Warnung: Der Niederschlagstag des %s endet erst um %s Uhr, die Regenmenge ist noch unvollständig (rain_day_credit)",
+			startYesterday.Format("02.01.2006"), ends)
+	}
+
 	db, err := sql.Open("sqlite3", dbPath)
 	if err != nil {
-		log.Fatalf("open DB: %v", err)
+		return fmt.Errorf("open DB: %v", err)
 	}
 	defer db.Close()
 
-	statsY, err := getStats(db, loc, startYesterday.UTC().Unix(), endYesterday.UTC().Unix())
+	store, err := openStore(config.StorePath)
 	if err != nil {
-		log.Fatalf("yesterday stats: %v", err)
+		log.Printf("Warnung: %v – QC-Ereignisse werden nicht gespeichert", err)
+	} else {
+		defer store.Close()
+	}
+	if !backfill {
+		sendMonthlyQCDigest(store, config, now, testMode)
 	}
-	statsV, err := getStats(db, loc, startDayBefore.UTC().Unix(), endDayBefore.UTC().Unix())
+
+	statsY, err := getStats(db, loc, config, startYesterday.UTC().Unix(), endYesterday.UTC().Unix())
 	if err != nil {
-		log.Fatalf("vorgestern stats: %v", err)
+		return fmt.Errorf("yesterday stats: %v", err)
+	}
+	statsV, err := getStats(db, loc, config, startDayBefore.UTC().Unix(), endDayBefore.UTC().Unix())
+	if err != nil {
+		return fmt.Errorf("vorgestern stats: %v", err)
+	}
+
+	// Tageswerte im Zustandsspeicher fortschreiben (Grundlage für Rekorde und Vergleiche)
+	if store != nil {
+		if err := saveDayStats(store, startYesterday.Format("2006-01-02"), statsY); err != nil {
+			log.Printf("Warnung: Tageswerte konnten nicht gespeichert werden: %v", err)
+		} else if err := updateRecords(store, config.RecordMinHistoryDays); err != nil {
+			log.Printf("Warnung: Rekorde konnten nicht aktualisiert werden: %v", err)
+		}
+	}
+
+	// Datenqualität des Vortags prüfen und für den Monatsbericht festhalten
+	dayKey := startYesterday.Format("2006-01-02")
+	if gaps, err := countArchiveGaps(db, startYesterday.Unix(), endYesterday.Unix()); err != nil {
+		log.Printf("Warnung: Lückenprüfung fehlgeschlagen: %v", err)
+	} else if gaps > 0 {
+		recordQCEvent(store, dayKey, qcGap, "archive", gaps, fmt.Sprintf("%d Lücken in archive", gaps))
+	}
+	if archiveRain, err := archiveRainSum(db, rainStart, rainEnd); err != nil {
+		log.Printf("Warnung: Selbstprüfung Niederschlag fehlgeschlagen: %v", err)
+	} else if math.Abs(archiveRain-statsY.rainSum) > 0.2 {
+		recordQCEvent(store, dayKey, qcMismatch, "selbstpruefung", 1,
+			fmt.Sprintf("archive_day_rain %.1f mm, archive %.1f mm", statsY.rainSum, archiveRain))
+	}
+	if problems := plausibilityProblems(statsY); len(problems) > 0 {
+		recordQCEvent(store, dayKey, qcReject, "tageswerte", len(problems), strings.Join(problems, "; "))
+		log.Printf("Warnung: Unplausible Wetterdaten (%s) – Posting wird übersprungen!", strings.Join(problems, "; "))
+		return nil
 	}
 
 	// Vor dem Posting: Prüfe auf NaN
-	if math.IsNaN(statsY.tMax) || math.IsNaN(statsY.tMin) || math.IsNaN(statsV.tMax) || math.IsNaN(statsV.tMin) {
+	if math.IsNaN(statsV.tMax) || math.IsNaN(statsV.tMin) {
 		log.Printf("Warnung: Ungültige Wetterdaten (NaN) – Posting wird übersprungen!")
-		return
+		return nil
 	}
 
-	// Ermittle Trockenperiode (Tage seit letztem Regen)
-	daysSinceRain := 0
-	for i := 1; i < 30; i++ { // max. 30 Tage zurück
-		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -i)
-		var rainSum sql.NullFloat64
-		// Korrigierte Abfrage für Trockenperiode
-		if err := db.QueryRow("SELECT sum FROM archive_day_rain WHERE dateTime = ?;", start.Unix()).Scan(&rainSum); err != nil {
-			break // Fehler oder kein Eintrag -> abbrechen
+	post := composeDailyPost(db, store, loc, config, now, startYesterday, statsY, statsV)
+	title, weatherText, alerts := post.title, post.text, post.alerts
+	lemmyTitle, lemmyText := post.forPlatform(config, platformLemmy)
+	// Die Inhaltswarnung zählt bei Mastodon zum Zeichenlimit
+	mastodonCW := contentWarning(config, postTypeDaily, post.data)
+	mastodonTitle, mastodonBody := post.fitToLimit(config, platformMastodon, mastodonLimit(config, mastodonCW))
+	mastodonText := mastodonTitle + "\n" + mastodonBody
+	webhookTitle, webhookText := post.forPlatform(config, platformWebhook)
+
+	// Ausgabe; im JSON-Format geht die Übersicht auf stderr, damit stdout nur das JSON enthält
+	out := io.Writer(os.Stdout)
+	if config.OutputFormat == outputFormatJSON {
+		out = os.Stderr
+	}
+	fmt.Fprintf(out, "Statistik für %s %s: (Vortag)\n", config.StationName, startYesterday.Format("02.01.2006"))
+	fmt.Fprintf(out, "  Höchsttemperatur:         %.1f °C (%.1f °C)\n", statsY.tMax, statsV.tMax)
+	fmt.Fprintf(out, "  Tiefsttemperatur:         %.1f °C (%.1f °C)\n", statsY.tMin, statsV.tMin)
+	fmt.Fprintf(out, "  Uhrzeit Höchst/Tiefst:    %s/%s (%s/%s)\n", formatClock(statsY.tMaxTime, loc), formatClock(statsY.tMinTime, loc), formatClock(statsV.tMaxTime, loc), formatClock(statsV.tMinTime, loc))
+	fmt.Fprintf(out, "  Gefühlt:                  %.1f bis %.1f °C (%.1f bis %.1f °C)\n", statsY.heatIndexMax, statsY.windChillMin, statsV.heatIndexMax, statsV.windChillMin)
+	fmt.Fprintf(out, "  Niederschlag:             %.1f mm (%.1f mm)\n", statsY.rainSum, statsV.rainSum)
+	fmt.Fprintf(out, "  Stunden mit Sonnenschein: %d h (%d h)\n", statsY.sunHours, statsV.sunHours)
+	fmt.Fprintf(out, "  Sonnenscheindauer:        %s (%s)\n", formatDuration(statsY.sunMinutes), formatDuration(statsV.sunMinutes))
+	fmt.Fprintf(out, "  Sonnenenergie:            %.2f kWh/m² (%.2f kWh/m²)\n", statsY.solarEnergy, statsV.solarEnergy)
+	fmt.Fprintf(out, "  UV-Index max.:            %.1f (%.1f)\n", statsY.uvMax, statsV.uvMax)
+	fmt.Fprintf(out, "  Max. Regenrate:           %.1f mm/h (%.1f mm/h)\n", statsY.rainRateMax, statsV.rainRateMax)
+	fmt.Fprintf(out, "  Regendauer:               %s in %d h (%s in %d h)\n", formatDuration(statsY.rainMinutes), statsY.rainHours, formatDuration(statsV.rainMinutes), statsV.rainHours)
+	fmt.Fprintf(out, "  Windweg:                  %.0f km (%.0f km)\n", statsY.windRun, statsV.windRun)
+	if !math.IsNaN(statsY.soilTempMin) || !math.IsNaN(statsY.soilMoistMin) {
+		fmt.Fprintf(out, "  Bodentemperatur:          %.1f bis %.1f °C (%.1f bis %.1f °C)\n", statsY.soilTempMin, statsY.soilTempMax, statsV.soilTempMin, statsV.soilTempMax)
+		fmt.Fprintf(out, "  Bodenfeuchte:             %.0f bis %.0f cb (%.0f bis %.0f cb)\n", statsY.soilMoistMin, statsY.soilMoistMax, statsV.soilMoistMin, statsV.soilMoistMax)
+	}
+	if !math.IsNaN(statsY.leafWetHours) {
+		fmt.Fprintf(out, "  Blattnässe:               %.1f h (%.1f h)\n", statsY.leafWetHours, statsV.leafWetHours)
+	}
+	if category, ok := airQualityCategory(statsY); ok {
+		fmt.Fprintf(out, "  Luftqualität:             %s, PM2.5 Ø %.1f µg/m³, PM10 Ø %.1f µg/m³\n", category.label, statsY.pm25Mean, statsY.pm10Mean)
+	}
+	fmt.Fprintf(out, "  Taupunkt:                 %.1f bis %.1f °C (%.1f bis %.1f °C)\n", statsY.dewMin, statsY.dewMax, statsV.dewMin, statsV.dewMax)
+	fmt.Fprintf(out, "  Wolkenbasis (geschätzt):  %.0f bis %.0f m (%.0f bis %.0f m)\n", statsY.cloudBaseMin, statsY.cloudBaseMax, statsV.cloudBaseMin, statsV.cloudBaseMax)
+	fmt.Fprintf(out, "  Luftdruck:                %.1f hPa (%.1f hPa), morgens bis abends %+.1f hPa, Tendenz %+.1f hPa in %d h\n",
+		statsY.pressure.mean, statsV.pressure.mean, statsY.pressure.change, statsY.pressure.trend, config.PressureTendencyHours)
+
+	report := newDailyReport(startYesterday, post, statsY, statsV)
+	if noaaFile != "" {
+		noaaRain, err := parseNoaaRain(noaaFile, yesterday)
+		if err != nil {
+			fmt.Fprintf(out, "NOAA-Report-Vergleich: Fehler: %v\n", err)
+		} else {
+			report.NOAARain = &noaaRain
+			fmt.Fprintf(out, "NOAA-Report: Tagesregenmenge für %s: %.1f mm\n", yesterday.Format("02.01.2006"), noaaRain)
+			if math.Abs(noaaRain-statsY.rainSum) < 0.01 {
+				fmt.Fprintf(out, "Vergleich: ✅ Werte stimmen überein.\n")
+			} else {
+				fmt.Fprintf(out, "Vergleich: ❌ Werte unterscheiden sich! (DB: %.2f mm, NOAA: %.2f mm)\n", statsY.rainSum, noaaRain)
+				recordQCEvent(store, dayKey, qcMismatch, "noaa", 1, fmt.Sprintf("DB %.2f mm, NOAA %.2f mm", statsY.rainSum, noaaRain))
+			}
+		}
+	}
+	if config.OutputFormat == outputFormatJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("JSON-Ausgabe: %v", err)
 		}
-		if rainSum.Valid && rainSum.Float64 * 10.0 > 0 {
-			break // Es hat geregnet
+		fmt.Println(string(data))
+		if testMode {
+			return nil
 		}
-		daysSinceRain++
 	}
 
-	// Wetterstatistik erstellen
-	var weatherText = fmt.Sprintf(`Niederschlag: %.1f mm (Vortag: %.1f mm), Stunden mit Sonnenschein: %d h (Vortag: %d h) Details: https://groloe.wetter.foxel.org/week.html`,
-		statsY.rainSum, statsV.rainSum,
-		statsY.sunHours, statsV.sunHours)
+	// Bilder zum Post; im Test-Modus bleiben die Dateien zur Ansicht erhalten. Die Übersichtskarte ist nur das
+	// Vorschaubild des Lemmy-Posts.
+	images := dailyMedia(db, loc, config, startYesterday, post.chartAnnotations())
+	media := images.forPlatform(config, platformMastodon)
+	lemmyMedia := images.forPlatform(config, platformLemmy)
+	allMedia := images.all()
+	if config.SummaryCard {
+		if card, err := summaryCardMedia(config, startYesterday, statsY); err != nil {
+			log.Printf("Warnung: Übersichtskarte nicht verfügbar: %v", err)
+		} else {
+			lemmyMedia = append([]mediaAttachment{card}, lemmyMedia...)
+			allMedia = append(allMedia, card)
+		}
+	}
+	if !testMode {
+		defer removeMedia(allMedia)
+	}
 
-	// Trockenperiode- und Regenserien-Hinweis ergänzen
-	consecutiveRainDays := 0
-	for i := 1; i < 30; i++ {
-		start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -i)
-		var rainSum sql.NullFloat64
-		// Korrigierte Abfrage für Regenserien
-		if err := db.QueryRow("SELECT sum FROM archive_day_rain WHERE dateTime = ?;", start.Unix()).Scan(&rainSum); err != nil {
-			break // Fehler oder kein Eintrag -> abbrechen
+	// Lemmy-Posting (nur wenn nicht im Test-Modus)
+	// Bereits erfolgreich bediente Plattformen werden bei einem erneuten Lauf für denselben Tag übersprungen
+	published := false
+	if !testMode && config.LemmyPassword != "CHANGEME" {
+		if isPublished(store, dayKey, platformLemmy) {
+			log.Printf("Lemmy-Posting übersprungen (für %s bereits veröffentlicht)", dayKey)
+		} else if err := lemmyPostWithRetry(store, config, lemmyTitle, lemmyText, lemmyMedia, retry); err != nil {
+			recordQCEvent(store, dayKey, qcPublishFailed, platformLemmy, 1, err.Error())
+			setPublishStatus(store, dayKey, platformLemmy, err)
+			saveDeadLetter(config, deadLetter{Day: dayKey, Platform: platformLemmy, Title: lemmyTitle, Text: lemmyText}, lemmyMedia, err)
+		} else {
+			setPublishStatus(store, dayKey, platformLemmy, nil)
+			published = true
+		}
+	} else if testMode {
+		fmt.Printf("\n=== TEST-MODUS: Lemmy-Post würde so aussehen ===\n")
+		fmt.Printf("Titel: %s\n", lemmyTitle)
+		fmt.Printf("Body:\n%s\n", lemmyText)
+		fmt.Printf("=== ENDE TEST-MODUS ===\n")
+		printMedia(lemmyMedia)
+		printPublishedDiff(store, dayKey, title, weatherText)
+		printPublishStatus(store, dayKey)
+		if !backfill {
+			for _, alert := range alerts {
+				fmt.Printf("\n=== TEST-MODUS: Warn-Post an Mastodon ===\n%s\n=== ENDE WARN-POST ===\n", alert)
+			}
+		}
+		fmt.Printf("\n=== TEST-MODUS: Mastodon-Konfiguration ===\n")
+		fmt.Printf("Server: %s\nToken: %s\nVisibility: %s\nLanguage: %s\n", config.MastodonServer, config.MastodonToken,
+			mastodonVisibility(config, postTypeDaily), mastodonLanguage(config))
+		if mastodonCW != "" {
+			fmt.Printf("Inhaltswarnung: %s\n", mastodonCW)
+		}
+		fmt.Printf("=== ENDE MASTODON-KONFIG ===\n")
+		if config.MastodonServer != "" && config.MastodonToken != "" {
+			fmt.Printf("\n=== TEST-MODUS: Mastodon-Post wird simuliert ===\n")
+			fmt.Printf("%s\n", mastodonText)
+			fmt.Printf("=== ENDE TEST-MODUS MASTODON ===\n")
+			if animatedChartEnabled(config, platformMastodon) != animatedChartEnabled(config, platformLemmy) {
+				printMedia(media)
+			}
+			_ = mastodonCreatePost(config.MastodonServer, config.MastodonToken, mastodonText, mastodonCW, mastodonVisibility(config, postTypeDaily), mastodonLanguage(config), nil)
+		}
+		if config.WebhookURL != "" {
+			payload, err := buildWebhookPayload(config.WebhookSchemaVersion, startYesterday, webhookTitle, webhookText, statsY, indoorEnabled(config, platformWebhook))
+			if err != nil {
+				log.Printf("Fehler beim Webhook-Payload: %v", err)
+			} else {
+				data, _ := json.MarshalIndent(payload, "", "  ")
+				fmt.Printf("\n=== TEST-MODUS: Webhook-Payload an %s ===\n%s\n=== ENDE TEST-MODUS WEBHOOK ===\n", config.WebhookURL, data)
+			}
+		}
+		if !backfill {
+			publishPeriodicPosts(db, store, loc, config, now, testMode, loopMode)
+		}
+		return nil
+	} else {
+		log.Printf("Lemmy-Posting übersprungen (Passwort nicht konfiguriert)")
+	}
+
+	// Mastodon-Posting (optional, unabhängig von Lemmy)
+	mastodonErr := error(nil)
+	if config.MastodonServer != "" && config.MastodonToken != "" && isPublished(store, dayKey, platformMastodon) {
+		log.Printf("Mastodon-Posting übersprungen (für %s bereits veröffentlicht)", dayKey)
+	} else if config.MastodonServer != "" && config.MastodonToken != "" {
+		mastodonErr = spendAPICall(store, config, platformMastodon)
+		if mastodonErr == nil {
+			mediaIDs := mastodonUploadAll(store, config, media)
+			mastodonErr = mastodonCreatePost(config.MastodonServer, config.MastodonToken, mastodonText, mastodonCW, mastodonVisibility(config, postTypeDaily), mastodonLanguage(config), mediaIDs)
 		}
-		if rainSum.Valid && rainSum.Float64 * 10.0 > 0 {
-			consecutiveRainDays++
+		setPublishStatus(store, dayKey, platformMastodon, mastodonErr)
+		if mastodonErr != nil {
+			log.Printf("Fehler beim Mastodon-Post: %v", mastodonErr)
+			recordQCEvent(store, dayKey, qcPublishFailed, platformMastodon, 1, mastodonErr.Error())
+			saveDeadLetter(config, deadLetter{Day: dayKey, Platform: platformMastodon, Text: mastodonText, ContentWarning: mastodonCW,
+				Visibility: mastodonVisibility(config, postTypeDaily)}, media, mastodonErr)
 		} else {
-			break // Kein Regen -> Serie endet
+			log.Printf("Wetterstatistik erfolgreich an Mastodon gepostet!")
+			published = true
+		}
+	}
+	if published {
+		savePublishedPost(store, dayKey, title, weatherText)
+	}
+	if !backfill {
+		publishAlerts(store, config, dayKey, alerts)
+	}
+
+	// Webhook (optional)
+	if config.WebhookURL != "" && isPublished(store, dayKey, platformWebhook) {
+		log.Printf("Webhook übersprungen (für %s bereits aufgerufen)", dayKey)
+	} else if config.WebhookURL != "" {
+		payload, err := buildWebhookPayload(config.WebhookSchemaVersion, startYesterday, webhookTitle, webhookText, statsY, indoorEnabled(config, platformWebhook))
+		if err == nil {
+			err = spendAPICall(store, config, platformWebhook)
+		}
+		if err == nil {
+			err = webhookPost(config.WebhookURL, payload)
 		}
+		setPublishStatus(store, dayKey, platformWebhook, err)
+		if err != nil {
+			log.Printf("Fehler beim Webhook: %v", err)
+			recordQCEvent(store, dayKey, qcPublishFailed, platformWebhook, 1, err.Error())
+			if data, jsonErr := json.Marshal(payload); jsonErr == nil {
+				saveDeadLetter(config, deadLetter{Day: dayKey, Platform: platformWebhook, Title: webhookTitle, Text: payload.Text, Payload: data}, nil, err)
+			}
+		}
+	}
+
+	if !backfill {
+		publishPeriodicPosts(db, store, loc, config, now, testMode, loopMode)
+	}
+	return nil
+}
+
+// publishPeriodicPosts veröffentlicht die Posts, die nur an bestimmten Tagen fällig sind: Jahresrückblick am
+// 1. Januar, Rückblick zum Ende jeder Jahreszeit, Bestenlisten und Sonnenwenden/Tagundnachtgleichen (optional)
+func publishPeriodicPosts(db, store *sql.DB, loc *time.Location, config Config, now time.Time, testMode, loopMode bool) {
+	publishYearReview(db, store, loc, config, now, testMode, loopMode)
+	publishSeasonSummary(db, store, loc, config, now, testMode, loopMode)
+	publishTopLists(db, store, config, now, testMode, loopMode)
+	publishCardinalPointPost(db, store, loc, config, now, testMode, loopMode)
+}
+
+// dailyPost ist der fertige Tagespost
+type dailyPost struct {
+	title, text string
+	sections    []postSection     // Abschnitte des Standardtexts in Post-Reihenfolge
+	alerts      []string          // Warn-Posts der Hinweisregeln, die getrennt an Mastodon gehen
+	data        templateData      // Datenkontext für die Templates der Plattformen
+	stats       dayStats          // Tageswerte für den Innenraum-Abschnitt
+	streaks     dailyStreaks      // Serien für die JSON-Ausgabe
+	annotations []chartAnnotation // Rekorde und Serien für die Diagramme, siehe chartAnnotations
+}
+
+// forPlatform liefert Titel und Text des Posts für eine Plattform. Ist für sie in publisher_templates ein Template
+// hinterlegt, wird es mit den Tageswerten gefüllt; sonst gelten der gemeinsame Titel und Text. Der Innenraum-Abschnitt
+// wird angehängt, wenn er für die Plattform freigegeben ist. Bei einem Zeichenlimit (char_limits) entfallen optionale
+// Abschnitte, bis der Post passt, siehe fitToLimit.
+func (p dailyPost) forPlatform(config Config, platform string) (string, string) {
+	return p.fitToLimit(config, platform, config.CharLimits[platform])
+}
+
+// forPlatformUnlimited liefert Titel und Text für eine Plattform ohne Rücksicht auf ihr Zeichenlimit
+func (p dailyPost) forPlatformUnlimited(config Config, platform string) (string, string) {
+	title, text := p.title, p.text
+	if t, ok := config.PublisherTemplates[platform]; ok {
+		title, text = renderTemplate(platform+"_title", t.Title, p.data, p.title), renderTemplate(platform+"_body", t.Body, p.data, p.text)
 	}
+	return title, withIndoor(text, p.stats, config, platform)
+}
 
-	if daysSinceRain >= drySpellThreshold {
+// composeDailyPost erzeugt Titel und Text des Tagesposts für den Tag startYesterday, wie er zum Zeitpunkt now
+// (beim täglichen Lauf am Folgetag) gepostet wird. Wird auch von -simulate für zurückliegende Tage verwendet.
+func composeDailyPost(db, store *sql.DB, loc *time.Location, config Config, now, startYesterday time.Time, statsY, statsV dayStats) dailyPost {
+	// Ermittle Trockenperiode (Tage seit letztem Regen)
+	daysSinceRain := countDaysSinceRain(db, loc, config, now)
+
+	// Wetterstatistik erstellen
+	rainUnit, tempUnit := unitOf(config, quantityRain), unitOf(config, quantityTemperature)
+	post := dailyPost{stats: statsY}
+	summary := tr(config, "Niederschlag: %s (Vortag: %s), %s",
+		rainUnit.format(statsY.rainSum, 1), rainUnit.format(statsV.rainSum, 1),
+		formatSunshine(statsY, statsV, config))
+	if details := detailLine(config, detailDaily); details != "" {
+		summary += " " + details
+	}
+	post.add("summary", summary)
+
+	// Trockenperiode- und Regenserien-Hinweis ergänzen
+	consecutiveRainDays := countConsecutiveRainDays(db, loc, config, now)
+
+	post.add("extreme_times", formatExtremeTimes(statsY, loc, config))
+	post.add("sparkline", formatTemperatureSparkline(db, loc, config, startYesterday))
+	post.add("sun_times", formatSunTimes(config, startYesterday))
+	post.add("moon_phase", formatMoonPhase(config, now))
+	post.add("solar_energy", formatSolarEnergy(statsY, statsV, config))
+	post.add("climate_days", formatClimateDays(db, config, startYesterday, statsY))
+	post.add("frost_dates", formatFrostDates(db, config, startYesterday, statsY))
+	post.add("tropical_night", formatTropicalNight(statsY, config, startYesterday))
+	post.add("lightning", formatLightning(statsY, config))
+	post.add("snow", formatSnow(db, config, startYesterday, statsY))
+	post.add("heavy_rain", formatHeavyRainDay(statsY, config))
+	post.add("rain_duration", formatRainDuration(statsY, config))
+	post.add("rain_intensity", formatRainIntensity(statsY, config))
+	if caveat := rainCaveat(config, startYesterday, statsY); caveat != "" {
+		post.add("rain_caveat", "⚠️ "+tr(config, caveat))
+	}
+
+	if daysSinceRain >= config.DrySpellThreshold {
 		if statsY.rainSum > 0 {
-			weatherText += fmt.Sprintf("\nEs hat nach %d Tagen wieder geregnet.", daysSinceRain)
+			post.add("dry_spell", tr(config, "Es hat nach %d Tagen wieder geregnet.", daysSinceRain))
 		} else {
-			weatherText += fmt.Sprintf("\nEs hat seit %d Tagen nicht mehr geregnet.", daysSinceRain)
+			post.add("dry_spell", tr(config, "Es hat seit %d Tagen nicht mehr geregnet.", daysSinceRain)+
+				formatDrySpellRecord(db, loc, config, now, daysSinceRain))
+		}
+	}
+	if consecutiveRainDays >= config.DrySpellThreshold {
+		post.add("rain_streak", tr(config, "Es regnet seit %d Tagen jeden Tag.", consecutiveRainDays))
+		post.annotations = append(post.annotations, chartAnnotation{section: "rain_streak", value: annotateRain,
+			label: tr(config, "%s Regentag in Folge", ordinal(config, consecutiveRainDays))})
+	}
+	post.streaks = dailyStreaks{DaysSinceRain: daysSinceRain, ConsecutiveRainDays: consecutiveRainDays}
+	if config.FrostStreakThreshold > 0 {
+		post.streaks.FrostNights = countFrostStreak(db, config, startYesterday)
+		if post.streaks.FrostNights >= config.FrostStreakThreshold {
+			post.add("frost_streak", tr(config, "Es friert seit %d Nächten.", post.streaks.FrostNights))
+			post.annotations = append(post.annotations, chartAnnotation{section: "frost_streak", value: annotateTMin,
+				label: tr(config, "%s Frostnacht in Folge", ordinal(config, post.streaks.FrostNights))})
 		}
 	}
-	if consecutiveRainDays >= drySpellThreshold {
-		weatherText += fmt.Sprintf("\nEs regnet seit %d Tagen jeden Tag.", consecutiveRainDays)
+	if config.HeatStreakEnabled {
+		post.streaks.HeatDays = countHeatStreak(db, config, startYesterday)
+		if post.streaks.HeatDays >= 2 {
+			post.add("heat_streak", tr(config, "Das ist der %s Hitzetag in Folge.", ordinal(config, post.streaks.HeatDays)))
+			post.annotations = append(post.annotations, chartAnnotation{section: "heat_streak", value: annotateTMax,
+				label: tr(config, "%s Hitzetag in Folge", ordinal(config, post.streaks.HeatDays))})
+		}
+	}
+	post.add("felt_temperature", formatFeltTemperature(statsY, config))
+	if !math.IsNaN(statsY.windRun) {
+		post.add("wind_run", tr(config, "Windweg: %s", unitOf(config, quantityDistance).format(statsY.windRun, 0)))
+	}
+	post.add("et", formatET(db, loc, config, startYesterday, statsY))
+	post.add("month_to_date", formatMonthToDate(db, loc, config, startYesterday))
+	post.add("month_extremes", formatMonthExtremes(db, config, startYesterday))
+	post.add("month_ranking", formatMonthRanking(db, loc, config, startYesterday))
+	post.add("year_to_date_rain", formatYearToDateRain(db, loc, config, startYesterday))
+	post.add("rain_year", formatRainYear(db, loc, config, startYesterday))
+	post.add("temp_anomaly", formatTempAnomaly(db, config, startYesterday))
+	post.add("normal_departure", formatNormalDeparture(db, config, startYesterday))
+	post.add("percentiles", formatPercentiles(db, config, startYesterday, statsY))
+	post.add("dwd_comparison", formatDWDComparison(store, config, startYesterday, statsY))
+	post.add("storm_day", formatStormDay(db, config, startYesterday, statsY))
+	post.add("gust_factor", formatGustFactor(statsY, config))
+	post.add("growing_degree_days", formatGrowingDegreeDays(db, config, startYesterday, statsY))
+	post.add("chill_hours", formatChillHours(db, config, startYesterday))
+	post.add("energy_degree_days", formatEnergyDegreeDays(db, config, startYesterday, statsY))
+	post.add("pressure", formatPressure(statsY.pressure, config))
+	post.add("anniversaries", formatAnniversaries(store, config, now))
+	post.add("dewpoint", formatDewpoint(statsY, config))
+	post.add("cloud_base", formatCloudBase(statsY, config))
+	// Hinweise; als eigener Warn-Post markierte Hinweise gehen nur mit Mastodon getrennt raus
+	for _, a := range evaluateAdvisories(config, statsY, getMorningValues(db, config, startYesterday, now), now) {
+		if a.separate && config.MastodonServer != "" && config.MastodonToken != "" {
+			post.alerts = append(post.alerts, a.text)
+			continue
+		}
+		post.add("advisories", a.text)
+	}
+	post.add("soil", formatSoil(statsY, config))
+	post.add("leaf_wetness", formatLeafWetness(statsY, config, startYesterday))
+	post.add("air_quality", formatAirQuality(statsY, config))
+	if block, ok := formatOnThisDay(db, loc, config, startYesterday); ok {
+		post.sections = append(post.sections, postSection{name: "on_this_day", block: &block})
+	}
+
+	// Stationsrekorde stehen am Anfang des Posts
+	if line, annotations := formatAllTimeRecords(db, store, config, startYesterday, statsY); line != "" {
+		post.sections = append([]postSection{{name: "records", text: line}}, post.sections...)
+		post.annotations = append(post.annotations, annotations...)
+	}
+	// Der Satz zum Tag steht ganz oben
+	daySummary := formatDaySummary(config, statsY, startYesterday)
+	if daySummary != "" {
+		post.sections = append([]postSection{{name: "day_summary", text: daySummary}}, post.sections...)
 	}
+	post.arrange(config)
+
+	external := fetchExternalData(store, config.ExternalData, time.Duration(config.ExternalDataCacheMinutes)*time.Minute)
+	post.data = newTemplateData(config, startYesterday, statsY, statsV, "", external)
+	post.data.DaySummary = daySummary
+	post.render(config, markup{})
 
 	// Emojis basierend auf Wetterbedingungen
 	var emojis []string
@@ -579,7 +1985,16 @@ func runWeatherPosting(dbPath string, config Config, testMode bool, loopMode boo
 	if statsY.tMax < 0 {
 		emojis = append(emojis, "🧊 ")
 	}
-	if statsY.tMin >= 20 {
+	if hasLightning(statsY, config) {
+		emojis = append(emojis, "⛈️ ")
+	}
+	if isStormDay(statsY, config) {
+		emojis = append(emojis, stormEmoji(statsY)+" ")
+	}
+	if !math.IsNaN(statsY.snowDepth) && statsY.snowDepth > 0 {
+		emojis = append(emojis, "☃️ ")
+	}
+	if isTropicalNight(statsY, config) {
 		emojis = append(emojis, "🌙 ")
 	}
 
@@ -589,64 +2004,18 @@ func runWeatherPosting(dbPath string, config Config, testMode bool, loopMode boo
 		emojiString = strings.Join(emojis, " ") + " "
 	}
 
-	title := fmt.Sprintf(`%sWetterstatistik für Overath %s: Temperatur %.1f bis %.1f °C (Vortag: %.1f bis %.1f°C)`,
-		emojiString,
-		startYesterday.Format("02.01.2006"),
-		statsY.tMax, statsY.tMin, statsV.tMax,
-		statsV.tMin)
+	title := emojiString + tr(config, `Wetterstatistik für %s %s: Temperatur %s bis %s %s (Vortag: %s bis %s%s)`,
+		config.StationName, formatDate(config, startYesterday),
+		tempUnit.number(statsY.tMax, 1), tempUnit.number(statsY.tMin, 1), tempUnit.symbol,
+		tempUnit.number(statsV.tMax, 1), tempUnit.number(statsV.tMin, 1), tempUnit.symbol)
 
-	// Ausgabe
-	fmt.Printf("Statistik für Overath %s: (Vortag)\n", startYesterday.Format("02.01.2006"))
-	fmt.Printf("  Höchsttemperatur:         %.1f °C (%.1f °C)\n", statsY.tMax, statsV.tMax)
-	fmt.Printf("  Tiefsttemperatur:         %.1f °C (%.1f °C)\n", statsY.tMin, statsV.tMin)
-	fmt.Printf("  Niederschlag:             %.1f mm (%.1f mm)\n", statsY.rainSum, statsV.rainSum)
-	fmt.Printf("  Stunden mit Sonnenschein: %d h (%d h)\n", statsY.sunHours, statsV.sunHours)
-
-	if testMode && noaaFile != "" {
-		noaaRain, err := parseNoaaRain(noaaFile, yesterday)
-		if err != nil {
-			fmt.Printf("NOAA-Report-Vergleich: Fehler: %v\n", err)
-		} else {
-			fmt.Printf("NOAA-Report: Tagesregenmenge für %s: %.1f mm\n", yesterday.Format("02.01.2006"), noaaRain)
-			if math.Abs(noaaRain-statsY.rainSum) < 0.01 {
-				fmt.Printf("Vergleich: ✅ Werte stimmen überein.\n")
-			} else {
-				fmt.Printf("Vergleich: ❌ Werte unterscheiden sich! (DB: %.2f mm, NOAA: %.2f mm)\n", statsY.rainSum, noaaRain)
-			}
-		}
-	}
-
-	// Lemmy-Posting (nur wenn nicht im Test-Modus)
-	if !testMode && config.LemmyPassword != "CHANGEME" {
-		lemmyPostWithRetry(config, title, weatherText, loopMode)
-	} else if testMode {
-		fmt.Printf("\n=== TEST-MODUS: Lemmy-Post würde so aussehen ===\n")
-		fmt.Printf("Titel: %s\n", title)
-		fmt.Printf("Body:\n%s\n", weatherText)
-		fmt.Printf("=== ENDE TEST-MODUS ===\n")
-		fmt.Printf("\n=== TEST-MODUS: Mastodon-Konfiguration ===\n")
-		fmt.Printf("Server: %s\nToken: %s\nVisibility: %s\n", config.MastodonServer, config.MastodonToken, config.MastodonVisibility)
-		fmt.Printf("=== ENDE MASTODON-KONFIG ===\n")
-		if config.MastodonServer != "" && config.MastodonToken != "" {
-			mastodonText := title + "\n" + weatherText
-			fmt.Printf("\n=== TEST-MODUS: Mastodon-Post wird simuliert ===\n")
-			fmt.Printf("%s\n", mastodonText)
-			fmt.Printf("=== ENDE TEST-MODUS MASTODON ===\n")
-			_ = mastodonCreatePost(config.MastodonServer, config.MastodonToken, mastodonText, config.MastodonVisibility)
-		}
-		return
-	} else {
-		log.Printf("Lemmy-Posting übersprungen (Passwort nicht konfiguriert)")
-	}
-
-	// Mastodon-Posting (optional, unabhängig von Lemmy)
-	mastodonErr := error(nil)
-	if config.MastodonServer != "" && config.MastodonToken != "" {
-		mastodonErr = mastodonCreatePost(config.MastodonServer, config.MastodonToken, title+"\n"+weatherText, config.MastodonVisibility)
-		if mastodonErr != nil {
-			log.Printf("Fehler beim Mastodon-Post: %v", mastodonErr)
-		} else {
-			log.Printf("Wetterstatistik erfolgreich an Mastodon gepostet!")
-		}
+	// Titel-Template: .Title ist dort der Standardtitel, in den übrigen Templates der gemeinsame Titel
+	for i, e := range emojis {
+		emojis[i] = strings.TrimSpace(e)
 	}
+	post.data.Emoji = strings.Join(emojis, " ")
+	post.data.Title = title
+	post.title = renderTemplate("title", config.TitleTemplate, post.data, title)
+	post.data.Title = post.title
+	return post
 }
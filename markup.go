@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Textformate der Plattformen (text_formats)
+const (
+	textFormatPlain    = "plain"
+	textFormatMarkdown = "markdown"
+)
+
+// markup formatiert Auszeichnungen im Textformat einer Plattform: Lemmy stellt Markdown dar,
+// Mastodon zeigt es als Rohtext an und bekommt daher reinen Text
+type markup struct {
+	markdown bool
+}
+
+// markupFor liefert die Auszeichnung für eine Plattform; ohne Eintrag in text_formats gilt reiner Text
+func markupFor(config Config, platform string) markup {
+	return markup{markdown: config.TextFormats[platform] == textFormatMarkdown}
+}
+
+// validateTextFormats prüft text_formats
+func validateTextFormats(config Config) error {
+	for platform, format := range config.TextFormats {
+		if format != textFormatPlain && format != textFormatMarkdown {
+			return fmt.Errorf("unbekanntes Textformat %q für %s (text_formats: plain, markdown)", format, platform)
+		}
+	}
+	return nil
+}
+
+// bold hebt einen Text hervor (nur Markdown)
+func (m markup) bold(s string) string {
+	if m.markdown {
+		return "**" + s + "**"
+	}
+	return s
+}
+
+// textBlock ist ein Abschnitt aus Überschrift (optional) und Zeilen, der in Markdown als Tabelle erscheint
+type textBlock struct {
+	title   string
+	columns []string   // Spaltenüberschriften der Tabelle
+	rows    [][]string // Zellen je Zeile (Markdown)
+	lines   []string   // je Zeile der reine Text
+}
+
+// block formatiert einen textBlock: als Überschrift mit Zeilen bzw. in Markdown als fette Überschrift mit Tabelle
+func (m markup) block(b textBlock) string {
+	if !m.markdown {
+		return strings.TrimPrefix(b.title+"\n"+strings.Join(b.lines, "\n"), "\n")
+	}
+	var sb strings.Builder
+	if b.title != "" {
+		sb.WriteString(m.bold(b.title) + "\n\n")
+	}
+	sb.WriteString("| " + strings.Join(b.columns, " | ") + " |\n")
+	sb.WriteString(strings.Repeat("|---", len(b.columns)) + "|")
+	for _, row := range b.rows {
+		cells := make([]string, len(row))
+		for i, c := range row {
+			cells[i] = strings.ReplaceAll(c, "|", `\|`)
+		}
+		sb.WriteString("\n| " + strings.Join(cells, " | ") + " |")
+	}
+	return sb.String()
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// mastodonMaxMedia ist die Höchstzahl an Anhängen je Mastodon-Status
+const mastodonMaxMedia = 4
+
+// mastodonMedia ist ein zu Mastodon hochgeladenes Medium
+type mastodonMedia struct {
+	ID  string `json:"id"`
+	URL string `json:"url"` // leer, solange Mastodon das Medium noch verarbeitet
+}
+
+// mastodonUploadMedia lädt ein Bild mit Beschreibung und Bildausschnitt über /api/v2/media hoch. Große Dateien
+// verarbeitet Mastodon im Hintergrund (HTTP 202); dann ist URL leer und mastodonWaitForMedia wartet darauf.
+func mastodonUploadMedia(server, token string, m mediaAttachment) (mastodonMedia, error) {
+	fields := map[string]string{"description": m.Description}
+	if m.Focus != "" {
+		fields["focus"] = m.Focus
+	}
+	throttle.wait(server)
+	body, _, err := uploadMultipart(server+"/api/v2/media", "file", m, fields, map[string]string{"Authorization": "Bearer " + token})
+	if err != nil {
+		return mastodonMedia{}, err
+	}
+	return parseMastodonMedia(body)
+}
+
+// mastodonGetMedia fragt den Stand eines hochgeladenen Mediums ab; während der Verarbeitung (HTTP 206) ist URL leer
+func mastodonGetMedia(server, token, id string) (mastodonMedia, error) {
+	body, status, err := mastodonMediaRequest("GET", server+"/api/v1/media/"+url.PathEscape(id), token, nil)
+	if err != nil {
+		return mastodonMedia{}, err
+	}
+	media, err := parseMastodonMedia(body)
+	if status == http.StatusPartialContent {
+		media.URL = ""
+	}
+	return media, err
+}
+
+// mastodonUpdateMedia ändert Beschreibung und Bildausschnitt eines hochgeladenen, noch nicht geposteten Mediums;
+// leere Angaben bleiben unverändert
+func mastodonUpdateMedia(server, token, id, description, focus string) error {
+	form := url.Values{}
+	if description != "" {
+		form.Set("description", description)
+	}
+	if focus != "" {
+		form.Set("focus", focus)
+	}
+	_, _, err := mastodonMediaRequest("PUT", server+"/api/v1/media/"+url.PathEscape(id), token, form)
+	return err
+}
+
+// mastodonWaitForMedia wartet, bis Mastodon das Medium verarbeitet hat, und fragt dazu mit wachsendem Abstand
+// (1 bis 5 Sekunden) nach; nach timeout gibt es einen Fehler. Die Abfragen lesen nur den Stand des eigenen Uploads
+// und zählen daher nicht zum API-Budget, das sonst schon ein einzelnes großes Bild aufbrauchen könnte.
+func mastodonWaitForMedia(server, token string, media mastodonMedia, timeout time.Duration) (mastodonMedia, error) {
+	deadline := time.Now().Add(timeout)
+	delay := time.Second
+	for media.URL == "" {
+		if time.Now().Add(delay).After(deadline) {
+			return media, fmt.Errorf("Medium %s nach %v noch nicht verarbeitet", media.ID, timeout)
+		}
+		time.Sleep(delay)
+		if delay < 5*time.Second {
+			delay *= 2
+		}
+		var err error
+		if media, err = mastodonGetMedia(server, token, media.ID); err != nil {
+			return media, err
+		}
+	}
+	return media, nil
+}
+
+// mastodonUploadAll lädt die Bilder eines Posts zu Mastodon hoch (höchstens mastodonMaxMedia) und wartet auf ihre
+// Verarbeitung (mastodon_media_timeout). Jeder Upload kostet einen Aufruf aus dem API-Budget; der Aufrufer hat den
+// Aufruf für den Status vorher verbucht, so dass ein knappes Budget nur Bilder, nicht den Post kostet.
+// Fehlgeschlagene Uploads werden protokolliert; der Post erscheint dann ohne das Bild.
+func mastodonUploadAll(store *sql.DB, config Config, media []mediaAttachment) []string {
+	timeout := time.Duration(config.MastodonMediaTimeout) * time.Second
+	var ids []string
+	for i, m := range media {
+		if i >= mastodonMaxMedia {
+			log.Printf("Warnung: Mastodon erlaubt höchstens %d Bilder je Post – %s entfällt", mastodonMaxMedia, m.Path)
+			continue
+		}
+		img, err := fitImage(config, platformMastodon, m)
+		if err != nil {
+			log.Printf("Warnung: Bild für Mastodon nicht angepasst: %v", err)
+		} else if img.Path != m.Path {
+			defer removeMedia([]mediaAttachment{img})
+		}
+		err = spendAPICall(store, config, platformMastodon)
+		var uploaded mastodonMedia
+		if err == nil {
+			uploaded, err = mastodonUploadMedia(config.MastodonServer, config.MastodonToken, img)
+		}
+		if err == nil {
+			uploaded, err = mastodonWaitForMedia(config.MastodonServer, config.MastodonToken, uploaded, timeout)
+		}
+		if err != nil {
+			log.Printf("Warnung: Bild-Upload zu Mastodon fehlgeschlagen: %v", err)
+			continue
+		}
+		ids = append(ids, uploaded.ID)
+	}
+	return ids
+}
+
+// parseMastodonMedia liest die Antwort der Medien-API
+func parseMastodonMedia(body []byte) (mastodonMedia, error) {
+	var media mastodonMedia
+	if err := json.Unmarshal(body, &media); err != nil || media.ID == "" {
+		return media, fmt.Errorf("unerwartete Antwort der Medien-API: %s", string(body))
+	}
+	return media, nil
+}
+
+// mastodonMediaRequest sendet eine Anfrage an die Medien-API, bei form als Formular, und liefert Antwort und HTTP-Status
+func mastodonMediaRequest(method, target, token string, form url.Values) ([]byte, int, error) {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, resp.StatusCode, fmt.Errorf("Medien-API HTTP %d - Antwort: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, resp.StatusCode, nil
+}
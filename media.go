@@ -0,0 +1,437 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// mediaAttachment ist ein Bild, das einem Post angehängt wird, mit der Bildbeschreibung (Alt-Text) für
+// Screenreader. Die Beschreibung wird aus den zugrundeliegenden Daten erzeugt und über das Beschreibungsfeld
+// der jeweiligen Plattform übermittelt.
+type mediaAttachment struct {
+	Path        string // Bilddatei
+	ContentType string // z.B. "image/png"
+	Description string // Alt-Text
+	Focus       string // Bildausschnitt für Vorschauen als "x,y" (je -1 bis 1, Mastodon), leer = Bildmitte
+	Link        string // Lemmy: Link des Posts, zu dem das Bild als Vorschaubild gesetzt wird; leer = Bild ist der Link
+}
+
+// writeMediaFile legt die Bilddaten als temporäre Datei ab; pattern wie bei os.CreateTemp, z.B. "temperatur-*.png".
+// Die Datei wird nach dem Posten mit removeMedia gelöscht.
+func writeMediaFile(pattern string, data []byte, contentType, description string) (mediaAttachment, error) {
+	f, err := os.CreateTemp("", "weewxstats2social-"+pattern)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return mediaAttachment{}, err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return mediaAttachment{}, err
+	}
+	return mediaAttachment{Path: f.Name(), ContentType: contentType, Description: description}, nil
+}
+
+// chartMedia gibt ein Diagramm als PNG-Anhang mit Alt-Text aus. Mit chart_dir wird es dort zusätzlich als
+// name.svg abgelegt; ein Fehler dabei verhindert den Anhang nicht.
+func chartMedia(config Config, d *chartDrawing, name, description string) (mediaAttachment, error) {
+	if config.ChartDir != "" {
+		if err := writeChartSVG(config.ChartDir, name, d.svg(chartDarkPalette(config))); err != nil {
+			log.Printf("Warnung: SVG-Diagramm %s konnte nicht geschrieben werden: %v", name, err)
+		}
+	}
+	data, err := d.png()
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	return writeMediaFile(name+"-*.png", data, "image/png", description)
+}
+
+// writeChartSVG schreibt das SVG-Diagramm data als name.svg in das Verzeichnis dir; eine ältere Datei wird ersetzt
+func writeChartSVG(dir, name string, data []byte) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(dir, name+".svg")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// removeMedia löscht die temporären Bilddateien
+func removeMedia(media []mediaAttachment) {
+	for _, m := range media {
+		if err := os.Remove(m.Path); err != nil && !os.IsNotExist(err) {
+			log.Printf("Warnung: Bilddatei %s konnte nicht gelöscht werden: %v", m.Path, err)
+		}
+	}
+}
+
+// dailyImages sind die Bilder zum Tagespost: die Tagesdiagramme, das animierte Tagesdiagramm für
+// animated_chart_publishers und die übrigen Bilder (pressure_chart, grafana_panels)
+type dailyImages struct {
+	charts, animated, extra []mediaAttachment
+}
+
+// forPlatform liefert die Bilder für die Posts einer Plattform; mit animated_chart_publishers ersetzt das animierte
+// Tagesdiagramm dort die Tagesdiagramme
+func (img dailyImages) forPlatform(config Config, platform string) []mediaAttachment {
+	charts := img.charts
+	if len(img.animated) > 0 && animatedChartEnabled(config, platform) {
+		charts = img.animated
+	}
+	return append(append([]mediaAttachment{}, charts...), img.extra...)
+}
+
+// all liefert alle Bilder, z.B. zum Löschen
+func (img dailyImages) all() []mediaAttachment {
+	return append(append(append([]mediaAttachment{}, img.charts...), img.animated...), img.extra...)
+}
+
+// dailyMedia erzeugt die Bilder zum Tagespost (Tagesdiagramme, animated_chart_publishers, pressure_chart,
+// grafana_panels). Die eigenen Tagesdiagramme beschriften die Rekorde und Serien des Posts (annotations). Fehler
+// verhindern den Post nicht, das Bild entfällt dann mit einer Warnung.
+func dailyMedia(db *sql.DB, loc *time.Location, config Config, day time.Time, annotations []chartAnnotation) dailyImages {
+	var img dailyImages
+	img.charts = dailyChartsMedia(db, loc, config, day, annotations)
+	if len(config.AnimatedChartPublishers) > 0 {
+		if m, err := animatedChartMedia(db, loc, config, day, annotations); err != nil {
+			log.Printf("Warnung: Animiertes Tagesdiagramm nicht verfügbar: %v", err)
+		} else {
+			img.animated = []mediaAttachment{m}
+		}
+	}
+	img.extra = append(pressureMedia(db, config, day), grafanaMedia(config, postTypeDaily, day, day.AddDate(0, 0, 1))...)
+	return img
+}
+
+// dailyChartsMedia liefert die Tagesdiagramme: skin_images, sonst temperature_chart und wind_rose bzw. combined_chart
+func dailyChartsMedia(db *sql.DB, loc *time.Location, config Config, day time.Time, annotations []chartAnnotation) []mediaAttachment {
+	var media []mediaAttachment
+	if len(config.SkinImages) > 0 {
+		if media = skinMedia(config, day); len(media) > 0 {
+			return media
+		}
+		log.Printf("Warnung: keine aktuellen weewx-Bilder, es werden die eigenen Diagramme verwendet")
+	}
+	if config.CombinedChart {
+		if m, err := dailyChartMedia(db, loc, config, day, annotations); err != nil {
+			log.Printf("Warnung: Tagesdiagramm nicht verfügbar: %v", err)
+		} else {
+			media = append(media, m)
+		}
+		return media
+	}
+	if config.TemperatureChart {
+		if m, err := temperatureChartMedia(db, loc, config, day, annotations); err != nil {
+			log.Printf("Warnung: Temperaturdiagramm nicht verfügbar: %v", err)
+		} else {
+			media = append(media, m)
+		}
+	}
+	if config.WindRose && (sectionEnabled(config, "wind_run") || sectionEnabled(config, "gust_factor")) {
+		if m, err := windRoseMedia(db, config, day); err != nil {
+			log.Printf("Warnung: Windrose nicht verfügbar: %v", err)
+		} else {
+			media = append(media, m)
+		}
+	}
+	return media
+}
+
+// pressureMedia erzeugt das Luftdruckdiagramm zum Tagespost, wenn pressure_chart gesetzt ist und der Tag ein
+// Sturmtag war oder der Luftdruck sich stark geändert hat
+func pressureMedia(db *sql.DB, config Config, day time.Time) []mediaAttachment {
+	if !config.PressureChart {
+		return nil
+	}
+	due, err := pressureChartDue(db, config, day)
+	if err != nil {
+		log.Printf("Warnung: Luftdruckdiagramm nicht verfügbar: %v", err)
+		return nil
+	}
+	if !due {
+		return nil
+	}
+	m, err := pressureChartMedia(db, config, day)
+	if err != nil {
+		log.Printf("Warnung: Luftdruckdiagramm nicht verfügbar: %v", err)
+		return nil
+	}
+	return []mediaAttachment{m}
+}
+
+// pressureChartMedia zeichnet das Luftdruckdiagramm der 72 Stunden bis zum Ende des Tages und legt es mit Alt-Text
+// als PNG ab
+func pressureChartMedia(db *sql.DB, config Config, day time.Time) (mediaAttachment, error) {
+	end := day.AddDate(0, 0, 1)
+	series, err := getArchiveSeries(db, "barometer", end.Add(-pressureChartHours*time.Hour).Unix(), end.Unix())
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	d, err := renderPressureChart(config, chartThemeFor(config), day, series)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	return chartMedia(config, d, "luftdruck-"+day.Format("2006-01-02"), altTextPressure(config, day, series))
+}
+
+// rangeMedia erzeugt die Bilder zur Zusammenfassung des Zeitraums [from, to] (rain_chart, climate_diagram für die
+// zwölf Monate bis zum Monat von to, grafana_panels)
+func rangeMedia(db *sql.DB, loc *time.Location, config Config, from, to time.Time) []mediaAttachment {
+	var media []mediaAttachment
+	if config.RainChart {
+		if m, err := rainChartMedia(db, loc, config, from, to); err != nil {
+			log.Printf("Warnung: Niederschlagsdiagramm nicht verfügbar: %v", err)
+		} else {
+			media = append(media, m)
+		}
+	}
+	if config.ClimateDiagram {
+		if m, err := climateDiagramMedia(db, loc, config, time.Date(to.Year(), to.Month()-11, 1, 0, 0, 0, 0, loc)); err != nil {
+			log.Printf("Warnung: Klimadiagramm nicht verfügbar: %v", err)
+		} else {
+			media = append(media, m)
+		}
+	}
+	return append(media, grafanaMedia(config, postTypeRange, from, to.AddDate(0, 0, 1))...)
+}
+
+// yearMedia erzeugt die Bilder zum Jahresrückblick auf das Jahr year (climate_diagram, year_heatmap, grafana_panels)
+func yearMedia(db *sql.DB, loc *time.Location, config Config, year int) []mediaAttachment {
+	var media []mediaAttachment
+	if config.ClimateDiagram {
+		if m, err := climateDiagramMedia(db, loc, config, time.Date(year, 1, 1, 0, 0, 0, 0, loc)); err != nil {
+			log.Printf("Warnung: Klimadiagramm nicht verfügbar: %v", err)
+		} else {
+			media = append(media, m)
+		}
+	}
+	if config.YearHeatmap != "" {
+		if m, err := yearHeatmapMedia(db, loc, config, year); err != nil {
+			log.Printf("Warnung: Jahreskalender nicht verfügbar: %v", err)
+		} else {
+			media = append(media, m)
+		}
+	}
+	first := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
+	return append(media, grafanaMedia(config, postTypeYearReview, first, first.AddDate(1, 0, 0))...)
+}
+
+// yearHeatmapMedia zeichnet den Jahreskalender der Messgröße year_heatmap und legt ihn mit Alt-Text als PNG ab
+func yearHeatmapMedia(db *sql.DB, loc *time.Location, config Config, year int) (mediaAttachment, error) {
+	days, err := getYearDays(db, loc, config, year)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	d, err := renderYearHeatmap(config, chartThemeFor(config), days, config.YearHeatmap)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	name := fmt.Sprintf("kalender-%s-%d", config.YearHeatmap, year)
+	return chartMedia(config, d, name, altTextYearHeatmap(config, days, config.YearHeatmap))
+}
+
+// climateDiagramMedia zeichnet das Klimadiagramm der zwölf Monate ab first und legt es mit Alt-Text als PNG ab
+func climateDiagramMedia(db *sql.DB, loc *time.Location, config Config, first time.Time) (mediaAttachment, error) {
+	months, err := getClimateMonths(db, loc, config, first, 12)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	d, err := renderClimateDiagram(config, chartThemeFor(config), months)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	name := "klimadiagramm-" + months[0].month.Format("2006-01") + "-" + months[len(months)-1].month.Format("2006-01")
+	return chartMedia(config, d, name, altTextClimateDiagram(config, months))
+}
+
+// rainChartMedia zeichnet das Balkendiagramm des täglichen Niederschlags und legt es mit Alt-Text als PNG ab
+func rainChartMedia(db *sql.DB, loc *time.Location, config Config, from, to time.Time) (mediaAttachment, error) {
+	rain := getDailyRain(db, loc, config, from, to)
+	d, err := renderRainChart(config, chartThemeFor(config), from, rain)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	name := "niederschlag-" + from.Format("2006-01-02") + "-" + to.Format("2006-01-02")
+	return chartMedia(config, d, name, altTextDailyRain(config, from, rain))
+}
+
+// windRoseMedia zeichnet die Windrose des Tages und legt sie mit Alt-Text als PNG ab
+func windRoseMedia(db *sql.DB, config Config, day time.Time) (mediaAttachment, error) {
+	d, err := getWindDistribution(db, day.Unix(), day.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	drawing, err := renderWindRose(config, chartThemeFor(config), day, d)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	return chartMedia(config, drawing, "windrose-"+day.Format("2006-01-02"), altTextWindRose(config, d))
+}
+
+// temperatureChartMedia zeichnet das Temperaturdiagramm des Tages und legt es mit Alt-Text als PNG ab
+func temperatureChartMedia(db *sql.DB, loc *time.Location, config Config, day time.Time, annotations []chartAnnotation) (mediaAttachment, error) {
+	start, end := day.Unix(), day.AddDate(0, 0, 1).Unix()
+	series, err := getTemperatureSeries(db, start, end)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	d, err := renderTemperatureChart(config, chartThemeFor(config), day, series, annotations)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	hourly, err := getHourlyTemperatures(db, loc, start, end)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	description := withAnnotations(config, altTextTemperature(config, hourly), annotations, annotateTMax, annotateTMin)
+	return chartMedia(config, d, "temperatur-"+day.Format("2006-01-02"), description)
+}
+
+// dailyChartMedia zeichnet das kombinierte Tagesdiagramm und legt es mit Alt-Text als PNG ab
+func dailyChartMedia(db *sql.DB, loc *time.Location, config Config, day time.Time, annotations []chartAnnotation) (mediaAttachment, error) {
+	s, err := getDailySeries(db, loc, day)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	d, err := renderDailyChart(config, chartThemeFor(config), day, s, annotations)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	hourly, err := getHourlyTemperatures(db, loc, day.Unix(), day.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	return chartMedia(config, d, "tag-"+day.Format("2006-01-02"), altTextDailyChart(config, loc, day, s, hourly, annotations))
+}
+
+// animatedChartMedia zeichnet das animierte Tagesdiagramm und legt es mit Alt-Text als GIF ab; mit chart_dir
+// entsteht davon kein SVG
+func animatedChartMedia(db *sql.DB, loc *time.Location, config Config, day time.Time, annotations []chartAnnotation) (mediaAttachment, error) {
+	s, err := getDailySeries(db, loc, day)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	frames, names, err := renderAnimatedChart(config, chartThemeFor(config), day, s, annotations)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	data, err := encodeAnimatedGIF(frames, config.AnimatedChartDelay)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	hourly, err := getHourlyTemperatures(db, loc, day.Unix(), day.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	description := altTextAnimatedChart(config, loc, day, s, hourly, annotations, names)
+	return writeMediaFile("tag-"+day.Format("2006-01-02")+"-*.gif", data, "image/gif", description)
+}
+
+// summaryCardMedia zeichnet die Übersichtskarte des Tages als Vorschaubild für Lemmy (summary_card) und legt sie
+// mit Alt-Text als PNG ab. Mit einem Link für den Tagespost (detail_urls) wird die Karte dessen Vorschaubild.
+func summaryCardMedia(config Config, day time.Time, s dayStats) (mediaAttachment, error) {
+	d, err := renderSummaryCard(config, chartThemeFor(config), day, s)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	m, err := chartMedia(config, d, "karte-"+day.Format("2006-01-02"), altTextSummaryCard(config, day, s))
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	m.Link = config.DetailURLs[detailDaily]
+	return m, nil
+}
+
+// printMedia zeigt im Test-Modus die erzeugten Bilder an; die Dateien bleiben zur Ansicht erhalten
+func printMedia(media []mediaAttachment) {
+	for _, m := range media {
+		fmt.Printf("\n=== TEST-MODUS: Bild %s ===\nAlt-Text: %s\n", m.Path, m.Description)
+		if m.Link != "" {
+			fmt.Printf("Vorschaubild zu: %s\n", m.Link)
+		}
+		fmt.Printf("=== ENDE BILD ===\n")
+	}
+}
+
+// uploadMultipart sendet die Bilddatei als multipart/form-data im Feld field, zusammen mit den Textfeldern
+// fields, und liefert Antwort und HTTP-Status. header enthält zusätzliche Kopfzeilen wie die Anmeldung.
+func uploadMultipart(url, field string, m mediaAttachment, fields, header map[string]string) ([]byte, int, error) {
+	data, err := os.ReadFile(m.Path)
+	if err != nil {
+		return nil, 0, err
+	}
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := w.WriteField(name, value); err != nil {
+			return nil, 0, err
+		}
+	}
+	part := make(textproto.MIMEHeader)
+	part.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, field, filepath.Base(m.Path)))
+	part.Set("Content-Type", m.ContentType)
+	fw, err := w.CreatePart(part)
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, err := fw.Write(data); err != nil {
+		return nil, 0, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequest("POST", url, &body)
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	resp, err := (&http.Client{Timeout: 60 * time.Second}).Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, resp.StatusCode, fmt.Errorf("Upload HTTP %d - Antwort: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, resp.StatusCode, nil
+}
+
+// lemmyUploadImage lädt ein Bild zum Bilddienst (pict-rs) der Lemmy-Instanz hoch und liefert dessen URL
+func lemmyUploadImage(serverURL, jwt string, m mediaAttachment) (string, error) {
+	throttle.wait(serverURL)
+	body, _, err := uploadMultipart(serverURL+"/pictrs/image", "images[]", m, nil,
+		map[string]string{"Authorization": "Bearer " + jwt, "Cookie": "jwt=" + jwt})
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Files []struct {
+			File string `json:"file"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || len(result.Files) == 0 {
+		return "", fmt.Errorf("unerwartete Antwort beim Bild-Upload: %s", string(body))
+	}
+	return serverURL + "/pictrs/image/" + result.Files[0].File, nil
+}
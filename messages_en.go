@@ -0,0 +1,321 @@
+package main
+
+// englishMessages sind die englischen Post-Texte, Schlüssel ist der deutsche Text bzw. das deutsche Format.
+// Die Reihenfolge der Argumente lässt sich mit %[n]d ändern.
+var englishMessages = map[string]string{
+	// Tagespost
+	`Wetterstatistik für %s %s: Temperatur %s bis %s %s (Vortag: %s bis %s%s)`:                     `Weather statistics for %s %s: temperature %s to %s %s (day before: %s to %s %s)`,
+	`Niederschlag: %s (Vortag: %s), %s`:                                                            `Precipitation: %s (day before: %s), %s`,
+	"Details: %s":                                                                                  "Details: %s",
+	"🌧️ Das war ein Starkregentag (mindestens %s Niederschlag).":                                   "🌧️ That was a heavy rain day (at least %s of precipitation).",
+	"Temperaturverlauf von %s bis %s, Maximum um %d Uhr, Minimum um %d Uhr":                        "Temperature curve from %s to %s, maximum at %d:00, minimum at %d:00",
+	"Niederschlag je Stunde, %s zwischen %d und %d Uhr":                                            "Hourly precipitation, %s between %d:00 and %d:00",
+	"Niederschlag je Stunde: kein Niederschlag":                                                    "Hourly precipitation: no precipitation",
+	"Niederschlag je Stunde, insgesamt %s in %d Stunden, am meisten zwischen %d und %d Uhr mit %s": "Hourly precipitation, %s in total over %d hours, most between %d:00 and %d:00 with %s",
+	"Niederschlag vom %s bis %s":                                                                   "Precipitation from %s to %s",
+	"Niederschlag je Tag vom %s bis %s":                                                            "Daily precipitation from %s to %s",
+	": kein Niederschlag":                                                                          ": no precipitation",
+	", %s am %s":                                                                                   ", %s on %s",
+	", insgesamt %s an %d Tagen, am meisten am %s mit %s":                                          ", %s in total on %d days, most on %s with %s",
+	"Windrose am %s":                                                                               "Wind rose on %s",
+	"Windstille %.0f%%":                                                                            "Calm %.0f%%",
+	"Windrose: Windstille":                                                                         "Wind rose: calm",
+	"Windrose: vorherrschend aus %s (%.0f %%), %.0f %% Windstille":                                 "Wind rose: prevailing from the %s (%.0f %%), %.0f %% calm",
+	"O":                                     "E",
+	"Nord":                                  "north",
+	"Nordost":                               "northeast",
+	"Ost":                                   "east",
+	"Südost":                                "southeast",
+	"Süd":                                   "south",
+	"Südwest":                               "southwest",
+	"West":                                  "west",
+	"Nordwest":                              "northwest",
+	"Temperatur am %s":                      "Temperature on %s",
+	"Klimadiagramm %s bis %s":               "Climate diagram %s to %s",
+	"Mittel %s":                             "mean %s",
+	"wärmster Monat %s (%s)":                "warmest month %s (%s)",
+	"kältester Monat %s (%s)":               "coldest month %s (%s)",
+	"nassester Monat %s (%s)":               "wettest month %s (%s)",
+	"trockenster Monat %s (%s)":             "driest month %s (%s)",
+	"aride Monate: %d":                      "arid months: %d",
+	"Tagesmitteltemperatur %d":              "Daily mean temperature %d",
+	"Niederschlag %d":                       "Precipitation %d",
+	"Mo":                                    "Mon",
+	"Mi":                                    "Wed",
+	"Fr":                                    "Fri",
+	"Kalender der Tagesmitteltemperatur %d": "Calendar of daily mean temperature %d",
+	": wärmster Tag %s (%s), kältester Tag %s (%s)":     ": warmest day %s (%s), coldest day %s (%s)",
+	"Kalender des Niederschlags %d":                     "Calendar of precipitation %d",
+	": %d Tage mit Niederschlag, nassester Tag %s (%s)": ": %d days with precipitation, wettest day %s (%s)",
+	"Wetter":                          "Weather",
+	"Max":                             "High",
+	"Min":                             "Low",
+	"Regen":                           "Rain",
+	"Sonne":                           "Sun",
+	"Grafana-Diagramm vom %s bis %s":  "Grafana chart from %s to %s",
+	"Grafana-Diagramm vom %s":         "Grafana chart of %s",
+	"Wetterdiagramm der Station (%s)": "Weather chart of the station (%s)",
+	"Luftdruck vom %s bis %s":         "Air pressure from %s to %s",
+	": von %.0f auf %.0f hPa, Tiefstwert %.1f hPa am %s um %d Uhr": ": from %.0f to %.0f hPa, lowest %.1f hPa on %s at %d:00",
+	"Wetter am %s":                            "Weather on %s",
+	"Wetter am %s (%d Bilder im Wechsel)":     "Weather on %s (%d alternating frames)",
+	"Temperatur":                              "Temperature",
+	"Sonnenstrahlung":                         "Solar radiation",
+	"Wind und Böen":                           "Wind and gusts",
+	"Sonnenstrahlung bis %.0f W/m² um %d Uhr": "Solar radiation up to %.0f W/m² at %d:00",
+	"Böen bis %s um %d Uhr":                   "Gusts up to %s at %d:00",
+	"Temperaturverlauf: %s (%s bis %s)":       "Temperature curve: %s (%s to %s)",
+	"%s (%.0f %% beleuchtet)":                 "%s (%.0f %% illuminated)",
+	"Vollmond am %s":                          "full moon on %s",
+	"Neumond am %s":                           "new moon on %s",
+	"Neumond":                                 "New moon",
+	"Zunehmende Sichel":                       "Waxing crescent",
+	"Erstes Viertel":                          "First quarter",
+	"Zunehmender Mond":                        "Waxing gibbous",
+	"Vollmond":                                "Full moon",
+	"Abnehmender Mond":                        "Waning gibbous",
+	"Letztes Viertel":                         "Last quarter",
+	"Abnehmende Sichel":                       "Waning crescent",
+	"Sonnenaufgang %s, Sonnenuntergang %s, Tageslänge %s (%s)":     "Sunrise %s, sunset %s, day length %s (%s)",
+	"so lang wie vor einer Woche":                                  "as long as a week ago",
+	"1 Minute länger als vor einer Woche":                          "1 minute longer than a week ago",
+	"1 Minute kürzer als vor einer Woche":                          "1 minute shorter than a week ago",
+	"%d Minuten länger als vor einer Woche":                        "%d minutes longer than a week ago",
+	"%d Minuten kürzer als vor einer Woche":                        "%d minutes shorter than a week ago",
+	"Stunden mit Sonnenschein: %d h (Vortag: %d h)":                "Hours with sunshine: %d h (day before: %d h)",
+	"Sonnenscheindauer: %s (Vortag: %s)":                           "Sunshine duration: %s (day before: %s)",
+	"Sonnenenergie: %.2f kWh/m²":                                   "Solar energy: %.2f kWh/m²",
+	" (Vortag: %.2f kWh/m²)":                                       " (day before: %.2f kWh/m²)",
+	"Höchstwert um %s Uhr":                                         "High at %s",
+	"Tiefstwert um %s Uhr":                                         "Low at %s",
+	"Windweg: %s":                                                  "Wind run: %s",
+	"Höchstwert gefühlt bis %s (Hitzeindex)":                       "High felt like up to %s (heat index)",
+	"Tiefstwert gefühlt bis %s (Windchill)":                        "Low felt like down to %s (wind chill)",
+	"Taupunkt: %s bis %s %s":                                       "Dew point: %s to %s %s",
+	"Es war schwül (Taupunkt bis %s).":                             "It was muggy (dew point up to %s).",
+	"☁️ Geschätzte Wolkenbasis: %s bis %s %s":                      "☁️ Estimated cloud base: %s to %s %s",
+	" (zeitweise Nebel möglich)":                                   " (fog possible at times)",
+	"Verdunstung (ET): %s":                                         "Evapotranspiration (ET): %s",
+	", Wasserbilanz im Monat: %s (Regen %s − ET %s)":               ", water balance this month: %s (rain %s − ET %s)",
+	"⛈️ Blitze: %.0f erfasst":                                      "⛈️ Lightning: %.0f strikes detected",
+	", nächster in %s Entfernung":                                  ", closest %s away",
+	"🍃 Blattnässe: %.1f h":                                         "🍃 Leaf wetness: %.1f h",
+	"🌱 Boden: ":                                                    "🌱 Soil: ",
+	"%s bis %s %s":                                                 "%s to %s %s",
+	"Feuchte %.0f bis %.0f cb":                                     "moisture %.0f to %.0f cb",
+	"🏠 Innen:":                                                     "🏠 Indoors:",
+	" %s bis %s %s":                                                " %s to %s %s",
+	" Luftfeuchte %.0f bis %.0f %%":                                " humidity %.0f to %.0f %%",
+	" Luftqualität: %s (%s)":                                       " Air quality: %s (%s)",
+	"Luftdruck: %.1f hPa":                                          "Pressure: %.1f hPa",
+	"Luftdruck: %.1f hPa %s %s (%+.1f hPa von morgens bis abends)": "Pressure: %.1f hPa %s %s (%+.1f hPa from morning to evening)",
+	", Tendenz %+.1f hPa in %d h":                                  ", tendency %+.1f hPa in %d h",
+	"Luftdruck: %.1f hPa %s %s (%+.1f hPa in %d h)":                "Pressure: %.1f hPa %s %s (%+.1f hPa in %d h)",
+	"Böenfaktor: %.1f (Spitzenböe %s, Mittel %s)":                  "Gust factor: %.1f (peak gust %s, mean %s)",
+	" – ungewöhnlich böig":                                         " – unusually gusty",
+	"❄️ Neuschnee: %s":                                             "❄️ New snow: %s",
+	"☃️ Schneehöhe: %s":                                            "☃️ Snow depth: %s",
+	"Das war der erste Schnee der Saison!":                         "That was the first snow of the season!",
+	"📏 %s gegenüber dem langjährigen Mittel (%s)":                  "📏 %s compared with the long-term mean (%s)",
+	" (hier %s)":                                                   " (here %s)",
+
+	// Niederschlag
+	"Kurzer Schauer (%s mit Niederschlag)":                 "Short shower (%s of precipitation)",
+	"Regen über %d Stunden verteilt (%s mit Niederschlag)": "Rain spread over %d hours (%s of precipitation)",
+	"Stärkster Regen zwischen %d und %d Uhr (%s)":          "Heaviest rain between %d:00 and %d:00 (%s)",
+	", maximale Regenrate %s/h":                            ", maximum rain rate %s/h",
+	"Niederschlag %s: %s":                                  "Precipitation %s: %s",
+	"seit Jahresbeginn":                                    "since January 1",
+	"im Abflussjahr (seit %s)":                             "in the water year (since %s)",
+	" (Vorjahr bis %s: %s":                                 " (last year to %s: %s",
+
+	// Serien und Trockenperioden
+	"Es hat nach %d Tagen wieder geregnet.":                                        "It rained again after %d days.",
+	"Es hat seit %d Tagen nicht mehr geregnet.":                                    "It has not rained for %d days.",
+	" Das ist die längste Trockenperiode seit Messbeginn (bisher %d Tage bis %s).": " That is the longest dry spell since records began (previously %d days to %s).",
+	"Es regnet seit %d Tagen jeden Tag.":                                           "It has rained every day for %d days.",
+	"Es friert seit %d Nächten.":                                                   "It has been freezing for %d nights.",
+	"Das ist der %s Hitzetag in Folge.":                                            "That is the %s hot day in a row.",
+
+	// Kenntage und Frost
+	"%s %s in diesem Monat": "%s %s this month",
+	"Eistag":                "ice day",
+	"Eistage":               "ice days",
+	"Frosttag":              "frost day",
+	"Frosttage":             "frost days",
+	"Hitzetag":              "hot day",
+	"Hitzetage":             "hot days",
+	"Sommertag":             "summer day",
+	"Sommertage":            "summer days",
+	"Tropennacht":           "tropical night",
+	"Tropennächte":          "tropical nights",
+	"🌙 Die Nacht zum %s war eine Tropennacht: Zwischen %d und %d Uhr fiel die Temperatur nicht unter %s.": "🌙 The night to %s was a tropical night: between %d:00 and %d:00 the temperature did not drop below %s.",
+	"❄️ Erster Frost der Saison":          "❄️ First frost of the season",
+	"🌱 Letzter Frost des Frühjahrs am %s": "🌱 Last spring frost on %s",
+	" (Vorjahr: %s)":                      " (last year: %s)",
+	" (Vorjahr: kein Frost)":              " (last year: no frost)",
+	"❄️ Spätfrostgefahr: Tiefstwert %s bei nassen Blättern – Blüten und Jungpflanzen schützen": "❄️ Late frost risk: low of %s with wet leaves – protect blossoms and young plants",
+
+	// Gradtage
+	"Wachstumsgradtage (Basis %s): %s":               "Growing degree days (base %s): %s",
+	"Wachstumsgradtage (Basis %s): %s (seit %s: %s)": "Growing degree days (base %s): %s (since %s: %s)",
+	"Kältestunden (%s): %.1f h":                      "Chill hours (%s): %.1f h",
+	"Kältestunden (%s): %.1f h (seit %s: %.0f h)":    "Chill hours (%s): %.1f h (since %s: %.0f h)",
+	"Heizgradtage (G%.0f/%.0f): %.1f":                "Heating degree days (G%.0f/%.0f): %.1f",
+	"Kühlgradtage (Basis %s): %s":                    "Cooling degree days (base %s): %s",
+	" (Monat bisher: %.1f)":                          " (month to date: %.1f)",
+	" (Monat bisher: %s)":                            " (month to date: %s)",
+
+	// Wind
+	" Sturmtag: ":          " Storm day: ",
+	"%s bis %s":            "%s up to %s",
+	"Böen bis %s":          "gusts up to %s",
+	" um %s Uhr":           " at %s",
+	" (%s Sturmtag im %s)": " (%s storm day in %s)",
+	"Windböen":             "strong gusts",
+	"stürmische Böen":      "gale-force gusts",
+	"Sturmböen":            "storm gusts",
+	"schwere Sturmböen":    "severe storm gusts",
+	"orkanartige Böen":     "violent storm gusts",
+	"Orkanböen":            "hurricane-force gusts",
+
+	// Luftdruck und Luftqualität
+	"steigend":        "rising",
+	"fallend":         "falling",
+	"gleichbleibend":  "steady",
+	"gut":             "good",
+	"mittelmäßig":     "fair",
+	"mäßig":           "moderate",
+	"schlecht":        "poor",
+	"sehr schlecht":   "very poor",
+	"extrem schlecht": "extremely poor",
+
+	// Vergleiche
+	"Tagesmittel %s: ":                  "Daily mean %s: ",
+	"%s wärmer als der":                 "%s warmer than the",
+	"%s kälter als der":                 "%s colder than the",
+	"genau im":                          "exactly at the",
+	" Schnitt der letzten %d Tage (%s)": " average of the last %d days (%s)",
+	"📊 %s %s: unter den %s %.0f %% aller %s seit %d": "📊 %s %s: among the %s %.0f %% of all %s since %d",
+	"Höchstwert":   "High",
+	"Niederschlag": "Precipitation",
+	"wärmsten":     "warmest",
+	"kühlsten":     "coolest",
+	"nassesten":    "wettest",
+	"%stage":       "%s days",
+
+	// Monatswerte
+	"📆 %s bisher: %s":     "📆 %s so far: %s",
+	"Niederschlag %s":     "precipitation %s",
+	"Sonnenschein %.0f h": "sunshine %.0f h",
+	"Mitteltemperatur %s": "mean temperature %s",
+	"🌡️ %s bisher: wärmster Tag %s (%s), kältester Tag %s (%s)": "🌡️ %s so far: warmest day %s (%s), coldest day %s (%s)",
+	"🏆 %s %d im Vergleich der %smonate seit %d: %s":             "🏆 %s %d compared with the %ss since %d: %s",
+	", gleichauf mit %s": ", tied with %s",
+	"wärmster":           "warmest",
+	"kältester":          "coldest",
+	"nassester":          "wettest",
+	"trockenster":        "driest",
+	"sonnigster":         "sunniest",
+	"trübster":           "dullest",
+
+	// Rekorde
+	"🏆 Stationsrekord: %s seit Messbeginn %d mit %s (bisher %s am %s)": "🏆 Station record: %s since records began in %d with %s (previously %s on %s)",
+	"🏅 %s %s seit Messbeginn (%d Jahre Daten): %s, bisher %s am %s":    "🏅 %s %s since records began (%d years of data): %s, previously %s on %s",
+	"%d. %s":              "%[2]s %[1]d",
+	"%stag":               "%s day",
+	"heißester Tag":       "hottest day",
+	"kältester Tag":       "coldest day",
+	"nassester Tag":       "wettest day",
+	"sonnigster Tag":      "sunniest day",
+	"%s Sonnenschein":     "%s of sunshine",
+	"📅 Heute %s: %s %s":   "📅 Today %s: %s %s",
+	"Jahr":                "Year",
+	"Tiefstwert":          "Low",
+	"vor einem Jahr":      "one year ago",
+	"vor %d Jahren":       "%d years ago",
+	"Hitzerekord":         "heat record",
+	"Kälterekord":         "cold record",
+	"Niederschlagsrekord": "precipitation record",
+	"Sonnenscheinrekord":  "sunshine record",
+	"Böenrekord":          "gust record",
+	"📜 Der %d. %s in früheren Jahren – Spannweite %d–%d: %s … %s %s": "📜 %[2]s %[1]d in previous years – range %[3]d–%[4]d: %s … %s %s",
+
+	// Hinweise (Standardregeln und Niederschlagshinweis)
+	"😎 Hoher UV-Index ({value}) – Mittagssonne meiden":                          "😎 High UV index ({value}) – avoid the midday sun",
+	"😎 Sehr hoher UV-Index ({value}) – Mittagssonne meiden, Sonnenschutz nötig": "😎 Very high UV index ({value}) – avoid the midday sun, sun protection needed",
+	"😎 Extremer UV-Index ({value}) – Aufenthalt im Freien möglichst vermeiden":  "😎 Extreme UV index ({value}) – stay indoors if possible",
+	"Schnee wird vom unbeheizten Messer nicht erfasst.":                         "Snow is not captured by the unheated rain gauge.",
+
+	// Satz zum Tag (Standardregeln)
+	"Ein":           "A",
+	"Tag":           "day",
+	"frostiger":     "frosty",
+	"eisiger":       "icy",
+	"warmer":        "warm",
+	"heißer":        "hot",
+	"trüber":        "dull",
+	"sonniger":      "sunny",
+	"trockener":     "dry",
+	"regnerischer":  "rainy",
+	"nasser":        "wet",
+	"windiger":      "windy",
+	"stürmischer":   "stormy",
+	"Wintertag":     "winter day",
+	"Frühlingstag":  "spring day",
+	"Frühsommertag": "early summer day",
+	"Hochsommertag": "midsummer day",
+	"Spätsommertag": "late summer day",
+	"Herbsttag":     "autumn day",
+
+	// Rückblicke
+	"📅 Jahresrückblick %d für %s":                        "📅 Year in review %d for %s",
+	"📅 Wetter in %s vom %s bis %s":                       "📅 Weather in %s from %s to %s",
+	"Höchstwert: %s am %s":                               "High: %s on %s",
+	"Tiefstwert: %s am %s":                               "Low: %s on %s",
+	"Höchstwert: %s am %s, Tiefstwert: %s am %s":         "High: %s on %s, low: %s on %s",
+	"Niederschlag: %s an %d Tagen":                       "Precipitation: %s on %d days",
+	"Sonnigster Monat: %s mit %.0f Stunden Sonnenschein": "Sunniest month: %s with %.0f hours of sunshine",
+	"Längste Trockenperiode: %d Tage (%s–%s)":            "Longest dry spell: %d days (%s–%s)",
+	"Kenntage: ":                "Climate days: ",
+	"Mitteltemperatur: %s":      "Mean temperature: %s",
+	"Mitteltemperatur: ":        "Mean temperature: ",
+	"Sonnenscheindauer: %s":     "Sunshine duration: %s",
+	"Sonnenscheindauer: %.0f h": "Sunshine duration: %.0f h",
+	"Sonnenschein: ":            "Sunshine: ",
+	"Niederschlag: ":            "Precipitation: ",
+	"Für %d Tage fehlen Daten.": "Data is missing for %d days.",
+	"%s in %s":                  "%s in %s",
+	" (%s über dem Mittel der Vorjahre, Rang %d von %d)":  " (%s above the mean of previous years, rank %d of %d)",
+	" (%s unter dem Mittel der Vorjahre, Rang %d von %d)": " (%s below the mean of previous years, rank %d of %d)",
+	"Noch keine Vergleichswerte aus den Vorjahren.":       "No comparison with previous years yet.",
+	"Frühling":                     "Spring",
+	"Sommer":                       "Summer",
+	"Herbst":                       "Autumn",
+	"Winter":                       "Winter",
+	"Frühlings-Tagundnachtgleiche": "Spring equinox",
+	"Sommersonnenwende":            "Summer solstice",
+	"Herbst-Tagundnachtgleiche":    "Autumn equinox",
+	"Wintersonnenwende":            "Winter solstice",
+	"%s %d in %s":                  "%s %d in %s",
+	"%s am %s um %s Uhr – Rückblick auf das Vierteljahr seit dem %s": "%s on %s at %s – looking back at the quarter since %s",
+	"Tageslänge: %s am %s → %s heute (%s)":                           "Day length: %s on %s → %s today (%s)",
+	"(Daten für %d Tage)":                                            "(data for %d days)",
+
+	// Bestenlisten
+	"🏅 Die %d %s Tage %s in %s":   "🏅 The %d %s days %s in %s",
+	"regenreichsten":              "rainiest",
+	"im %s %d":                    "in %s %d",
+	"des Jahres %d":               "of %d",
+	"Platz":                       "Rank",
+	"Datum":                       "Date",
+	"Wert":                        "Value",
+	"seit Aufzeichnungsbeginn %d": "since records began in %d",
+
+	// Beschriftung von Rekorden und Serien in den Diagrammen
+	"Stationsrekord":         "station record",
+	"%s Regentag in Folge":   "%s rainy day in a row",
+	"%s Frostnacht in Folge": "%s frost night in a row",
+	"%s Hitzetag in Folge":   "%s hot day in a row",
+	"Sonnenschein":           "Sunshine",
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// monthValues sind Mitteltemperatur, Niederschlag und Sonnenscheindauer eines Kalendermonats
+type monthValues struct {
+	year     int
+	tMean    float64 // °C
+	rainSum  float64 // mm
+	sunHours float64 // NaN ohne Strahlungswerte
+}
+
+// getMonthValues berechnet die Monatswerte bis einschließlich to. ok ist false, wenn weniger als 90 % der Tage
+// Daten haben.
+func getMonthValues(db *sql.DB, loc *time.Location, config Config, year int, month time.Month, to time.Time) (monthValues, bool, error) {
+	m := monthValues{year: year}
+	start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	end := start.AddDate(0, 1, 0)
+	if to.Before(end) {
+		end = to.AddDate(0, 0, 1)
+	}
+	var days int
+	var err error
+	if m.rainSum, days, err = rainBetween(db, loc, config, start, end.AddDate(0, 0, -1)); err != nil {
+		return m, false, err
+	}
+	monthDays := start.AddDate(0, 1, -1).Day()
+	if days*10 < monthDays*9 {
+		return m, false, nil
+	}
+	if m.tMean, err = meanTemperature(db, config, start.Unix(), end.Unix()); err != nil || math.IsNaN(m.tMean) {
+		return m, false, err
+	}
+	sun, err := getSunshineMinutes(db, config, start.Unix(), end.Unix())
+	if err != nil {
+		return m, false, err
+	}
+	m.sunHours = sun / 60
+	return m, true, nil
+}
+
+// germanOrdinalPrefixes sind die Präfixe für Ranglisten-Adjektive ("drittwärmster"), Index = Rang
+var germanOrdinalPrefixes = []string{"", "", "zweit", "dritt", "viert", "fünft", "sechst", "siebt", "acht", "neunt", "zehnt", "elft", "zwölft"}
+
+// rankAdjective liefert das Adjektiv zum Rang, z.B. "drittwärmster" oder "13.-wärmster" (englisch "3rd warmest")
+func rankAdjective(config Config, rank int, adjective string) string {
+	if config.Language == languageEnglish {
+		if rank == 1 {
+			return tr(config, adjective)
+		}
+		return ordinal(config, rank) + " " + tr(config, adjective)
+	}
+	if rank < len(germanOrdinalPrefixes) {
+		return germanOrdinalPrefixes[rank] + adjective
+	}
+	return fmt.Sprintf("%d.-%s", rank, adjective)
+}
+
+// monthRankMetric ist eine Größe für die Monatsrangliste mit den Adjektiven für beide Enden
+type monthRankMetric struct {
+	high, low string // z.B. "wärmster", "kältester"
+	quantity  string
+	decimals  int
+	value     func(m monthValues) float64
+}
+
+var monthRankMetrics = []monthRankMetric{
+	{"wärmster", "kältester", quantityTemperature, 1, func(m monthValues) float64 { return m.tMean }},
+	{"nassester", "trockenster", quantityRain, 1, func(m monthValues) float64 { return m.rainSum }},
+	{"sonnigster", "trübster", quantitySunshine, 0, func(m monthValues) float64 { return m.sunHours }},
+}
+
+// formatMonthRanking ordnet am letzten Tag eines Monats den Monat unter dieselben Monate der Vorjahre ein,
+// z.B. "🏆 Oktober 2026 im Vergleich der Oktobermonate seit 2023: drittwärmster (10.6 °C), nassester (86.0 mm),
+// zweitsonnigster (111 h)". Genannt wird jeweils das nähere Ende der Rangliste. Gleiche Werte (in der angezeigten
+// Genauigkeit) teilen sich einen Rang und werden mit "gleichauf mit" benannt.
+func formatMonthRanking(db *sql.DB, loc *time.Location, config Config, day time.Time) string {
+	if config.MonthRankingMinYears <= 0 || day.AddDate(0, 0, 1).Day() != 1 {
+		return ""
+	}
+	var first sql.NullInt64
+	if err := db.QueryRow(`SELECT MIN(dateTime) FROM archive;`).Scan(&first); err != nil || !first.Valid {
+		return ""
+	}
+	current, ok, err := getMonthValues(db, loc, config, day.Year(), day.Month(), day)
+	if err != nil || !ok {
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warnung: Monatsrangliste nicht verfügbar: %v\n", err)
+		}
+		return ""
+	}
+	var previous []monthValues
+	for y := time.Unix(first.Int64, 0).In(loc).Year(); y < day.Year(); y++ {
+		m, ok, err := getMonthValues(db, loc, config, y, day.Month(), day)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warnung: Monatswerte %s %d nicht verfügbar: %v\n", germanMonths[day.Month()-1], y, err)
+			continue
+		}
+		if ok {
+			previous = append(previous, m)
+		}
+	}
+	if len(previous) == 0 || len(previous)+1 < config.MonthRankingMinYears {
+		return ""
+	}
+
+	var parts []string
+	for _, metric := range monthRankMetrics {
+		v := metric.value(current)
+		if math.IsNaN(v) {
+			continue
+		}
+		unit := unitOf(config, metric.quantity)
+		shown := unit.format(v, metric.decimals)
+		higher, lower := 1, 1
+		var tied []string
+		for _, m := range previous {
+			p := metric.value(m)
+			if math.IsNaN(p) {
+				continue
+			}
+			switch other := unit.format(p, metric.decimals); {
+			case other == shown:
+				tied = append(tied, fmt.Sprint(m.year))
+			case p > v:
+				higher++
+			default:
+				lower++
+			}
+		}
+		part := rankAdjective(config, higher, metric.high)
+		if lower < higher {
+			part = rankAdjective(config, lower, metric.low)
+		}
+		part += " (" + shown
+		if len(tied) > 0 {
+			part += tr(config, ", gleichauf mit %s", strings.Join(tied, ", "))
+		}
+		parts = append(parts, part+")")
+	}
+	return tr(config, "🏆 %s %d im Vergleich der %smonate seit %d: %s", monthName(config, day.Month()), day.Year(),
+		monthName(config, day.Month()), previous[0].year, strings.Join(parts, ", "))
+}
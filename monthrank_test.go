@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestRankAdjective(t *testing.T) {
+	tests := []struct {
+		language string
+		rank     int
+		want     string
+	}{
+		{"de", 1, "wärmster"},
+		{"de", 2, "zweitwärmster"},
+		{"de", 3, "drittwärmster"},
+		{"de", 12, "zwölftwärmster"},
+		{"de", 13, "13.-wärmster"},
+		{languageEnglish, 1, "warmest"},
+		{languageEnglish, 2, "2nd warmest"},
+		{languageEnglish, 11, "11th warmest"},
+		{languageEnglish, 23, "23rd warmest"},
+	}
+	for _, tt := range tests {
+		config := DefaultConfig()
+		config.Language = tt.language
+		if got := rankAdjective(config, tt.rank, "wärmster"); got != tt.want {
+			t.Errorf("%s, Rang %d: got %q, want %q", tt.language, tt.rank, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// monthToDate sind die laufenden Monatswerte vom Monatsersten bis einschließlich des berichteten Tages
+type monthToDate struct {
+	rainSum    float64 // mm
+	sunMinutes float64 // NaN ohne Strahlungswerte
+	tMean      float64 // NaN ohne Temperaturdaten
+}
+
+// meanTemperature liefert die mittlere Temperatur des Zeitraums aus allen Messwerten, NaN ohne Daten.
+// Mit daily_source "weewx" aus sum/count von archive_day_outTemp, sonst direkt aus archive.
+func meanTemperature(db *sql.DB, config Config, start, end int64) (float64, error) {
+	q := `SELECT SUM(sum) / SUM(count) FROM archive_day_outTemp WHERE dateTime >= ? AND dateTime < ?;`
+	if config.DailySource == dailySourceArchive {
+		q = `SELECT AVG(outTemp) FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	}
+	var mean sql.NullFloat64
+	if err := db.QueryRow(q, start, end).Scan(&mean); err != nil {
+		return math.NaN(), err
+	}
+	if !mean.Valid {
+		return math.NaN(), nil
+	}
+	return mean.Float64, nil
+}
+
+// getMonthToDate berechnet Niederschlag, Sonnenscheindauer und Mitteltemperatur vom Monatsersten bis einschließlich day
+func getMonthToDate(db *sql.DB, loc *time.Location, config Config, day time.Time) (monthToDate, error) {
+	m := monthToDate{sunMinutes: math.NaN(), tMean: math.NaN()}
+	monthStart := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+	end := time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc)
+
+	var err error
+	if m.rainSum, _, err = rainBetween(db, loc, config, monthStart, day); err != nil {
+		return m, err
+	}
+	if m.sunMinutes, err = getSunshineMinutes(db, config, monthStart.Unix(), end.Unix()); err != nil {
+		return m, err
+	}
+	m.tMean, err = meanTemperature(db, config, monthStart.Unix(), end.Unix())
+	return m, err
+}
+
+// formatMonthToDate erzeugt die Zeile mit den laufenden Monatswerten,
+// z.B. "📆 Oktober bisher: Niederschlag 42.3 mm, Sonnenschein 87 h, Mitteltemperatur 11.4 °C"
+func formatMonthToDate(db *sql.DB, loc *time.Location, config Config, day time.Time) string {
+	if !config.MonthToDateEnabled {
+		return ""
+	}
+	m, err := getMonthToDate(db, loc, config, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Monatswerte nicht verfügbar: %v\n", err)
+		return ""
+	}
+	parts := []string{tr(config, "Niederschlag %s", unitOf(config, quantityRain).format(m.rainSum, 1))}
+	if !math.IsNaN(m.sunMinutes) {
+		parts = append(parts, tr(config, "Sonnenschein %.0f h", m.sunMinutes/60))
+	}
+	if !math.IsNaN(m.tMean) {
+		parts = append(parts, tr(config, "Mitteltemperatur %s", unitOf(config, quantityTemperature).format(m.tMean, 1)))
+	}
+	return tr(config, "📆 %s bisher: %s", monthName(config, day.Month()), strings.Join(parts, ", "))
+}
+
+// formatMonthExtremes nennt den wärmsten und kältesten Tag des laufenden Monats bis einschließlich day,
+// z.B. "🌡️ Oktober bisher: wärmster Tag 12.10. (21.3 °C), kältester Tag 03.10. (2.1 °C)". Leer am Monatsersten.
+func formatMonthExtremes(db *sql.DB, config Config, day time.Time) string {
+	if !config.MonthExtremesEnabled {
+		return ""
+	}
+	monthStart := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	temps, err := dailyTemperatures(db, config, monthStart, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Extremwerte des Monats nicht verfügbar: %v\n", err)
+		return ""
+	}
+	if len(temps) < 2 {
+		return ""
+	}
+	warmest, coldest := temps[0], temps[0]
+	for _, t := range temps[1:] {
+		if t.tMax > warmest.tMax {
+			warmest = t
+		}
+		if t.tMin < coldest.tMin {
+			coldest = t
+		}
+	}
+	unit := unitOf(config, quantityTemperature)
+	return tr(config, "🌡️ %s bisher: wärmster Tag %s (%s), kältester Tag %s (%s)", monthName(config, day.Month()),
+		formatDayMonth(config, warmest.day), unit.format(warmest.tMax, 1), formatDayMonth(config, coldest.day), unit.format(coldest.tMin, 1))
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// moonPhase ist eine der acht Mondphasen mit Emoji, in der Reihenfolge ab Neumond
+type moonPhase struct {
+	emoji string
+	name  string
+}
+
+var moonPhases = []moonPhase{
+	{"🌑", "Neumond"},
+	{"🌒", "Zunehmende Sichel"},
+	{"🌓", "Erstes Viertel"},
+	{"🌔", "Zunehmender Mond"},
+	{"🌕", "Vollmond"},
+	{"🌖", "Abnehmender Mond"},
+	{"🌗", "Letztes Viertel"},
+	{"🌘", "Abnehmende Sichel"},
+}
+
+// lunarPhaseTerms sind die Korrekturterme (Koeffizient, Potenz von E, Vielfache von M, M', F, Ω) für Neumond und
+// Vollmond nach Meeus, Astronomical Algorithms, Kapitel 49; kleinere Terme entfallen (Genauigkeit wenige Minuten)
+var lunarPhaseTerms = map[bool][][6]float64{
+	false: { // Neumond
+		{-0.40720, 0, 0, 1, 0, 0}, {0.17241, 1, 1, 0, 0, 0}, {0.01608, 0, 0, 2, 0, 0}, {0.01039, 0, 0, 0, 2, 0},
+		{0.00739, 1, -1, 1, 0, 0}, {-0.00514, 1, 1, 1, 0, 0}, {0.00208, 2, 2, 0, 0, 0}, {-0.00111, 0, 0, 1, -2, 0},
+		{-0.00057, 0, 0, 1, 2, 0}, {0.00056, 1, 1, 2, 0, 0}, {-0.00042, 0, 0, 3, 0, 0}, {0.00042, 1, 1, 0, 2, 0},
+		{0.00038, 1, 1, 0, -2, 0}, {-0.00024, 1, -1, 2, 0, 0}, {-0.00017, 0, 0, 0, 0, 1},
+	},
+	true: { // Vollmond
+		{-0.40614, 0, 0, 1, 0, 0}, {0.17302, 1, 1, 0, 0, 0}, {0.01614, 0, 0, 2, 0, 0}, {0.01043, 0, 0, 0, 2, 0},
+		{0.00734, 1, -1, 1, 0, 0}, {-0.00515, 1, 1, 1, 0, 0}, {0.00209, 2, 2, 0, 0, 0}, {-0.00111, 0, 0, 1, -2, 0},
+		{-0.00057, 0, 0, 1, 2, 0}, {0.00056, 1, 1, 2, 0, 0}, {-0.00042, 0, 0, 3, 0, 0}, {0.00042, 1, 1, 0, 2, 0},
+		{0.00038, 1, 1, 0, -2, 0}, {-0.00024, 1, -1, 2, 0, 0}, {-0.00017, 0, 0, 0, 0, 1},
+	},
+}
+
+// lunation berechnet den Zeitpunkt von Neumond (k ganzzahlig) bzw. Vollmond (k + 0.5) der Lunation k seit
+// Januar 2000 nach Meeus, Kapitel 49
+func lunation(k float64) time.Time {
+	rad := math.Pi / 180
+	t := k / 1236.85
+	jde := 2451550.09766 + 29.530588861*k + 0.00015437*t*t - 0.000000150*t*t*t + 0.00000000073*t*t*t*t
+	e := 1 - 0.002516*t - 0.0000074*t*t
+	m := (2.5534 + 29.10535670*k - 0.0000014*t*t - 0.00000011*t*t*t) * rad
+	mm := (201.5643 + 385.81693528*k + 0.0107582*t*t + 0.00001238*t*t*t - 0.000000058*t*t*t*t) * rad
+	f := (160.7108 + 390.67050284*k - 0.0016118*t*t - 0.00000227*t*t*t + 0.000000011*t*t*t*t) * rad
+	omega := (124.7746 - 1.56375588*k + 0.0020672*t*t + 0.00000215*t*t*t) * rad
+	full := k-math.Floor(k) != 0
+	for _, term := range lunarPhaseTerms[full] {
+		jde += term[0] * math.Pow(e, term[1]) * math.Sin(term[2]*m+term[3]*mm+term[4]*f+term[5]*omega)
+	}
+	unix := (jde - 2440587.5) * 86400
+	return time.Unix(int64(math.Round(unix)), 0).Add(-deltaT)
+}
+
+// moonAt liefert für den Zeitpunkt t die Mondphase, den beleuchteten Anteil (0–1) sowie den nächsten Vollmond und Neumond
+func moonAt(t time.Time) (moonPhase, float64, time.Time, time.Time) {
+	years := float64(t.Unix()-time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()) / (365.25 * 86400)
+	k := math.Floor(years*12.3685) - 1
+	for !lunation(k + 1).After(t) {
+		k++
+	}
+	previous, next := lunation(k), lunation(k+1)
+	age := t.Sub(previous).Seconds() / next.Sub(previous).Seconds() // 0 = Neumond, 0.5 = Vollmond
+	phase := moonPhases[int(math.Round(age*float64(len(moonPhases))))%len(moonPhases)]
+	illuminated := (1 - math.Cos(2*math.Pi*age)) / 2
+	full := lunation(k + 0.5)
+	if !full.After(t) {
+		full = lunation(k + 1.5)
+	}
+	return phase, illuminated, full, next
+}
+
+// formatMoonPhase erzeugt die Zeile zur Mondphase zum Zeitpunkt now mit den Terminen von nächstem Vollmond und Neumond,
+// z.B. "🌔 Zunehmender Mond (78 % beleuchtet), Vollmond am 25.10., Neumond am 09.11."
+func formatMoonPhase(config Config, now time.Time) string {
+	if !config.MoonPhaseEnabled {
+		return ""
+	}
+	phase, illuminated, full, newMoon := moonAt(now)
+	line := phase.emoji + " " + tr(config, "%s (%.0f %% beleuchtet)", tr(config, phase.name), illuminated*100)
+	fullText := tr(config, "Vollmond am %s", formatDayMonth(config, full.In(now.Location())))
+	newText := tr(config, "Neumond am %s", formatDayMonth(config, newMoon.In(now.Location())))
+	if newMoon.Before(full) {
+		return line + ", " + newText + ", " + fullText
+	}
+	return line + ", " + fullText + ", " + newText
+}
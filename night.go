@@ -0,0 +1,42 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"time"
+)
+
+// nightWindow liefert die Nacht, die am Morgen des Tages day endet (z.B. Vortag 18 Uhr bis 6 Uhr).
+// Diese Nacht ist beim morgendlichen Lauf für den Vortag bereits vollständig.
+func nightWindow(loc *time.Location, config Config, day time.Time) (time.Time, time.Time) {
+	end := time.Date(day.Year(), day.Month(), day.Day(), config.TropicalNightTo, 0, 0, 0, loc)
+	start := time.Date(day.Year(), day.Month(), day.Day(), config.TropicalNightFrom, 0, 0, 0, loc).AddDate(0, 0, -1)
+	return start, end
+}
+
+// getNightMin ermittelt den Tiefstwert der Nacht, die am Morgen des Tages day endet
+func getNightMin(db *sql.DB, loc *time.Location, config Config, day time.Time) (float64, error) {
+	start, end := nightWindow(loc, config, day)
+	var tMin sql.NullFloat64
+	if err := db.QueryRow(`SELECT MIN(outTemp) FROM archive WHERE dateTime >= ? AND dateTime < ?;`, start.Unix(), end.Unix()).Scan(&tMin); err != nil {
+		return math.NaN(), err
+	}
+	if !tMin.Valid {
+		return math.NaN(), nil
+	}
+	return tMin.Float64, nil
+}
+
+// isTropicalNight prüft, ob die Nacht eine Tropennacht war (Tiefstwert der Nacht über der Schwelle)
+func isTropicalNight(s dayStats, config Config) bool {
+	return !math.IsNaN(s.nightMin) && s.nightMin >= config.TropicalNightTemp
+}
+
+// formatTropicalNight erzeugt den Satz zur Tropennacht, leer wenn die Nacht keine war
+func formatTropicalNight(s dayStats, config Config, day time.Time) string {
+	if !isTropicalNight(s, config) {
+		return ""
+	}
+	return tr(config, "🌙 Die Nacht zum %s war eine Tropennacht: Zwischen %d und %d Uhr fiel die Temperatur nicht unter %s.",
+		formatDayMonth(config, day), config.TropicalNightFrom, config.TropicalNightTo, unitOf(config, quantityTemperature).format(s.nightMin, 1))
+}
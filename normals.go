@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadMonthlyNormals liest die Monatsmittel der Temperatur (°C) aus einer Referenzdatei, z.B. den
+// vieljährigen Mittelwerten 1991–2020 des DWD:
+//
+//	Stations_id;Bezeichnung;Datenquelle;Jan.;Feb.;Mrz.;…;Dez.;Jahr;eor
+//	2667;Köln/Bonn;…;2.9;3.5;6.4;…;3.6;10.9;eor
+//
+// Felder sind durch Semikolon getrennt, Dezimalkomma ist erlaubt. Aus jeder Zeile werden die ersten zwölf Felder
+// nach den Kennungsfeldern als Januar bis Dezember gelesen; Zeilen mit weniger als zwölf Zahlen (Kopfzeile,
+// Kommentare) werden übersprungen. Mit station wird die Zeile gewählt, deren erstes Feld station ist, sonst die erste.
+func loadMonthlyNormals(path, station string) ([12]float64, error) {
+	var normals [12]float64
+	f, err := os.Open(path)
+	if err != nil {
+		return normals, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), ";")
+		if station != "" && strings.TrimSpace(fields[0]) != station {
+			continue
+		}
+		var values []float64
+		for _, field := range fields[1:] {
+			v, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(field), ",", "."), 64)
+			if err != nil {
+				if len(values) > 0 {
+					break // Zahlenblock zu Ende (z.B. "eor")
+				}
+				continue // Kennungsfelder
+			}
+			values = append(values, v)
+		}
+		if len(values) < 12 {
+			continue
+		}
+		copy(normals[:], values[:12])
+		return normals, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return normals, err
+	}
+	if station != "" {
+		return normals, fmt.Errorf("Station %s nicht in %s gefunden", station, path)
+	}
+	return normals, fmt.Errorf("keine Monatswerte in %s gefunden", path)
+}
+
+// monthMiddle liefert die Mitte des Monats, der als Bezugspunkt des Monatsmittels dient
+func monthMiddle(year int, month time.Month, loc *time.Location) time.Time {
+	start := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	return start.Add(start.AddDate(0, 1, 0).Sub(start) / 2)
+}
+
+// dailyNormal interpoliert die Monatsmittel linear zwischen den Monatsmitten auf den Tag day (Tagesmitte)
+func dailyNormal(normals [12]float64, day time.Time) float64 {
+	loc := day.Location()
+	t := time.Date(day.Year(), day.Month(), day.Day(), 12, 0, 0, 0, loc)
+	mid := monthMiddle(t.Year(), t.Month(), loc)
+	prevMonth := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, -1, 0)
+	from, to := prevMonth, time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	if !t.Before(mid) {
+		from, to = to, to.AddDate(0, 1, 0)
+	}
+	fromMid, toMid := monthMiddle(from.Year(), from.Month(), loc), monthMiddle(to.Year(), to.Month(), loc)
+	f := t.Sub(fromMid).Hours() / toMid.Sub(fromMid).Hours()
+	return normals[from.Month()-1] + f*(normals[to.Month()-1]-normals[from.Month()-1])
+}
+
+// formatNormalDeparture vergleicht das Tagesmittel mit dem langjährigen Mittel aus normals_file,
+// z.B. "📏 +1.8 °C gegenüber dem langjährigen Mittel (10.3 °C)"
+func formatNormalDeparture(db *sql.DB, config Config, day time.Time) string {
+	if config.NormalsFile == "" {
+		return ""
+	}
+	normals, err := loadMonthlyNormals(config.NormalsFile, config.NormalsStation)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Klimanormalwerte nicht verfügbar: %v\n", err)
+		return ""
+	}
+	mean, err := meanTemperature(db, config, day.Unix(), day.AddDate(0, 0, 1).Unix())
+	if err != nil || math.IsNaN(mean) {
+		return ""
+	}
+	normal := dailyNormal(normals, day)
+	unit := unitOf(config, quantityTemperature)
+	return tr(config, "📏 %s gegenüber dem langjährigen Mittel (%s)", unit.delta(mean-normal, 1, true), unit.format(normal, 1))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// formatOnThisDay erzeugt den Rückblick auf denselben Kalendertag in den früheren Jahren der Aufzeichnung,
+// z.B. "📜 Der 17. Oktober in früheren Jahren – Spannweite 2023–2025: -1.2 … 21.3 °C" mit einer Zeile je Jahr.
+// Erst ab on_this_day_min_years Jahren mit Daten, damit junge Stationen keinen dünnen Rückblick zeigen.
+// In Markdown erscheinen die Jahre als Tabelle; ok ist false, wenn es keinen Rückblick gibt.
+func formatOnThisDay(db *sql.DB, loc *time.Location, config Config, day time.Time) (textBlock, bool) {
+	b := textBlock{columns: []string{tr(config, "Jahr"), tr(config, "Tiefstwert"), tr(config, "Höchstwert"), tr(config, "Niederschlag")}}
+	if config.OnThisDayMinYears <= 0 {
+		return b, false
+	}
+	var first sql.NullInt64
+	if err := db.QueryRow(`SELECT MIN(dateTime) FROM archive;`).Scan(&first); err != nil || !first.Valid {
+		return b, false
+	}
+
+	temp, rain := unitOf(config, quantityTemperature), unitOf(config, quantityRain)
+	firstYear, lastYear := 0, 0
+	lo, hi := math.NaN(), math.NaN()
+	for y := time.Unix(first.Int64, 0).In(loc).Year(); y < day.Year(); y++ {
+		d := time.Date(y, day.Month(), day.Day(), 0, 0, 0, 0, loc)
+		if d.Day() != day.Day() {
+			continue // 29. Februar
+		}
+		temps, err := dailyTemperatures(db, config, d, d)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warnung: Rückblick %d nicht verfügbar: %v\n", y, err)
+			continue
+		}
+		if len(temps) == 0 {
+			continue
+		}
+		t := temps[0]
+		line := fmt.Sprintf("%d: %s … %s %s", y, temp.number(t.tMin, 1), temp.number(t.tMax, 1), temp.symbol)
+		row := []string{strconv.Itoa(y), temp.format(t.tMin, 1), temp.format(t.tMax, 1), "–"}
+		if sum, ok := rainOnDay(db, loc, config, d); ok {
+			line += ", " + rain.format(sum, 1)
+			row[3] = rain.format(sum, 1)
+		}
+		b.lines = append(b.lines, line)
+		b.rows = append(b.rows, row)
+		if firstYear == 0 {
+			firstYear = y
+		}
+		lastYear = y
+		if math.IsNaN(lo) || t.tMin < lo {
+			lo = t.tMin
+		}
+		if math.IsNaN(hi) || t.tMax > hi {
+			hi = t.tMax
+		}
+	}
+	if len(b.lines) < config.OnThisDayMinYears {
+		return b, false
+	}
+	b.title = tr(config, "📜 Der %d. %s in früheren Jahren – Spannweite %d–%d: %s … %s %s",
+		day.Day(), monthName(config, day.Month()), firstYear, lastYear, temp.number(lo, 1), temp.number(hi, 1), temp.symbol)
+	return b, true
+}
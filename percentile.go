@@ -0,0 +1,84 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+)
+
+// percentileMinDays ist die Mindestzahl an Tagen des Kalendermonats, ab der eine Einordnung aussagekräftig ist
+const percentileMinDays = 60
+
+// monthShare ermittelt, welcher Anteil (%) aller Tage des Kalendermonats von day seit firstYear (bis einschließlich day)
+// in series mindestens (higher) bzw. höchstens v erreicht hat, dazu die Zahl der Tage
+func monthShare(db *sql.DB, config Config, day time.Time, firstYear int, series dailyValues, higher bool, v float64) (float64, int, error) {
+	loc := day.Location()
+	count, matching := 0, 0
+	for y := firstYear; y <= day.Year(); y++ {
+		from := time.Date(y, day.Month(), 1, 0, 0, 0, 0, loc)
+		to := from.AddDate(0, 1, -1)
+		if y == day.Year() {
+			to = day
+		}
+		values, err := series(db, config, from, to)
+		if err != nil {
+			return math.NaN(), 0, err
+		}
+		for _, d := range values {
+			count++
+			if (higher && d.value >= v) || (!higher && d.value <= v) {
+				matching++
+			}
+		}
+	}
+	if count == 0 {
+		return math.NaN(), 0, nil
+	}
+	return float64(matching) / float64(count) * 100, count, nil
+}
+
+// formatPercentiles ordnet Höchstwert und Niederschlag des Tages in die Verteilung aller Tage desselben
+// Kalendermonats ein, z.B. "📊 Höchstwert 31.2 °C: unter den wärmsten 4 % aller Julitage seit 2023".
+// Genannt werden nur Werte innerhalb der obersten bzw. untersten percentile_threshold Prozent.
+func formatPercentiles(db *sql.DB, config Config, day time.Time, s dayStats) string {
+	if config.PercentileThreshold <= 0 {
+		return ""
+	}
+	first, ok := firstArchiveDay(db, day.Location())
+	if !ok {
+		return ""
+	}
+	since := first.Year()
+	monthDays := tr(config, "%stage", monthName(config, day.Month()))
+
+	checks := []struct {
+		label, quantity, rank string
+		value                 float64
+		series                dailyValues
+		higher                bool
+	}{
+		{"Höchstwert", quantityTemperature, "wärmsten", s.tMax, dailyMaxima, true},
+		{"Höchstwert", quantityTemperature, "kühlsten", s.tMax, dailyMaxima, false},
+		{"Niederschlag", quantityRain, "nassesten", s.rainSum, dailyRainSums, true},
+	}
+	var lines []string
+	for _, c := range checks {
+		if math.IsNaN(c.value) || (c.quantity == quantityRain && c.value <= 0) {
+			continue
+		}
+		share, count, err := monthShare(db, config, day, since, c.series, c.higher, c.value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warnung: Einordnung %s nicht möglich: %v\n", c.label, err)
+			continue
+		}
+		if count < percentileMinDays || math.IsNaN(share) || share > config.PercentileThreshold {
+			continue
+		}
+		lines = append(lines, tr(config, "📊 %s %s: unter den %s %.0f %% aller %s seit %d",
+			tr(config, c.label), unitOf(config, c.quantity).format(c.value, 1), tr(config, c.rank), math.Max(1, math.Ceil(share)), monthDays, since))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// pressureStats enthält den mittleren Luftdruck (reduziert auf Meereshöhe), die Änderung im Tagesverlauf und die
+// Tendenz zum Tagesende
+type pressureStats struct {
+	mean   float64 // hPa, NaN wenn keine Werte vorhanden
+	change float64 // hPa, Abendfenster minus Morgenfenster, NaN wenn eines der Fenster leer ist
+	trend  float64 // hPa, Änderung in den letzten pressure_tendency_hours Stunden vor dem letzten Wert, NaN ohne Vergleichswert
+}
+
+// pressureTendencySlack ist der größte Abstand eines Archivwerts vom Beginn des Tendenzfensters
+const pressureTendencySlack = 30 * 60
+
+// getPressureStats berechnet Tagesmittel, Tagesgang und Tendenz aus der barometer-Spalte.
+// Der Tagesgang vergleicht den Mittelwert des konfigurierten Abendfensters mit dem des Morgenfensters, die Tendenz
+// den letzten Wert des Tages mit dem Wert pressure_tendency_hours Stunden davor.
+func getPressureStats(db *sql.DB, loc *time.Location, config Config, start, end int64) (pressureStats, error) {
+	p := pressureStats{mean: math.NaN(), change: math.NaN(), trend: math.NaN()}
+
+	const qBarometer = `
+		SELECT dateTime, barometer
+		FROM archive
+		WHERE dateTime >= ? AND dateTime < ? AND barometer IS NOT NULL;`
+	rows, err := db.Query(qBarometer, start, end)
+	if err != nil {
+		return p, err
+	}
+	defer rows.Close()
+
+	var sum, morningSum, eveningSum float64
+	var count, morningCount, eveningCount int
+	var times []int64
+	var values []float64
+	for rows.Next() {
+		var ts int64
+		var baro float64
+		if err := rows.Scan(&ts, &baro); err != nil {
+			return p, err
+		}
+		sum += baro
+		count++
+		times, values = append(times, ts), append(values, baro)
+
+		h := time.Unix(ts, 0).In(loc).Hour()
+		if h >= config.PressureMorningFrom && h < config.PressureMorningTo {
+			morningSum += baro
+			morningCount++
+		}
+		if h >= config.PressureEveningFrom && h < config.PressureEveningTo {
+			eveningSum += baro
+			eveningCount++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return p, err
+	}
+
+	if count == 0 {
+		fmt.Fprintf(os.Stderr, "Warnung: Keine Luftdruckwerte (barometer) für Zeitraum %d-%d\n", start, end)
+		return p, nil
+	}
+	p.mean = sum / float64(count)
+	if morningCount > 0 && eveningCount > 0 {
+		p.change = eveningSum/float64(eveningCount) - morningSum/float64(morningCount)
+	}
+	p.trend = pressureTrend(times, values, int64(config.PressureTendencyHours)*3600)
+	return p, nil
+}
+
+// pressureTrend liefert die Änderung vom letzten Wert zum spätesten Wert, der mindestens window Sekunden davor liegt.
+// Liegt dieser mehr als pressureTendencySlack vor dem Fensterbeginn (Lücke im Archiv), ist die Tendenz NaN.
+// times ist aufsteigend sortiert.
+func pressureTrend(times []int64, values []float64, window int64) float64 {
+	if len(times) == 0 {
+		return math.NaN()
+	}
+	last := len(times) - 1
+	from := times[last] - window
+	for i := last; i >= 0; i-- {
+		if times[i] <= from {
+			if from-times[i] > pressureTendencySlack {
+				break
+			}
+			return values[last] - values[i]
+		}
+	}
+	return math.NaN()
+}
+
+// pressureTendency liefert Pfeilsymbol und Beschreibung für eine Luftdruckänderung
+func pressureTendency(trend, steadyThreshold float64) (string, string) {
+	switch {
+	case trend >= steadyThreshold:
+		return "↗", "steigend"
+	case trend <= -steadyThreshold:
+		return "↘", "fallend"
+	default:
+		return "→", "gleichbleibend"
+	}
+}
+
+// formatPressure erzeugt die Luftdruckzeile für den Post, leer wenn keine Daten vorliegen
+func formatPressure(p pressureStats, config Config) string {
+	if math.IsNaN(p.mean) {
+		return ""
+	}
+	if !math.IsNaN(p.change) {
+		arrow, desc := pressureTendency(p.change, config.PressureSteadyThreshold)
+		line := tr(config, "Luftdruck: %.1f hPa %s %s (%+.1f hPa von morgens bis abends)", p.mean, arrow, tr(config, desc), p.change)
+		if !math.IsNaN(p.trend) {
+			line += tr(config, ", Tendenz %+.1f hPa in %d h", p.trend, config.PressureTendencyHours)
+		}
+		return line
+	}
+	if !math.IsNaN(p.trend) {
+		arrow, desc := pressureTendency(p.trend, config.PressureSteadyThreshold)
+		return tr(config, "Luftdruck: %.1f hPa %s %s (%+.1f hPa in %d h)", p.mean, arrow, tr(config, desc), p.trend, config.PressureTendencyHours)
+	}
+	return tr(config, "Luftdruck: %.1f hPa", p.mean)
+}
+
+// validatePressureWindows prüft Morgen- und Abendfenster (Beginn 0–23, Ende nach dem Beginn bis 24, Morgen vor
+// Abend) und die Länge der Tendenz
+func validatePressureWindows(config Config) error {
+	windows := []struct {
+		name     string
+		from, to int
+	}{
+		{"pressure_morning_from/pressure_morning_to", config.PressureMorningFrom, config.PressureMorningTo},
+		{"pressure_evening_from/pressure_evening_to", config.PressureEveningFrom, config.PressureEveningTo},
+	}
+	for _, w := range windows {
+		if w.from < 0 || w.from > 23 || w.to <= w.from || w.to > 24 {
+			return fmt.Errorf("ungültiges Luftdruckfenster %d–%d Uhr (%s: Beginn 0–23, Ende nach dem Beginn bis 24)", w.from, w.to, w.name)
+		}
+	}
+	if config.PressureMorningTo > config.PressureEveningFrom {
+		return fmt.Errorf("Morgenfenster für den Luftdruck endet nach Beginn des Abendfensters (pressure_morning_to ≤ pressure_evening_from)")
+	}
+	if config.PressureTendencyHours < 1 || config.PressureTendencyHours > 24 {
+		return fmt.Errorf("ungültige Dauer der Luftdrucktendenz (pressure_tendency_hours 1–24)")
+	}
+	return nil
+}
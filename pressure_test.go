@@ -0,0 +1,51 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPressureTrend(t *testing.T) {
+	hour := int64(3600)
+	times := []int64{18 * hour, 19 * hour, 20 * hour, 21 * hour, 23*hour + 50*60}
+	values := []float64{1012, 1011, 1010, 1009, 1006.5}
+
+	// letzter Wert 23:50, Fensterbeginn 20:50 → spätester Wert davor 20:00, 50 Minuten zu früh
+	if got := pressureTrend(times, values, 3*hour); !math.IsNaN(got) {
+		t.Errorf("Lücke am Fensterbeginn: got %.1f, want NaN", got)
+	}
+	if got := pressureTrend(times[:4], values[:4], 3*hour); got != -3 {
+		t.Errorf("3 h bis 21 Uhr: got %.1f, want -3", got)
+	}
+	if got := pressureTrend(times[:4], values[:4], 1*hour); got != -1 {
+		t.Errorf("1 h bis 21 Uhr: got %.1f, want -1", got)
+	}
+	if got := pressureTrend(nil, nil, 3*hour); !math.IsNaN(got) {
+		t.Errorf("keine Werte: got %.1f, want NaN", got)
+	}
+}
+
+func TestValidatePressureWindows(t *testing.T) {
+	if err := validatePressureWindows(DefaultConfig()); err != nil {
+		t.Fatalf("Standard: %v", err)
+	}
+	for name, change := range map[string]func(*Config){
+		"Ende vor Beginn":      func(c *Config) { c.PressureMorningFrom, c.PressureMorningTo = 9, 6 },
+		"leeres Fenster":       func(c *Config) { c.PressureEveningTo = c.PressureEveningFrom },
+		"Beginn 24":            func(c *Config) { c.PressureEveningFrom, c.PressureEveningTo = 24, 25 },
+		"negativer Beginn":     func(c *Config) { c.PressureMorningFrom = -1 },
+		"Morgen nach Abend":    func(c *Config) { c.PressureMorningFrom, c.PressureMorningTo = 19, 22 },
+		"Tendenz ohne Stunden": func(c *Config) { c.PressureTendencyHours = 0 },
+	} {
+		config := DefaultConfig()
+		change(&config)
+		if err := validatePressureWindows(config); err == nil {
+			t.Errorf("%s: kein Fehler", name)
+		}
+	}
+	config := DefaultConfig()
+	config.PressureEveningFrom, config.PressureEveningTo = 21, 24
+	if err := validatePressureWindows(config); err != nil {
+		t.Errorf("Abendfenster bis Mitternacht: %v", err)
+	}
+}
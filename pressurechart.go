@@ -0,0 +1,103 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// pressureChartHours ist der Zeitraum des Luftdruckdiagramms bis zum Ende des Tages
+const pressureChartHours = 72
+
+// getPressureChange liefert die größte Luftdruckänderung (hPa) im Zeitraum als Differenz von Höchst- und Tiefstwert,
+// NaN ohne Werte
+func getPressureChange(db *sql.DB, start, end int64) (float64, error) {
+	var hi, lo sql.NullFloat64
+	err := db.QueryRow(`SELECT MAX(barometer), MIN(barometer) FROM archive WHERE dateTime >= ? AND dateTime < ?;`, start, end).Scan(&hi, &lo)
+	if err != nil || !hi.Valid || !lo.Valid {
+		return math.NaN(), err
+	}
+	return hi.Float64 - lo.Float64, nil
+}
+
+// pressureChartDue prüft, ob der Tag day ein Luftdruckdiagramm bekommt: bei einem Sturmtag (storm_beaufort) oder
+// einer Luftdruckänderung ab pressure_chart_threshold
+func pressureChartDue(db *sql.DB, config Config, day time.Time) (bool, error) {
+	start, end := day.Unix(), day.AddDate(0, 0, 1).Unix()
+	gust, _, err := getWindExtremes(db, start, end)
+	if err != nil {
+		return false, err
+	}
+	if !math.IsNaN(gust) && gust >= stormThreshold(config) {
+		return true, nil
+	}
+	change, err := getPressureChange(db, start, end)
+	if err != nil {
+		return false, err
+	}
+	return !math.IsNaN(change) && change >= config.PressureChartThreshold, nil
+}
+
+// renderPressureChart zeichnet den Luftdruck (hPa) der 72 Stunden bis zum Ende des Tages day als PNG mit markiertem
+// Höchst- und Tiefstwert; der Tag selbst ist hinterlegt
+func renderPressureChart(config Config, theme chartTheme, day time.Time, series []chartPoint) (*chartDrawing, error) {
+	hi, lo := seriesMax(series), -1
+	for i, p := range series {
+		if !math.IsNaN(p.y) && (lo < 0 || p.y < series[lo].y) {
+			lo = i
+		}
+	}
+	if hi < 0 {
+		return nil, fmt.Errorf("keine Luftdruckwerte")
+	}
+	end := day.AddDate(0, 0, 1)
+	start := end.Add(-pressureChartHours * time.Hour)
+	yMin, yMax, step := niceRange(series[lo].y, series[hi].y, 6)
+
+	c := newChartCanvas(theme)
+	c.setRange(float64(start.Unix()), float64(end.Unix()), yMin, yMax)
+	c.band(float64(day.Unix()), float64(end.Unix()), mixColor(theme.background, theme.grid, 0.5))
+	c.title(tr(config, "Luftdruck vom %s bis %s", formatDayMonth(config, start), formatDate(config, day)), "hPa")
+	c.yAxis(step, axisLabel(step))
+	var ticks []chartTick
+	for t := start; t.Before(end); t = t.Add(12 * time.Hour) {
+		label := "12"
+		if t.Hour() == 0 {
+			label = formatDayMonth(config, t)
+		}
+		ticks = append(ticks, chartTick{x: float64(t.Unix()), label: label})
+	}
+	c.xAxis(ticks)
+	c.polyline(series, theme.line)
+	c.marker(series[hi].x, series[hi].y, fmt.Sprintf("%.1f", series[hi].y), true, theme.high)
+	c.marker(series[lo].x, series[lo].y, fmt.Sprintf("%.1f", series[lo].y), false, theme.low)
+	return c.d, nil
+}
+
+// altTextPressure beschreibt das Luftdruckdiagramm, z.B. "Luftdruck vom 06.10. bis 08.10.2024: von 1012 auf
+// 1003 hPa, Tiefstwert 987.4 hPa am 08.10. um 14 Uhr"
+func altTextPressure(config Config, day time.Time, series []chartPoint) string {
+	end := day.AddDate(0, 0, 1)
+	start := end.Add(-pressureChartHours * time.Hour)
+	text := tr(config, "Luftdruck vom %s bis %s", formatDayMonth(config, start), formatDate(config, day))
+	first, last, lo := -1, -1, -1
+	for i, p := range series {
+		if math.IsNaN(p.y) {
+			continue
+		}
+		if first < 0 {
+			first = i
+		}
+		last = i
+		if lo < 0 || p.y < series[lo].y {
+			lo = i
+		}
+	}
+	if first < 0 {
+		return text
+	}
+	at := time.Unix(int64(series[lo].x), 0).In(day.Location())
+	return text + tr(config, ": von %.0f auf %.0f hPa, Tiefstwert %.1f hPa am %s um %d Uhr",
+		series[first].y, series[last].y, series[lo].y, formatDayMonth(config, at), at.Hour())
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// savePublishedPost merkt sich den zuletzt veröffentlichten Post eines Tages für spätere Vergleiche
+func savePublishedPost(store *sql.DB, day, title, body string) {
+	if store == nil {
+		return
+	}
+	const q = `INSERT OR REPLACE INTO published_posts (day, title, body, published) VALUES (?, ?, ?, ?);`
+	if _, err := store.Exec(q, day, title, body, time.Now().Unix()); err != nil {
+		log.Printf("Warnung: Veröffentlichter Post konnte nicht gespeichert werden: %v", err)
+	}
+}
+
+// lastPublishedPost liefert den zuletzt veröffentlichten Post eines Tages; ok ist false, wenn es keinen gibt
+func lastPublishedPost(store *sql.DB, day string) (title, body string, published time.Time, ok bool) {
+	if store == nil {
+		return
+	}
+	var ts int64
+	err := store.QueryRow(`SELECT title, body, published FROM published_posts WHERE day = ?;`, day).Scan(&title, &body, &ts)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("Warnung: Veröffentlichter Post konnte nicht gelesen werden: %v", err)
+		}
+		return "", "", time.Time{}, false
+	}
+	return title, body, time.Unix(ts, 0), true
+}
+
+// diffLines vergleicht zwei Texte zeilenweise (längste gemeinsame Teilfolge) und liefert
+// die Zeilen mit Präfix " " (unverändert), "-" (entfernt) bzw. "+" (hinzugefügt)
+func diffLines(old, new string) []string {
+	a, b := strings.Split(old, "\n"), strings.Split(new, "\n")
+	// lcs[i][j] = Länge der gemeinsamen Teilfolge von a[i:] und b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, " "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "-"+a[i])
+			i++
+		default:
+			out = append(out, "+"+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, "-"+a[i])
+	}
+	for ; j < len(b); j++ {
+		out = append(out, "+"+b[j])
+	}
+	return out
+}
+
+// printPublishedDiff zeigt im Test-Modus die Unterschiede zum zuletzt veröffentlichten Post desselben Tages
+func printPublishedDiff(store *sql.DB, day, title, body string) {
+	oldTitle, oldBody, published, ok := lastPublishedPost(store, day)
+	if !ok {
+		fmt.Printf("\n(Für %s wurde noch kein Post veröffentlicht – kein Vergleich möglich)\n", day)
+		return
+	}
+	fmt.Printf("\n=== TEST-MODUS: Unterschiede zum veröffentlichten Post vom %s ===\n", published.Format("02.01.2006 15:04"))
+	if oldTitle == title && oldBody == body {
+		fmt.Printf("Keine Unterschiede.\n")
+	} else {
+		for _, line := range diffLines(oldTitle+"\n"+oldBody, title+"\n"+body) {
+			fmt.Println(line)
+		}
+	}
+	fmt.Printf("=== ENDE DIFF ===\n")
+}
+
+// Veröffentlichungsziele für den Status pro Plattform
+const (
+	platformLemmy    = "lemmy"
+	platformMastodon = "mastodon"
+	platformWebhook  = "webhook"
+
+	platformMastodonAlert = "mastodon-alert" // Warn-Posts der Hinweisregeln
+)
+
+// isPublished prüft, ob der Post eines Tages auf einer Plattform bereits erfolgreich veröffentlicht wurde.
+// Ohne Zustandsspeicher gilt nichts als veröffentlicht, damit im Zweifel gepostet wird.
+func isPublished(store *sql.DB, day, platform string) bool {
+	if store == nil {
+		return false
+	}
+	var status string
+	err := store.QueryRow(`SELECT status FROM publish_status WHERE day = ? AND platform = ?;`, day, platform).Scan(&status)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("Warnung: Veröffentlichungsstatus für %s unbekannt: %v", platform, err)
+	}
+	return status == "ok"
+}
+
+// setPublishStatus vermerkt Erfolg (err == nil) oder Fehlschlag einer Veröffentlichung und zählt die Versuche
+func setPublishStatus(store *sql.DB, day, platform string, err error) {
+	if store == nil {
+		return
+	}
+	status, message := "ok", ""
+	if err != nil {
+		status, message = "failed", err.Error()
+	}
+	const q = `INSERT INTO publish_status (day, platform, status, message, attempts, updated) VALUES (?, ?, ?, ?, 1, ?)
+		ON CONFLICT(day, platform) DO UPDATE SET status = excluded.status, message = excluded.message,
+			attempts = attempts + 1, updated = excluded.updated;`
+	if _, dbErr := store.Exec(q, day, platform, status, message, time.Now().Unix()); dbErr != nil {
+		log.Printf("Warnung: Veröffentlichungsstatus für %s konnte nicht gespeichert werden: %v", platform, dbErr)
+	}
+}
+
+// printPublishStatus zeigt im Test-Modus, auf welchen Plattformen der Tag bereits veröffentlicht wurde
+func printPublishStatus(store *sql.DB, day string) {
+	if store == nil {
+		return
+	}
+	rows, err := store.Query(`SELECT platform, status, attempts FROM publish_status WHERE day = ? ORDER BY platform;`, day)
+	if err != nil {
+		log.Printf("Warnung: Veröffentlichungsstatus unbekannt: %v", err)
+		return
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var platform, status string
+		var attempts int
+		if err := rows.Scan(&platform, &status, &attempts); err != nil {
+			return
+		}
+		if status == "ok" {
+			fmt.Printf("Status %s: bereits veröffentlicht, wird beim nächsten Lauf übersprungen\n", platform)
+		} else {
+			fmt.Printf("Status %s: %d fehlgeschlagene Versuche, wird beim nächsten Lauf wiederholt\n", platform, attempts)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		want     []string
+	}{
+		{"gleich", "a\nb", "a\nb", []string{" a", " b"}},
+		{"Zeile geändert", "a\nb\nc", "a\nx\nc", []string{" a", "-b", "+x", " c"}},
+		{"Zeile hinzugefügt", "a\nc", "a\nb\nc", []string{" a", "+b", " c"}},
+		{"Zeile entfernt", "a\nb\nc", "a\nc", []string{" a", "-b", " c"}},
+		{"am Ende angehängt", "a", "a\nb", []string{" a", "+b"}},
+		{"alles neu", "a\nb", "c", []string{"-a", "-b", "+c"}},
+	}
+	for _, tt := range tests {
+		if got := diffLines(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// Arten von Qualitätsereignissen im Zustandsspeicher
+const (
+	qcGap           = "gap"            // Lücken in der archive-Tabelle
+	qcReject        = "qc_reject"      // Tageswerte wegen Unplausibilität verworfen
+	qcMismatch      = "mismatch"       // Abweichung zwischen NOAA-Report bzw. Selbstprüfung und Tageswert
+	qcPublishFailed = "publish_failed" // Veröffentlichung endgültig fehlgeschlagen
+)
+
+// germanMonths enthält die deutschen Monatsnamen, Index 0 = Januar
+var germanMonths = [...]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"}
+
+// recordQCEvent speichert ein Qualitätsereignis für den Monatsbericht.
+// Wiederholte Läufe für denselben Tag überschreiben den Eintrag statt ihn zu duplizieren.
+func recordQCEvent(store *sql.DB, day, kind, source string, count int, message string) {
+	if store == nil {
+		return
+	}
+	const q = `INSERT OR REPLACE INTO qc_events (day, kind, source, count, message, created) VALUES (?, ?, ?, ?, ?, ?);`
+	if _, err := store.Exec(q, day, kind, source, count, message, time.Now().Unix()); err != nil {
+		log.Printf("Warnung: QC-Ereignis konnte nicht gespeichert werden: %v", err)
+	}
+}
+
+// countArchiveGaps zählt Lücken in der archive-Tabelle, d.h. Abstände von mehr als zwei Archivintervallen
+func countArchiveGaps(db *sql.DB, start, end int64) (int, error) {
+	const q = `SELECT dateTime, interval FROM archive WHERE dateTime >= ? AND dateTime < ? ORDER BY dateTime;`
+	rows, err := db.Query(q, start, end)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	gaps := 0
+	var prev int64
+	for rows.Next() {
+		var ts int64
+		var interval sql.NullInt64
+		if err := rows.Scan(&ts, &interval); err != nil {
+			return 0, err
+		}
+		step := int64(5 * 60)
+		if interval.Valid && interval.Int64 > 0 {
+			step = interval.Int64 * 60
+		}
+		if prev != 0 && ts-prev > 2*step {
+			gaps++
+		}
+		prev = ts
+	}
+	return gaps, rows.Err()
+}
+
+// archiveRainSum summiert den Niederschlag direkt aus der archive-Tabelle (mm) zur Selbstprüfung der Tagessumme
+func archiveRainSum(db *sql.DB, start, end int64) (float64, error) {
+	var sum sql.NullFloat64
+	if err := db.QueryRow(`SELECT SUM(rain) FROM archive WHERE dateTime >= ? AND dateTime < ?;`, start, end).Scan(&sum); err != nil {
+		return 0, err
+	}
+	// Gleiche Korrektur wie bei archive_day_rain: Werte in cm
+	return sum.Float64 * 10.0, nil
+}
+
+// plausibilityProblems prüft die Tageswerte auf physikalisch unplausible Werte
+func plausibilityProblems(s dayStats) []string {
+	var problems []string
+	if math.IsNaN(s.tMax) || math.IsNaN(s.tMin) {
+		problems = append(problems, "Temperatur fehlt (NaN)")
+	} else {
+		if s.tMax > 60 || s.tMax < -60 || s.tMin > 60 || s.tMin < -60 {
+			problems = append(problems, fmt.Sprintf("Temperatur außerhalb -60…60 °C (%.1f/%.1f)", s.tMin, s.tMax))
+		}
+		if s.tMin > s.tMax {
+			problems = append(problems, fmt.Sprintf("Tiefstwert %.1f über Höchstwert %.1f", s.tMin, s.tMax))
+		}
+	}
+	if s.rainSum < 0 || s.rainSum > 500 {
+		problems = append(problems, fmt.Sprintf("Niederschlag außerhalb 0…500 mm (%.1f)", s.rainSum))
+	}
+	return problems
+}
+
+// qcDigestMaxDetails begrenzt die Einzelereignisse im QC-Bericht; die Summen zählen weiterhin alle
+const qcDigestMaxDetails = 10
+
+// buildQCDigest erstellt den Monatsbericht über Datenqualität und Veröffentlichungen
+func buildQCDigest(store *sql.DB, month time.Time) (string, error) {
+	prefix := month.Format("2006-01") + "-%"
+	const q = `SELECT day, kind, source, count, message FROM qc_events WHERE day LIKE ? ORDER BY day, kind, source;`
+	rows, err := store.Query(q, prefix)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]int)
+	var details []string
+	omitted := 0
+	for rows.Next() {
+		var day, kind, source, message string
+		var count int
+		if err := rows.Scan(&day, &kind, &source, &count, &message); err != nil {
+			return "", err
+		}
+		totals[kind] += count
+		if len(details) < qcDigestMaxDetails {
+			details = append(details, fmt.Sprintf("- %s %s (%s): %s", day, kind, source, message))
+		} else {
+			omitted++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "QC-Bericht %s %d\n", germanMonths[month.Month()-1], month.Year())
+	fmt.Fprintf(&b, "Datenlücken: %d\n", totals[qcGap])
+	fmt.Fprintf(&b, "QC-Zurückweisungen: %d\n", totals[qcReject])
+	fmt.Fprintf(&b, "Abweichungen NOAA/Selbstprüfung: %d\n", totals[qcMismatch])
+	fmt.Fprintf(&b, "Fehlgeschlagene Veröffentlichungen: %d", totals[qcPublishFailed])
+	if len(details) > 0 {
+		b.WriteString("\n\nDetails:\n")
+		b.WriteString(strings.Join(details, "\n"))
+		if omitted > 0 {
+			fmt.Fprintf(&b, "\n- … %d weitere", omitted)
+		}
+	}
+	return b.String(), nil
+}
+
+// adminChannelConfigured prüft, ob Direktnachrichten an einen Admin-Account verschickt werden können
+func adminChannelConfigured(config Config) bool {
+	return config.AdminMastodonAccount != "" && config.MastodonServer != "" && config.MastodonToken != ""
+}
+
+// sendAdminMessage schickt eine Nachricht als Direktnachricht an den konfigurierten Admin-Account,
+// gekürzt auf das Zeichenlimit von Mastodon
+func sendAdminMessage(config Config, text string) error {
+	if !adminChannelConfigured(config) {
+		return fmt.Errorf("kein Admin-Kanal konfiguriert (admin_mastodon_account)")
+	}
+	account := config.AdminMastodonAccount
+	if !strings.HasPrefix(account, "@") {
+		account = "@" + account
+	}
+	status := fitMastodonText(config, "", account+" "+text)
+	return mastodonCreatePost(config.MastodonServer, config.MastodonToken, status, "", "direct", mastodonLanguage(config), nil)
+}
+
+// sendMonthlyQCDigest verschickt den QC-Bericht des Vormonats, sofern er noch nicht verschickt wurde
+func sendMonthlyQCDigest(store *sql.DB, config Config, now time.Time, testMode bool) {
+	if store == nil {
+		return
+	}
+	month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -1, 0)
+	period := "qc-" + month.Format("2006-01")
+
+	var sent int64
+	err := store.QueryRow(`SELECT sent FROM admin_reports WHERE period = ?;`, period).Scan(&sent)
+	if err == nil {
+		return // bereits verschickt
+	}
+	if err != sql.ErrNoRows {
+		log.Printf("Warnung: Status des QC-Berichts unbekannt: %v", err)
+		return
+	}
+
+	digest, err := buildQCDigest(store, month)
+	if err != nil {
+		log.Printf("Fehler beim Erstellen des QC-Berichts: %v", err)
+		return
+	}
+	if testMode {
+		fmt.Printf("\n=== TEST-MODUS: QC-Bericht an Admin ===\n%s\n=== ENDE QC-BERICHT ===\n", digest)
+		return
+	}
+	if !adminChannelConfigured(config) {
+		return // ohne Admin-Kanal kein Bericht
+	}
+	if err := sendAdminMessage(config, digest); err != nil {
+		log.Printf("QC-Bericht konnte nicht verschickt werden: %v", err)
+		return
+	}
+	if _, err := store.Exec(`INSERT INTO admin_reports (period, sent) VALUES (?, ?);`, period, time.Now().Unix()); err != nil {
+		log.Printf("Warnung: Versand des QC-Berichts konnte nicht vermerkt werden: %v", err)
+	}
+	log.Printf("QC-Bericht für %s an Admin verschickt.", month.Format("01/2006"))
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// Zuordnung eines Beobachtungstags (rain_day_start_hour ≠ 0) zu einem Kalendertag (rain_day_credit)
+const (
+	rainDayCreditStart = "start" // Tag, an dem der Zeitraum beginnt (Klimabeobachter, DWD)
+	rainDayCreditEnd   = "end"   // Tag, an dem der Zeitraum endet; beim morgendlichen Lauf schon vollständig
+)
+
+// rainDayWindow liefert den Zeitraum des Niederschlagstags für den Kalendertag day.
+// Mit rain_day_start_hour = 0 ist das der Kalendertag selbst. Bei einem abweichenden Tagesbeginn
+// (z.B. 9 Uhr) wird dem Tag wie beim DWD der 24-Stunden-Zeitraum zugeordnet, der an diesem Tag zur
+// Beobachtungsstunde beginnt, mit rain_day_credit = "end" der Zeitraum, der an diesem Tag endet.
+func rainDayWindow(loc *time.Location, config Config, day time.Time) (int64, int64) {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	if config.RainDayStartHour == 0 {
+		return dayStart.Unix(), dayStart.AddDate(0, 0, 1).Unix()
+	}
+	start := time.Date(day.Year(), day.Month(), day.Day(), config.RainDayStartHour, 0, 0, 0, loc)
+	if config.RainDayCredit == rainDayCreditEnd {
+		start = start.AddDate(0, 0, -1)
+	}
+	return start.Unix(), start.AddDate(0, 0, 1).Unix()
+}
+
+// rainOnDay liefert die Niederschlagsmenge (mm) eines Niederschlagstags. ok ist false,
+// wenn für den Tag keine Daten vorliegen.
+func rainOnDay(db *sql.DB, loc *time.Location, config Config, day time.Time) (float64, bool) {
+	start, end := rainDayWindow(loc, config, day)
+	if config.RainDayStartHour == 0 && config.DailySource != dailySourceArchive {
+		var rainSum sql.NullFloat64
+		if err := db.QueryRow("SELECT sum FROM archive_day_rain WHERE dateTime = ?;", start).Scan(&rainSum); err != nil {
+			return 0, false
+		}
+		// Korrektur: Regenmengen in der DB in cm
+		return rainSum.Float64 * 10.0, true
+	}
+
+	var rainSum sql.NullFloat64
+	var count int
+	if err := db.QueryRow(`SELECT SUM(rain), COUNT(*) FROM archive WHERE dateTime >= ? AND dateTime < ?;`, start, end).Scan(&rainSum, &count); err != nil || count == 0 {
+		return 0, false
+	}
+	return rainSum.Float64 * 10.0, true
+}
+
+// dailyRainSums liefert den Niederschlag (mm) der Niederschlagstage [from, to] (inklusive) in Tagesreihenfolge; Tage
+// ohne Daten fehlen. Mit Tageszusammenfassungen von weewx und Kalendertagen genügt eine Abfrage über archive_day_rain.
+func dailyRainSums(db *sql.DB, config Config, from, to time.Time) ([]dailyValue, error) {
+	loc := from.Location()
+	var values []dailyValue
+	if config.RainDayStartHour == 0 && config.DailySource != dailySourceArchive {
+		const q = `SELECT dateTime, sum FROM archive_day_rain WHERE dateTime >= ? AND dateTime < ? AND sum IS NOT NULL ORDER BY dateTime;`
+		rows, err := db.Query(q, from.Unix(), to.AddDate(0, 0, 1).Unix())
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var ts int64
+			var sum float64
+			if err := rows.Scan(&ts, &sum); err != nil {
+				return nil, err
+			}
+			// Korrektur: Regenmengen in der DB in cm
+			values = append(values, dailyValue{time.Unix(ts, 0).In(loc), sum * 10.0})
+		}
+		return values, rows.Err()
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if r, ok := rainOnDay(db, loc, config, day); ok {
+			values = append(values, dailyValue{day, r})
+		}
+	}
+	return values, nil
+}
+
+// rainBetween summiert den Niederschlag (mm) der Niederschlagstage [from, to] (inklusive) und zählt die Tage mit Daten.
+// Mit Tageszusammenfassungen von weewx und Kalendertagen genügt eine Abfrage über archive_day_rain.
+func rainBetween(db *sql.DB, loc *time.Location, config Config, from, to time.Time) (float64, int, error) {
+	if config.RainDayStartHour == 0 && config.DailySource != dailySourceArchive {
+		var rainSum sql.NullFloat64
+		var days int
+		start, _ := rainDayWindow(loc, config, from)
+		_, end := rainDayWindow(loc, config, to)
+		if err := db.QueryRow("SELECT SUM(sum), COUNT(sum) FROM archive_day_rain WHERE dateTime >= ? AND dateTime < ?;", start, end).Scan(&rainSum, &days); err != nil {
+			return 0, 0, err
+		}
+		// Korrektur: Regenmengen in der DB in cm
+		return rainSum.Float64 * 10.0, days, nil
+	}
+	var rain float64
+	days := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if r, ok := rainOnDay(db, loc, config, day); ok {
+			rain += r
+			days++
+		}
+	}
+	return rain, days, nil
+}
+
+// formatYearToDateRain erzeugt die Zeile mit dem Niederschlag seit Jahresbeginn und dem Vergleich zum selben
+// Zeitraum des Vorjahres. Der Vergleich entfällt, wenn das Vorjahr zu weniger als 90 % in der Datenbank liegt.
+func formatYearToDateRain(db *sql.DB, loc *time.Location, config Config, day time.Time) string {
+	if !config.YearToDateRainEnabled {
+		return ""
+	}
+	return formatRainSince(db, loc, config, time.Date(day.Year(), 1, 1, 0, 0, 0, 0, loc), day, tr(config, "seit Jahresbeginn"))
+}
+
+// formatRainYear erzeugt die Zeile mit dem Niederschlag im hydrologischen Jahr (Abflussjahr) ab rain_year_start,
+// z.B. "Niederschlag im Abflussjahr (seit 01.11.): 812.4 mm (Vorjahr bis 15.10.: 745.0 mm, +9 %)".
+// Beginnt das Abflussjahr am 1. Januar, entspricht es dem Kalenderjahr und die Zeile entfällt.
+func formatRainYear(db *sql.DB, loc *time.Location, config Config, day time.Time) string {
+	if config.RainYearStart == "" || config.RainYearStart == "01-01" {
+		return ""
+	}
+	start, ok := seasonStart(day, config.RainYearStart, previousMonthDay(config.RainYearStart))
+	if !ok {
+		return ""
+	}
+	return formatRainSince(db, loc, config, start, day, tr(config, "im Abflussjahr (seit %s)", formatDayMonth(config, start)))
+}
+
+// formatRainSince erzeugt die Zeile mit dem Niederschlag von start bis einschließlich day und dem Vergleich zum
+// selben Zeitraum ein Jahr zuvor, sofern dieser zu mindestens 90 % in der Datenbank liegt
+func formatRainSince(db *sql.DB, loc *time.Location, config Config, start, day time.Time, label string) string {
+	rain, _, err := rainBetween(db, loc, config, start, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Niederschlag %s nicht verfügbar: %v\n", label, err)
+		return ""
+	}
+	unit := unitOf(config, quantityRain)
+	line := tr(config, "Niederschlag %s: %s", label, unit.format(rain, 1))
+
+	lastStart, lastDay := start.AddDate(-1, 0, 0), day.AddDate(-1, 0, 0)
+	lastRain, lastDays, err := rainBetween(db, loc, config, lastStart, lastDay)
+	if err != nil || lastDays*10 < (int(lastDay.Sub(lastStart).Hours()/24+0.5)+1)*9 {
+		return line
+	}
+	line += tr(config, " (Vorjahr bis %s: %s", formatDayMonth(config, lastDay), unit.format(lastRain, 1))
+	if lastRain > 0 {
+		line += fmt.Sprintf(", %+.0f %%", (rain-lastRain)/lastRain*100)
+	}
+	return line + ")"
+}
+
+// getRainRateMax liefert die höchste Regenrate (mm/h) des Zeitraums, NaN ohne Spalte rainRate
+func getRainRateMax(db *sql.DB, start, end int64) (float64, error) {
+	column := optionalColumn(db, "rainRate")
+	if column == "" {
+		return math.NaN(), nil
+	}
+	var rate sql.NullFloat64
+	if err := db.QueryRow(`SELECT MAX(`+column+`) FROM archive WHERE dateTime >= ? AND dateTime < ?;`, start, end).Scan(&rate); err != nil {
+		return math.NaN(), err
+	}
+	if !rate.Valid {
+		return math.NaN(), nil
+	}
+	// Gleiche Korrektur wie bei den Regenmengen: Werte in cm/h
+	return rate.Float64 * 10.0, nil
+}
+
+// formatRainIntensity beschreibt die regenreichste Stunde und die höchste Regenrate, leer an trockenen Tagen
+func formatRainIntensity(s dayStats, config Config) string {
+	if s.heaviestHour < 0 {
+		return ""
+	}
+	unit := unitOf(config, quantityRain)
+	line := tr(config, "Stärkster Regen zwischen %d und %d Uhr (%s)", s.heaviestHour, (s.heaviestHour+1)%24, unit.format(s.heaviestHourRain, 1))
+	if !math.IsNaN(s.rainRateMax) && s.rainRateMax > 0 {
+		line += tr(config, ", maximale Regenrate %s/h", unit.format(s.rainRateMax, 1))
+	}
+	return line
+}
+
+// formatHeavyRainDay erzeugt den Hinweis auf einen Starkregentag, leer unterhalb von heavy_rain_threshold
+func formatHeavyRainDay(s dayStats, config Config) string {
+	if config.HeavyRainThreshold <= 0 || math.IsNaN(s.rainSum) || s.rainSum < config.HeavyRainThreshold {
+		return ""
+	}
+	return tr(config, "🌧️ Das war ein Starkregentag (mindestens %s Niederschlag).", unitOf(config, quantityRain).format(config.HeavyRainThreshold, 0))
+}
+
+// getRainMinutes summiert die Archivintervalle mit Niederschlag zur Regendauer in Minuten
+func getRainMinutes(db *sql.DB, start, end int64) (float64, error) {
+	var minutes sql.NullFloat64
+	if err := db.QueryRow(`SELECT SUM(interval) FROM archive WHERE dateTime >= ? AND dateTime < ? AND rain > 0;`, start, end).Scan(&minutes); err != nil {
+		return math.NaN(), err
+	}
+	return minutes.Float64, nil
+}
+
+// formatRainDuration beschreibt, wie sich der Regen über den Tag verteilt hat: kurzer Schauer
+// (höchstens eine Stunde mit Regen) oder über mehrere Stunden verteilt. Leer an trockenen Tagen.
+func formatRainDuration(s dayStats, config Config) string {
+	if s.rainHours == 0 || math.IsNaN(s.rainMinutes) {
+		return ""
+	}
+	if s.rainHours == 1 {
+		return tr(config, "Kurzer Schauer (%s mit Niederschlag)", formatDuration(s.rainMinutes))
+	}
+	return tr(config, "Regen über %d Stunden verteilt (%s mit Niederschlag)", s.rainHours, formatDuration(s.rainMinutes))
+}
+
+// getHourlyRain summiert den Niederschlag (mm) für die Stunden 0–23 (Ortszeit) des Zeitraums
+func getHourlyRain(db *sql.DB, loc *time.Location, start, end int64) ([24]float64, error) {
+	var hourly [24]float64
+	rows, err := db.Query(`SELECT dateTime, rain FROM archive WHERE dateTime >= ? AND dateTime < ? AND rain IS NOT NULL;`, start, end)
+	if err != nil {
+		return hourly, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ts int64
+		var rain float64
+		if err := rows.Scan(&ts, &rain); err != nil {
+			return hourly, err
+		}
+		hourly[time.Unix(ts, 0).In(loc).Hour()] += rain * 10.0 // cm → mm
+	}
+	return hourly, rows.Err()
+}
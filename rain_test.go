@@ -0,0 +1,67 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestRainDayWindow(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2024, month, day, hour, 0, 0, 0, loc)
+	}
+	tests := []struct {
+		name       string
+		startHour  int
+		credit     string
+		day        time.Time
+		start, end time.Time
+	}{
+		{"Kalendertag", 0, rainDayCreditStart, at(10, 8, 0), at(10, 8, 0), at(10, 9, 0)},
+		{"Kalendertag unabhängig von credit", 0, rainDayCreditEnd, at(10, 8, 0), at(10, 8, 0), at(10, 9, 0)},
+		{"09–09 ab dem Tag", 9, rainDayCreditStart, at(10, 8, 0), at(10, 8, 9), at(10, 9, 9)},
+		{"09–09 bis zum Tag", 9, rainDayCreditEnd, at(10, 8, 0), at(10, 7, 9), at(10, 8, 9)},
+		// Zeitumstellung: der Beobachtungstag hat 25 Stunden
+		{"Ende der Sommerzeit", 9, rainDayCreditStart, at(10, 26, 0), at(10, 26, 9), at(10, 27, 9)},
+	}
+	for _, tt := range tests {
+		config := DefaultConfig()
+		config.RainDayStartHour, config.RainDayCredit = tt.startHour, tt.credit
+		start, end := rainDayWindow(loc, config, tt.day)
+		if start != tt.start.Unix() || end != tt.end.Unix() {
+			t.Errorf("%s: got %s – %s, want %s – %s", tt.name, time.Unix(start, 0).In(loc), time.Unix(end, 0).In(loc), tt.start, tt.end)
+		}
+	}
+}
+
+func TestRainOnDay(t *testing.T) {
+	loc := time.UTC
+	day := time.Date(2024, 10, 8, 0, 0, 0, 0, loc)
+	// Regenmengen in der DB in cm
+	db := testArchive(t, map[time.Time]map[string]float64{
+		day.Add(-20 * time.Hour): {"rain": 0.1}, // 07.10. 4 Uhr
+		day.Add(6 * time.Hour):   {"rain": 0.2}, // 08.10. 6 Uhr
+		day.Add(12 * time.Hour):  {"rain": 0.4}, // 08.10. 12 Uhr
+		day.Add(30 * time.Hour):  {"rain": 0.8}, // 09.10. 6 Uhr
+	})
+	tests := []struct {
+		startHour int
+		credit    string
+		want      float64
+	}{
+		{9, rainDayCreditStart, 12},
+		{9, rainDayCreditEnd, 2},
+	}
+	for _, tt := range tests {
+		config := DefaultConfig()
+		config.RainDayStartHour, config.RainDayCredit = tt.startHour, tt.credit
+		got, ok := rainOnDay(db, loc, config, day)
+		if !ok || math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("rain_day_credit %s: got %v (ok %v), want %v", tt.credit, got, ok, tt.want)
+		}
+	}
+}
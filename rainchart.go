@@ -0,0 +1,66 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"time"
+)
+
+// getDailyRain liefert den Niederschlag (mm) der Niederschlagstage [from, to] (inklusive), NaN für Tage ohne Daten
+func getDailyRain(db *sql.DB, loc *time.Location, config Config, from, to time.Time) []float64 {
+	var rain []float64
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		r, ok := rainOnDay(db, loc, config, day)
+		if !ok {
+			r = math.NaN()
+		}
+		rain = append(rain, r)
+	}
+	return rain
+}
+
+// renderRainChart zeichnet den täglichen Niederschlag ab from als Balkendiagramm (PNG) und markiert den
+// regenreichsten Tag
+func renderRainChart(config Config, theme chartTheme, from time.Time, rain []float64) (*chartDrawing, error) {
+	if len(rain) == 0 {
+		return nil, fmt.Errorf("keine Niederschlagswerte")
+	}
+	unit := unitOf(config, quantityRain)
+	wettest := -1
+	for i, r := range rain {
+		if !math.IsNaN(r) && (wettest < 0 || r > rain[wettest]) {
+			wettest = i
+		}
+	}
+	if wettest < 0 {
+		return nil, fmt.Errorf("keine Niederschlagswerte")
+	}
+	hi := unit.convert(rain[wettest])
+	if hi <= 0 {
+		hi = unit.convert(1)
+	}
+	_, yMax, step := niceRange(0, hi, 5)
+
+	c := newChartCanvas(theme)
+	c.setRange(0, float64(len(rain)), 0, yMax)
+	to := from.AddDate(0, 0, len(rain)-1)
+	c.title(tr(config, "Niederschlag vom %s bis %s", formatDate(config, from), formatDate(config, to)), unit.symbol)
+	c.yAxis(step, axisLabel(step))
+	// höchstens acht Beschriftungen, damit sich die Datumsangaben nicht überlappen
+	every := (len(rain) + 7) / 8
+	var ticks []chartTick
+	for i := 0; i < len(rain); i += every {
+		ticks = append(ticks, chartTick{x: float64(i) + 0.5, label: formatDayMonth(config, from.AddDate(0, 0, i))})
+	}
+	c.xAxis(ticks)
+	for i, r := range rain {
+		if !math.IsNaN(r) && r > 0 {
+			c.bar(float64(i), float64(i+1), unit.convert(r), theme.bar)
+		}
+	}
+	if rain[wettest] > 0 {
+		c.marker(float64(wettest)+0.5, unit.convert(rain[wettest]), unit.number(rain[wettest], 1), true, theme.high)
+	}
+	return c.d, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"database/sql"
+	"time"
+)
+
+// recordMetric beschreibt eine Messgröße aus daily_stats, für die Stationsrekorde geführt werden
+type recordMetric struct {
+	column   string
+	label    string // z.B. "Hitzerekord"
+	quantity string // Messgröße für die Anzeigeeinheit
+	decimals int    // Nachkommastellen (metrisch)
+	higher   bool   // true: größter Wert ist Rekord, false: kleinster
+}
+
+// recordMetrics sind die Messgrößen mit Stationsrekorden
+var recordMetrics = []recordMetric{
+	{"t_max", "Hitzerekord", quantityTemperature, 1, true},
+	{"t_min", "Kälterekord", quantityTemperature, 1, false},
+	{"rain_sum", "Niederschlagsrekord", quantityRain, 1, true},
+	{"sun_hours", "Sonnenscheinrekord", quantitySunshine, 0, true},
+	{"gust_max", "Böenrekord", quantityWind, 0, true},
+}
+
+// formatValue formatiert einen Wert der Messgröße mit Einheit
+func (m recordMetric) formatValue(config Config, v float64) string {
+	return unitOf(config, m.quantity).format(v, m.decimals)
+}
+
+// updateRecords berechnet die Tabelle records aus daily_stats neu: Jeder Tag, der den bisherigen
+// Höchst- bzw. Tiefstwert einer Messgröße übertroffen hat, wird als Rekord eingetragen. Rekorde aus
+// den ersten minHistoryDays Tagen der Aufzeichnung zählen nicht, weil anfangs fast jeder Tag einer wäre.
+func updateRecords(store *sql.DB, minHistoryDays int) error {
+	tx, err := store.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM records;`); err != nil {
+		return err
+	}
+	for _, m := range recordMetrics {
+		rows, err := tx.Query(`SELECT day, ` + m.column + ` FROM daily_stats WHERE ` + m.column + ` IS NOT NULL ORDER BY day;`)
+		if err != nil {
+			return err
+		}
+		type record struct {
+			day             string
+			value, previous float64
+		}
+		var found []record
+		var best float64
+		var first time.Time
+		for rows.Next() {
+			var day string
+			var v float64
+			if err := rows.Scan(&day, &v); err != nil {
+				rows.Close()
+				return err
+			}
+			d, err := time.Parse("2006-01-02", day)
+			if err != nil {
+				continue
+			}
+			if first.IsZero() {
+				first, best = d, v
+				continue
+			}
+			if (m.higher && v > best) || (!m.higher && v < best) {
+				if d.Sub(first) >= time.Duration(minHistoryDays)*24*time.Hour {
+					found = append(found, record{day, v, best})
+				}
+				best = v
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		for _, r := range found {
+			if _, err := tx.Exec(`INSERT INTO records (metric, day, value, previous) VALUES (?, ?, ?, ?);`, m.column, r.day, r.value, r.previous); err != nil {
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+// formatAnniversaries erzeugt Hinweise auf Stationsrekorde, die an diesem Kalendertag in früheren
+// Jahren aufgestellt wurden, z.B. "Heute vor 2 Jahren: 38.4 °C Hitzerekord"
+func formatAnniversaries(store *sql.DB, config Config, today time.Time) string {
+	if store == nil {
+		return ""
+	}
+	rows, err := store.Query(`SELECT metric, day, value FROM records WHERE substr(day, 6) = ? AND day < ? ORDER BY day DESC;`,
+		today.Format("01-02"), today.Format("2006-01-02"))
+	if err != nil {
+		return ""
+	}
+	defer rows.Close()
+
+	metrics := make(map[string]recordMetric)
+	for _, m := range recordMetrics {
+		metrics[m.column] = m
+	}
+	text := ""
+	for rows.Next() {
+		var metric, day string
+		var value float64
+		if err := rows.Scan(&metric, &day, &value); err != nil {
+			return text
+		}
+		m, ok := metrics[metric]
+		if !ok {
+			continue
+		}
+		years := today.Year() - mustParseDay(day).Year()
+		ago := tr(config, "vor einem Jahr")
+		if years > 1 {
+			ago = tr(config, "vor %d Jahren", years)
+		}
+		if text != "" {
+			text += "\n"
+		}
+		text += tr(config, "📅 Heute %s: %s %s", ago, m.formatValue(config, value), tr(config, m.label))
+	}
+	return text
+}
+
+// mustParseDay wandelt einen Tag im Format 2006-01-02 um; ungültige Werte ergeben den Nullzeitpunkt
+func mustParseDay(day string) time.Time {
+	d, _ := time.Parse("2006-01-02", day)
+	return d
+}
@@ -0,0 +1,56 @@
+package main
+
+import "time"
+
+// Konsolenausgabe des Tageslaufs (output_format, -format)
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// dailyStreaks sind die Serien bis zum Berichtstag; deaktivierte Serien bleiben 0
+type dailyStreaks struct {
+	DaysSinceRain       int `json:"days_since_rain"`
+	ConsecutiveRainDays int `json:"consecutive_rain_days"`
+	FrostNights         int `json:"frost_nights"`
+	HeatDays            int `json:"heat_days"`
+}
+
+// dailyReport ist die JSON-Ausgabe des Tageslaufs mit allen berechneten Werten, z.B. für eigene Dashboards
+type dailyReport struct {
+	Date     string                 `json:"date"` // YYYY-MM-DD
+	Title    string                 `json:"title"`
+	Text     string                 `json:"text"`
+	Stats    map[string]interface{} `json:"stats"`    // Spaltennamen wie in daily_stats, fehlende Werte null
+	Previous map[string]interface{} `json:"previous"` // dieselben Werte für den Vortag
+	Streaks  dailyStreaks           `json:"streaks"`
+	Sections map[string]string      `json:"sections"` // Abschnitte des Standardtexts (Rekorde, Vergleiche, …) nach Name
+	Alerts   []string               `json:"alerts"`
+	NOAARain *float64               `json:"noaa_rain,omitempty"` // Tagesregenmenge laut NOAA-Report (-noaa)
+}
+
+// newDailyReport erzeugt die JSON-Ausgabe für den Tag day aus dem fertigen Post und den Tageswerten
+func newDailyReport(day time.Time, post dailyPost, statsY, statsV dayStats) dailyReport {
+	sections := make(map[string]string)
+	for _, s := range post.sections {
+		if s.block != nil {
+			sections[s.name] = markup{}.block(*s.block)
+			continue
+		}
+		sections[s.name] = s.text
+	}
+	alerts := post.alerts
+	if alerts == nil {
+		alerts = []string{}
+	}
+	return dailyReport{
+		Date:     day.Format("2006-01-02"),
+		Title:    post.title,
+		Text:     post.text,
+		Stats:    dayStatsMap(statsY, true),
+		Previous: dayStatsMap(statsV, true),
+		Streaks:  post.streaks,
+		Sections: sections,
+		Alerts:   alerts,
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// periodSummary sind die Kennzahlen eines Zeitraums (Jahr, Jahreszeit) für Rückblicke
+type periodSummary struct {
+	from, to        time.Time // inklusive
+	days            int       // Tage mit Temperaturdaten
+	tMax, tMin      float64
+	tMaxDay         time.Time
+	tMinDay         time.Time
+	tMean           float64 // Mittel der Tagesmittel (Höchst- und Tiefstwert)
+	rainSum         float64 // mm
+	rainDays        int
+	sunMinutes      float64    // NaN ohne Strahlungswerte
+	sunniestMonth   time.Month // 0 ohne Strahlungswerte
+	sunniestMinutes float64
+	dryStart        time.Time // Beginn der längsten Trockenperiode
+	dryDays         int
+	climateDays     map[string]int
+}
+
+// getPeriodSummary berechnet die Kennzahlen für die Tage [from, to] (inklusive) aus den Tageswerten
+func getPeriodSummary(db *sql.DB, loc *time.Location, config Config, from, to time.Time) (periodSummary, error) {
+	p := periodSummary{from: from, to: to, tMax: math.NaN(), tMin: math.NaN(), tMean: math.NaN()}
+
+	// Extremwerte und Mitteltemperatur
+	temps, err := dailyTemperatures(db, config, from, to)
+	if err != nil {
+		return p, err
+	}
+	if len(temps) == 0 {
+		return p, fmt.Errorf("keine Temperaturdaten vom %s bis %s", from.Format("02.01.2006"), to.Format("02.01.2006"))
+	}
+	sum := 0.0
+	for _, t := range temps {
+		if math.IsNaN(p.tMax) || t.tMax > p.tMax {
+			p.tMax, p.tMaxDay = t.tMax, t.day
+		}
+		if math.IsNaN(p.tMin) || t.tMin < p.tMin {
+			p.tMin, p.tMinDay = t.tMin, t.day
+		}
+		sum += (t.tMax + t.tMin) / 2
+	}
+	p.days = len(temps)
+	p.tMean = sum / float64(len(temps))
+
+	// Niederschlag und längste Trockenperiode (Tage ohne Daten unterbrechen die Serie)
+	streak := 0
+	var streakStart time.Time
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		rain, ok := rainOnDay(db, loc, config, day)
+		if !ok || rain > 0 {
+			streak = 0
+			if ok {
+				p.rainSum += rain
+				p.rainDays++
+			}
+			continue
+		}
+		if streak == 0 {
+			streakStart = day
+		}
+		streak++
+		if streak > p.dryDays {
+			p.dryDays, p.dryStart = streak, streakStart
+		}
+	}
+
+	// Sonnenscheindauer und sonnigster Monat (Monate werden auf den Zeitraum begrenzt)
+	end := to.AddDate(0, 0, 1)
+	p.sunMinutes, err = getSunshineMinutes(db, config, from.Unix(), end.Unix())
+	if err != nil {
+		return p, err
+	}
+	for start := from; start.Before(end); {
+		next := time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, loc)
+		if next.After(end) {
+			next = end
+		}
+		minutes, err := getSunshineMinutes(db, config, start.Unix(), next.Unix())
+		if err != nil {
+			return p, err
+		}
+		if !math.IsNaN(minutes) && minutes > p.sunniestMinutes {
+			p.sunniestMonth, p.sunniestMinutes = start.Month(), minutes
+		}
+		start = next
+	}
+
+	p.climateDays, err = countClimateDays(db, config, from, to)
+	return p, err
+}
+
+// getYearSummary berechnet die Kennzahlen des Jahres year
+func getYearSummary(db *sql.DB, loc *time.Location, config Config, year int) (periodSummary, error) {
+	return getPeriodSummary(db, loc, config, time.Date(year, 1, 1, 0, 0, 0, 0, loc), time.Date(year, 12, 31, 0, 0, 0, 0, loc))
+}
+
+// formatClimateDayCounts listet die Zahl der Kenntage, z.B. "1 Eistag, 12 Frosttage, …"
+func formatClimateDayCounts(config Config, counts map[string]int) string {
+	var parts []string
+	for _, t := range climateDayTypes {
+		name := t.plural
+		if counts[t.name] == 1 {
+			name = t.name
+		}
+		parts = append(parts, fmt.Sprintf("%d %s", counts[t.name], tr(config, name)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatYearReview erzeugt Titel und Text des Jahresrückblicks
+func formatYearReview(config Config, y periodSummary) (string, string) {
+	title := tr(config, "📅 Jahresrückblick %d für %s", y.from.Year(), config.StationName)
+
+	temp, rain := unitOf(config, quantityTemperature), unitOf(config, quantityRain)
+	lines := []string{
+		tr(config, "Höchstwert: %s am %s", temp.format(y.tMax, 1), formatDayMonth(config, y.tMaxDay)),
+		tr(config, "Tiefstwert: %s am %s", temp.format(y.tMin, 1), formatDayMonth(config, y.tMinDay)),
+		tr(config, "Niederschlag: %s an %d Tagen", rain.format(y.rainSum, 1), y.rainDays),
+	}
+	if y.sunniestMonth != 0 {
+		lines = append(lines, tr(config, "Sonnigster Monat: %s mit %.0f Stunden Sonnenschein", monthName(config, y.sunniestMonth), y.sunniestMinutes/60))
+	}
+	if y.dryDays > 0 {
+		dryEnd := y.dryStart.AddDate(0, 0, y.dryDays-1)
+		lines = append(lines, tr(config, "Längste Trockenperiode: %d Tage (%s–%s)", y.dryDays, formatDayMonth(config, y.dryStart), formatDayMonth(config, dryEnd)))
+	}
+	lines = append(lines, tr(config, "Kenntage: ")+formatClimateDayCounts(config, y.climateDays))
+	if details := detailLine(config, detailYearly); details != "" {
+		lines = append(lines, details)
+	}
+	return title, strings.Join(lines, "\n")
+}
+
+// publishYearReview veröffentlicht am 1. Januar den Rückblick auf das Vorjahr.
+// Der Status wird unter dem Jahr (z.B. "2025") vermerkt, damit der Rückblick nur einmal erscheint.
+func publishYearReview(db, store *sql.DB, loc *time.Location, config Config, now time.Time, testMode, loopMode bool) {
+	if !config.YearReviewEnabled || now.Month() != time.January || now.Day() != 1 {
+		return
+	}
+	year := now.Year() - 1
+	y, err := getYearSummary(db, loc, config, year)
+	if err != nil {
+		log.Printf("Jahresrückblick %d nicht möglich: %v", year, err)
+		return
+	}
+	title, text := formatYearReview(config, y)
+	media := yearMedia(db, loc, config, year)
+	if !testMode {
+		defer removeMedia(media)
+	}
+	publishSummary(store, config, strconv.Itoa(year), postTypeYearReview, "Jahresrückblick", title, text, "", media, testMode, loopMode)
+}
+
+// publishSummary veröffentlicht einen Rückblick (Jahr, Jahreszeit) auf Lemmy und Mastodon. key ist der Schlüssel
+// für den Veröffentlichungsstatus, postType die Post-Art für Inhaltswarnung und Sichtbarkeit, kind die Bezeichnung für
+// Test-Ausgabe und Log. markdown ist die Fassung für Plattformen mit Markdown (text_formats), leer = text.
+// media sind angehängte Bilder. Im Test-Modus wird er nur angezeigt.
+func publishSummary(store *sql.DB, config Config, key, postType, kind, title, text, markdown string, media []mediaAttachment, testMode, loopMode bool) {
+	textFor := func(platform string) string {
+		if markdown != "" && markupFor(config, platform).markdown {
+			return markdown
+		}
+		return text
+	}
+	cw := contentWarning(config, postType, summaryData{Title: title, Text: text})
+	if testMode {
+		printMedia(media)
+		if cw != "" {
+			fmt.Printf("\n=== TEST-MODUS: %s (Inhaltswarnung: %s) ===\n%s\n%s\n=== ENDE %s ===\n", kind, cw, title, text, strings.ToUpper(kind))
+			return
+		}
+		fmt.Printf("\n=== TEST-MODUS: %s ===\n%s\n%s\n=== ENDE %s ===\n", kind, title, text, strings.ToUpper(kind))
+		return
+	}
+	if config.LemmyPassword != "CHANGEME" && isPublished(store, key, platformLemmy) {
+		log.Printf("%s auf Lemmy übersprungen (bereits veröffentlicht)", kind)
+	} else if config.LemmyPassword != "CHANGEME" {
+		lemmyText := fitText(config, platformLemmy, textFor(platformLemmy))
+		err := lemmyPostWithRetry(store, config, title, lemmyText, media, retryPolicyFor(loopMode))
+		setPublishStatus(store, key, platformLemmy, err)
+		if err != nil {
+			saveDeadLetter(config, deadLetter{Day: key, Platform: platformLemmy, Title: title, Text: lemmyText}, media, err)
+		}
+	}
+	if config.MastodonServer != "" && config.MastodonToken != "" && isPublished(store, key, platformMastodon) {
+		log.Printf("%s auf Mastodon übersprungen (bereits veröffentlicht)", kind)
+	} else if config.MastodonServer != "" && config.MastodonToken != "" {
+		mastodonText := fitMastodonText(config, cw, title+"\n"+textFor(platformMastodon))
+		err := spendAPICall(store, config, platformMastodon)
+		if err == nil {
+			mediaIDs := mastodonUploadAll(store, config, media)
+			err = mastodonCreatePost(config.MastodonServer, config.MastodonToken, mastodonText, cw, mastodonVisibility(config, postType), mastodonLanguage(config), mediaIDs)
+		}
+		setPublishStatus(store, key, platformMastodon, err)
+		if err != nil {
+			log.Printf("Fehler beim %s auf Mastodon: %v", kind, err)
+			saveDeadLetter(config, deadLetter{Day: key, Platform: platformMastodon, Text: mastodonText, ContentWarning: cw,
+				Visibility: mastodonVisibility(config, postType)}, media, err)
+		}
+	}
+}
+
+// printYearReview zeigt den Jahresrückblick für ein beliebiges Jahr an (-year-review)
+func printYearReview(dbPath string, config Config, year int) error {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("timezone: %v", err)
+	}
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return fmt.Errorf("open DB: %v", err)
+	}
+	defer db.Close()
+
+	y, err := getYearSummary(db, loc, config, year)
+	if err != nil {
+		return err
+	}
+	title, text := formatYearReview(config, y)
+	fmt.Printf("%s\n%s\n", title, text)
+	return nil
+}
+
+// formatRangeSummary erzeugt Titel und Text der Zusammenfassung eines beliebigen Zeitraums (-from/-to)
+func formatRangeSummary(config Config, p periodSummary) (string, string) {
+	title := tr(config, "📅 Wetter in %s vom %s bis %s", config.StationName, formatDate(config, p.from), formatDate(config, p.to))
+
+	temp, rain := unitOf(config, quantityTemperature), unitOf(config, quantityRain)
+	lines := []string{
+		tr(config, "Mitteltemperatur: %s", temp.format(p.tMean, 1)),
+		tr(config, "Höchstwert: %s am %s, Tiefstwert: %s am %s", temp.format(p.tMax, 1), formatDate(config, p.tMaxDay), temp.format(p.tMin, 1), formatDate(config, p.tMinDay)),
+		tr(config, "Niederschlag: %s an %d Tagen", rain.format(p.rainSum, 1), p.rainDays),
+	}
+	if !math.IsNaN(p.sunMinutes) {
+		lines = append(lines, tr(config, "Sonnenscheindauer: %s", formatDuration(p.sunMinutes)))
+	}
+	if p.dryDays > 1 {
+		dryEnd := p.dryStart.AddDate(0, 0, p.dryDays-1)
+		lines = append(lines, tr(config, "Längste Trockenperiode: %d Tage (%s–%s)", p.dryDays, formatDayMonth(config, p.dryStart), formatDayMonth(config, dryEnd)))
+	}
+	lines = append(lines, tr(config, "Kenntage: ")+formatClimateDayCounts(config, p.climateDays))
+	if missing := int(p.to.Sub(p.from).Hours()/24+0.5) + 1 - p.days; missing > 0 {
+		lines = append(lines, tr(config, "Für %d Tage fehlen Daten.", missing))
+	}
+	if details := detailLine(config, rangeDetailKind(p.from, p.to)); details != "" {
+		lines = append(lines, details)
+	}
+	return title, strings.Join(lines, "\n")
+}
+
+// runRangeReport zeigt die Zusammenfassung für den Zeitraum -from/-to an und veröffentlicht sie mit post
+// auf Lemmy und Mastodon (im Test-Modus nur Anzeige). Ohne Angaben gilt der Zeitraum vom Jahresbeginn bis gestern.
+func runRangeReport(dbPath string, config Config, from, to string, post, testMode bool) error {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("timezone: %v", err)
+	}
+	start, end, err := parseDateRange(loc, from, to)
+	if err != nil {
+		return err
+	}
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return fmt.Errorf("open DB: %v", err)
+	}
+	defer db.Close()
+
+	p, err := getPeriodSummary(db, loc, config, start, end)
+	if err != nil {
+		return err
+	}
+	title, text := formatRangeSummary(config, p)
+	if !post {
+		fmt.Printf("%s\n%s\n", title, text)
+		return nil
+	}
+
+	store, err := openStore(config.StorePath)
+	if err != nil {
+		log.Printf("Warnung: %v – Veröffentlichungsstatus wird nicht gespeichert", err)
+	} else {
+		defer store.Close()
+	}
+	key := "range-" + start.Format("2006-01-02") + "-" + end.Format("2006-01-02")
+	media := rangeMedia(db, loc, config, start, end)
+	if !testMode {
+		defer removeMedia(media)
+	}
+	publishSummary(store, config, key, postTypeRange, "Zeitraumrückblick", title, text, "", media, testMode, false)
+	return nil
+}
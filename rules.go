@@ -0,0 +1,160 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// AdvisoryCondition vergleicht eine Messgröße mit einer Schwelle
+type AdvisoryCondition struct {
+	Metric string  `json:"metric"` // Name wie die Spalte in daily_stats (z.B. "uv_max") oder ein Morgenwert (z.B. "night_min_now")
+	Op     string  `json:"op"`     // ">=", ">", "<=" oder "<"
+	Value  float64 `json:"value"`
+}
+
+// AdvisoryRule ist eine Hinweisregel: Sind alle Bedingungen erfüllt, erscheint der Text im Post.
+// Von mehreren zutreffenden Regeln derselben Gruppe gilt nur die letzte, so dass gestufte
+// Schwellen (z.B. hoher/sehr hoher UV-Index) nicht doppelt erscheinen.
+type AdvisoryRule struct {
+	AdvisoryCondition
+	And      []AdvisoryCondition `json:"and,omitempty"`    // weitere Bedingungen, die zusätzlich erfüllt sein müssen
+	Season   []DateRange         `json:"season,omitempty"` // nur in diesen Zeiträumen (leer = ganzjährig)
+	Group    string              `json:"group,omitempty"`
+	Separate bool                `json:"separate,omitempty"` // als eigener Warn-Post statt im Tagespost
+	Text     string              `json:"text"`               // {value} wird durch den Wert der ersten Bedingung ersetzt
+}
+
+// advisory ist ein ausgelöster Hinweis
+type advisory struct {
+	text     string
+	separate bool
+}
+
+// defaultAdvisoryRules sind die voreingestellten Hinweise: UV-Index nach WHO-Skala. Regeln zu Bodenfrost und Glätte
+// sind nicht voreingestellt, weil night_min_now ein gemessener Wert und keine Vorhersage für die kommende Nacht ist.
+func defaultAdvisoryRules() []AdvisoryRule {
+	return []AdvisoryRule{
+		{AdvisoryCondition: AdvisoryCondition{Metric: "uv_max", Op: ">=", Value: 6}, Group: "uv", Text: "😎 Hoher UV-Index ({value}) – Mittagssonne meiden"},
+		{AdvisoryCondition: AdvisoryCondition{Metric: "uv_max", Op: ">=", Value: 8}, Group: "uv", Text: "😎 Sehr hoher UV-Index ({value}) – Mittagssonne meiden, Sonnenschutz nötig"},
+		{AdvisoryCondition: AdvisoryCondition{Metric: "uv_max", Op: ">=", Value: 11}, Group: "uv", Text: "😎 Extremer UV-Index ({value}) – Aufenthalt im Freien möglichst vermeiden"},
+	}
+}
+
+// morningValues sind Werte zum Zeitpunkt des Laufs, die Regeln zusätzlich zu den Tageswerten nutzen können
+type morningValues map[string]float64
+
+// getMorningValues ermittelt den Tiefstwert der laufenden Nacht (ab Beginn des Nachtfensters am Vortag)
+// und den Niederschlag der letzten 24 Stunden bis now
+func getMorningValues(db *sql.DB, config Config, yesterday, now time.Time) morningValues {
+	values := morningValues{"night_min_now": math.NaN(), "rain_24h": math.NaN()}
+	nightStart := time.Date(yesterday.Year(), yesterday.Month(), yesterday.Day(), config.TropicalNightFrom, 0, 0, 0, yesterday.Location())
+	if tMin, _, err := columnExtremes(db, "outTemp", nightStart.Unix(), now.Unix()); err == nil {
+		values["night_min_now"] = tMin
+	}
+	if rain, err := archiveRainSum(db, now.Add(-24*time.Hour).Unix(), now.Unix()); err == nil {
+		values["rain_24h"] = rain
+	}
+	return values
+}
+
+// statValue liefert den Wert einer Messgröße über ihren Spaltennamen; ok ist false bei fehlenden Werten
+func statValue(s dayStats, metric string) (float64, bool) {
+	for _, c := range dailyStatsColumns {
+		if c.name != metric {
+			continue
+		}
+		switch v := c.value(s).(type) {
+		case float64:
+			return v, !math.IsNaN(v)
+		case int:
+			return float64(v), true
+		case int64:
+			return float64(v), true
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// advisoryOps sind die zulässigen Vergleichsoperatoren der Hinweisregeln
+var advisoryOps = map[string]func(v, limit float64) bool{
+	">=": func(v, limit float64) bool { return v >= limit },
+	">":  func(v, limit float64) bool { return v > limit },
+	"<=": func(v, limit float64) bool { return v <= limit },
+	"<":  func(v, limit float64) bool { return v < limit },
+}
+
+// check prüft eine Bedingung und liefert den verglichenen Wert
+func (c AdvisoryCondition) check(s dayStats, morning morningValues) (float64, bool) {
+	op, valid := advisoryOps[c.Op]
+	if !valid {
+		log.Printf("Warnung: Hinweisregel für %s hat ungültigen Operator %q", c.Metric, c.Op)
+		return 0, false
+	}
+	v, ok := morning[c.Metric]
+	if ok {
+		ok = !math.IsNaN(v)
+	} else {
+		v, ok = statValue(s, c.Metric)
+	}
+	return v, ok && op(v, c.Value)
+}
+
+// evaluateAdvisories wertet die Hinweisregeln der Konfiguration für einen Tag aus und liefert die Hinweise
+// in Regelreihenfolge und in der Sprache der Posts
+func evaluateAdvisories(config Config, s dayStats, morning morningValues, day time.Time) []advisory {
+	var result []advisory
+	groupIndex := make(map[string]int)
+	for _, r := range config.AdvisoryRules {
+		if len(r.Season) > 0 && !inSeasons(r.Season, day) {
+			continue
+		}
+		v, ok := r.check(s, morning)
+		for _, c := range r.And {
+			if !ok {
+				break
+			}
+			_, ok = c.check(s, morning)
+		}
+		if !ok {
+			continue
+		}
+		a := advisory{text: strings.ReplaceAll(tr(config, r.Text), "{value}", fmt.Sprintf("%.1f", v)), separate: r.Separate}
+		if i, seen := groupIndex[r.Group]; seen && r.Group != "" {
+			result[i] = a
+			continue
+		}
+		groupIndex[r.Group] = len(result)
+		result = append(result, a)
+	}
+	return result
+}
+
+// publishAlerts veröffentlicht Warn-Posts (Hinweisregeln mit "separate") einmal pro Tag auf Mastodon
+func publishAlerts(store *sql.DB, config Config, day string, alerts []string) {
+	if len(alerts) == 0 {
+		return
+	}
+	if isPublished(store, day, platformMastodonAlert) {
+		log.Printf("Warn-Post übersprungen (für %s bereits veröffentlicht)", day)
+		return
+	}
+	text := strings.Join(alerts, "\n")
+	cw := contentWarning(config, postTypeAlert, summaryData{Text: text})
+	text = fitMastodonText(config, cw, text)
+	err := spendAPICall(store, config, platformMastodon)
+	if err == nil {
+		err = mastodonCreatePost(config.MastodonServer, config.MastodonToken, text, cw, mastodonVisibility(config, postTypeAlert), mastodonLanguage(config), nil)
+	}
+	setPublishStatus(store, day, platformMastodonAlert, err)
+	if err != nil {
+		log.Printf("Fehler beim Warn-Post: %v", err)
+		recordQCEvent(store, day, qcPublishFailed, platformMastodonAlert, 1, err.Error())
+		saveDeadLetter(config, deadLetter{Day: day, Platform: platformMastodonAlert, Text: text, ContentWarning: cw,
+			Visibility: mastodonVisibility(config, postTypeAlert)}, nil, err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	seasonsMeteorological = "meteorological" // Frühling ab 1. März, Sommer ab 1. Juni, …
+	seasonsAstronomical   = "astronomical"   // ab Tagundnachtgleiche bzw. Sonnenwende (berechnet, siehe cardinalPoint)
+)
+
+// seasonNames sind die Jahreszeiten in der Reihenfolge von season_starts mit Emoji für den Titel
+var seasonNames = []struct{ name, emoji string }{
+	{"Frühling", "🌷"},
+	{"Sommer", "☀️"},
+	{"Herbst", "🍂"},
+	{"Winter", "❄️"},
+}
+
+// seasonStartDates liefert die Anfänge (MM-DD) von Frühling, Sommer, Herbst und Winter im Jahr von now.
+// season_starts hat Vorrang vor season_type.
+func seasonStartDates(config Config, now time.Time) ([]string, error) {
+	starts := config.SeasonStarts
+	if len(starts) == 0 {
+		switch config.SeasonType {
+		case seasonsMeteorological, "":
+			starts = []string{"03-01", "06-01", "09-01", "12-01"}
+		case seasonsAstronomical:
+			starts = astronomicalSeasonStarts(now.Year(), now.Location())
+		default:
+			return nil, fmt.Errorf("unbekannter season_type %q", config.SeasonType)
+		}
+	}
+	if len(starts) != len(seasonNames) {
+		return nil, fmt.Errorf("season_starts braucht %d Einträge (Frühling, Sommer, Herbst, Winter)", len(seasonNames))
+	}
+	for _, s := range starts {
+		if _, _, err := parseMonthDay(s); err != nil {
+			return nil, err
+		}
+	}
+	return starts, nil
+}
+
+// endedSeason prüft, ob mit dem Vortag von now eine Jahreszeit zu Ende gegangen ist, und liefert deren Index
+// in seasonNames sowie den ersten und letzten Tag
+func endedSeason(starts []string, now time.Time) (int, time.Time, time.Time, bool) {
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	for i, s := range starts {
+		month, day, _ := parseMonthDay(s)
+		if today.Month() != month || today.Day() != day {
+			continue
+		}
+		prev := (i + len(starts) - 1) % len(starts)
+		month, day, _ = parseMonthDay(starts[prev])
+		from := time.Date(today.Year(), month, day, 0, 0, 0, 0, loc)
+		if !from.Before(today) {
+			from = from.AddDate(-1, 0, 0)
+		}
+		return prev, from, today.AddDate(0, 0, -1), true
+	}
+	return 0, time.Time{}, time.Time{}, false
+}
+
+// seasonLabel liefert die Bezeichnung einer Jahreszeit, z.B. "Herbst 2025" oder "Winter 2025/26"
+func seasonLabel(config Config, index int, from, to time.Time) string {
+	name := tr(config, seasonNames[index].name)
+	if from.Year() != to.Year() {
+		return fmt.Sprintf("%s %d/%02d", name, from.Year(), to.Year()%100)
+	}
+	return fmt.Sprintf("%s %d", name, from.Year())
+}
+
+// previousSeasons berechnet dieselbe Jahreszeit der Vorjahre, soweit sie zu mindestens 90 % in der
+// Datenbank liegt. Die Suche endet beim ersten Jahr ohne Daten.
+func previousSeasons(db *sql.DB, loc *time.Location, config Config, from, to time.Time) []periodSummary {
+	var seasons []periodSummary
+	length := int(to.Sub(from).Hours()/24+0.5) + 1
+	for y := 1; ; y++ {
+		p, err := getPeriodSummary(db, loc, config, from.AddDate(-y, 0, 0), to.AddDate(-y, 0, 0))
+		if err != nil {
+			break
+		}
+		if p.days*10 >= length*9 {
+			seasons = append(seasons, p)
+		}
+	}
+	return seasons
+}
+
+// seasonComparison vergleicht einen Wert mit denselben Jahreszeiten der Vorjahre, z.B.
+// " (0.8 °C über dem Mittel der Vorjahre, Rang 2 von 4)"; Rang 1 ist der höchste Wert. Ohne Vorjahre leer.
+// formatDiff formatiert den Abstand zum Mittel mit Einheit.
+func seasonComparison(config Config, value float64, previous []float64, formatDiff func(float64) string) string {
+	var values []float64
+	for _, v := range previous {
+		if !math.IsNaN(v) {
+			values = append(values, v)
+		}
+	}
+	if len(values) == 0 || math.IsNaN(value) {
+		return ""
+	}
+	sum, rank := 0.0, 1
+	for _, v := range values {
+		sum += v
+		if v > value {
+			rank++
+		}
+	}
+	diff := value - sum/float64(len(values))
+	if diff < 0 {
+		return tr(config, " (%s unter dem Mittel der Vorjahre, Rang %d von %d)", formatDiff(-diff), rank, len(values)+1)
+	}
+	return tr(config, " (%s über dem Mittel der Vorjahre, Rang %d von %d)", formatDiff(diff), rank, len(values)+1)
+}
+
+// formatSeasonSummary erzeugt Titel und Text des Rückblicks auf eine Jahreszeit mit Vergleich zu den Vorjahren
+func formatSeasonSummary(config Config, index int, s periodSummary, previous []periodSummary) (string, string) {
+	title := seasonNames[index].emoji + " " + tr(config, "%s in %s", seasonLabel(config, index, s.from, s.to), config.StationName)
+
+	var means, rains, suns []float64
+	for _, p := range previous {
+		means = append(means, p.tMean)
+		rains = append(rains, p.rainSum)
+		suns = append(suns, p.sunMinutes/60)
+	}
+	temp, rain := unitOf(config, quantityTemperature), unitOf(config, quantityRain)
+	lines := []string{
+		tr(config, "Mitteltemperatur: %s", temp.format(s.tMean, 1)) +
+			seasonComparison(config, s.tMean, means, func(d float64) string { return temp.delta(d, 1, false) }),
+		tr(config, "Höchstwert: %s am %s, Tiefstwert: %s am %s", temp.format(s.tMax, 1), formatDayMonth(config, s.tMaxDay), temp.format(s.tMin, 1), formatDayMonth(config, s.tMinDay)),
+		tr(config, "Niederschlag: %s an %d Tagen", rain.format(s.rainSum, 1), s.rainDays) +
+			seasonComparison(config, s.rainSum, rains, func(d float64) string { return rain.delta(d, 0, false) }),
+	}
+	if !math.IsNaN(s.sunMinutes) {
+		lines = append(lines, tr(config, "Sonnenscheindauer: %.0f h", s.sunMinutes/60)+
+			seasonComparison(config, s.sunMinutes/60, suns, func(d float64) string { return fmt.Sprintf("%.0f h", d) }))
+	}
+	lines = append(lines, tr(config, "Kenntage: ")+formatClimateDayCounts(config, s.climateDays))
+	if len(previous) == 0 {
+		lines = append(lines, tr(config, "Noch keine Vergleichswerte aus den Vorjahren."))
+	}
+	return title, strings.Join(lines, "\n")
+}
+
+// publishSeasonSummary veröffentlicht am ersten Tag einer Jahreszeit den Rückblick auf die vergangene.
+// Der Status wird unter "season-" und dem Beginn der Jahreszeit vermerkt.
+func publishSeasonSummary(db, store *sql.DB, loc *time.Location, config Config, now time.Time, testMode, loopMode bool) {
+	if !config.SeasonSummaryEnabled {
+		return
+	}
+	starts, err := seasonStartDates(config, now)
+	if err != nil {
+		log.Printf("Warnung: Jahreszeiten: %v", err)
+		return
+	}
+	index, from, to, ok := endedSeason(starts, now)
+	if !ok {
+		return
+	}
+	s, err := getPeriodSummary(db, loc, config, from, to)
+	if err != nil {
+		log.Printf("Rückblick %s nicht möglich: %v", seasonLabel(config, index, from, to), err)
+		return
+	}
+	title, text := formatSeasonSummary(config, index, s, previousSeasons(db, loc, config, from, to))
+	publishSummary(store, config, "season-"+from.Format("2006-01-02"), postTypeSeason, "Jahreszeitenrückblick", title, text, "", nil, testMode, loopMode)
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// postSection ist ein Abschnitt des Tagesposts, z.B. "summary" (Niederschlag und Sonnenschein) oder "pressure"
+type postSection struct {
+	name  string
+	text  string
+	block *textBlock // statt text: Abschnitt, der je nach Textformat der Plattform als Tabelle erscheint
+}
+
+// sectionDropOrder legt fest, in welcher Reihenfolge Abschnitte bei knappem Zeichenlimit entfallen: zuerst
+// Rückblicke und Einordnungen, zuletzt die Ereignisse des Tages. Nicht aufgeführte Abschnitte (Zusammenfassung,
+// Hinweise, Niederschlagshinweis) bleiben immer erhalten.
+var sectionDropOrder = []string{
+	"on_this_day", "anniversaries", "day_summary", "sparkline", "sun_times", "moon_phase", "dwd_comparison", "percentiles", "normal_departure", "temp_anomaly",
+	"month_ranking", "month_extremes", "month_to_date", "rain_year", "year_to_date_rain",
+	"cloud_base", "dewpoint", "pressure", "energy_degree_days", "chill_hours", "growing_degree_days",
+	"et", "wind_run", "gust_factor", "solar_energy", "extreme_times", "leaf_wetness", "soil", "air_quality",
+	"felt_temperature", "rain_intensity", "rain_duration", "heavy_rain", "climate_days", "heat_streak", "frost_streak",
+	"rain_streak", "dry_spell", "tropical_night", "frost_dates", "snow", "lightning", "storm_day", "records",
+}
+
+// sectionGroups fasst die Abschnitte des Tagesposts zu Gruppen zusammen, die sich in sections als Ganzes
+// einordnen lassen. Jeder Abschnitt gehört zu genau einer Gruppe.
+var sectionGroups = map[string][]string{
+	"core": {"day_summary", "summary", "extreme_times", "sparkline", "solar_energy", "heavy_rain", "rain_duration",
+		"rain_intensity", "rain_caveat", "felt_temperature", "wind_run", "et", "storm_day", "gust_factor", "pressure",
+		"dewpoint", "cloud_base", "advisories", "soil", "leaf_wetness", "air_quality", "lightning", "snow"},
+	"streaks":   {"climate_days", "frost_dates", "tropical_night", "dry_spell", "rain_streak", "frost_streak", "heat_streak"},
+	"records":   {"records", "anniversaries", "on_this_day"},
+	"astronomy": {"sun_times", "moon_phase"},
+	"comparisons": {"month_to_date", "month_extremes", "month_ranking", "year_to_date_rain", "rain_year", "temp_anomaly",
+		"normal_departure", "percentiles", "dwd_comparison", "growing_degree_days", "chill_hours", "energy_degree_days"},
+}
+
+// validateSections prüft, dass sections nur Gruppen und Abschnitte des Tagesposts nennt
+func validateSections(config Config) error {
+	for _, name := range config.Sections {
+		if _, ok := sectionGroups[name]; ok {
+			continue
+		}
+		if sectionGroup(name) == "" {
+			return fmt.Errorf("unbekannter Abschnitt %q in sections (Gruppen: core, streaks, records, astronomy, comparisons)", name)
+		}
+	}
+	return nil
+}
+
+// sectionGroup liefert die Gruppe des Abschnitts name, leer für unbekannte Abschnitte
+func sectionGroup(name string) string {
+	for group, names := range sectionGroups {
+		for _, n := range names {
+			if n == name {
+				return group
+			}
+		}
+	}
+	return ""
+}
+
+// sectionEnabled prüft, ob der Abschnitt name nach sections im Tagespost erscheinen kann
+func sectionEnabled(config Config, name string) bool {
+	if len(config.Sections) == 0 {
+		return true
+	}
+	group := sectionGroup(name)
+	for _, s := range config.Sections {
+		if s == name || s == group {
+			return true
+		}
+	}
+	return false
+}
+
+// arrange ordnet die Abschnitte nach sections: Gruppen und einzelne Abschnitte in der angegebenen Reihenfolge,
+// innerhalb einer Gruppe in der Standardreihenfolge. Nicht genannte Abschnitte entfallen; ohne sections
+// bleibt der Post unverändert.
+func (p *dailyPost) arrange(config Config) {
+	if len(config.Sections) == 0 {
+		return
+	}
+	placed := make([]bool, len(p.sections))
+	var arranged []postSection
+	for _, name := range config.Sections {
+		_, isGroup := sectionGroups[name]
+		for i, s := range p.sections {
+			if !placed[i] && (s.name == name || isGroup && sectionGroup(s.name) == name) {
+				arranged = append(arranged, s)
+				placed[i] = true
+			}
+		}
+	}
+	p.sections = arranged
+}
+
+// add hängt einen Abschnitt an; leere Abschnitte werden übergangen
+func (p *dailyPost) add(name, text string) {
+	if text != "" {
+		p.sections = append(p.sections, postSection{name: name, text: text})
+	}
+}
+
+// render setzt den Standardtext im Textformat m aus den Abschnitten zusammen
+func (p *dailyPost) render(config Config, m markup) {
+	var lines []string
+	for _, s := range p.sections {
+		if s.block != nil {
+			lines = append(lines, "\n"+m.block(*s.block))
+			continue
+		}
+		lines = append(lines, s.text)
+	}
+	p.data.Text = strings.Join(lines, "\n")
+	p.data.Markdown = m.markdown
+	p.text = p.data.Text
+}
+
+// without liefert den Post ohne die Abschnitte name; ok ist false, wenn es keinen solchen Abschnitt gibt
+func (p dailyPost) without(config Config, m markup, name string) (dailyPost, bool) {
+	var kept []postSection
+	for _, s := range p.sections {
+		if s.name != name {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) == len(p.sections) {
+		return p, false
+	}
+	p.sections = kept
+	p.render(config, m)
+	return p, true
+}
+
+// fitToLimit liefert Titel und Text für platform so, dass Titel und Text zusammen (getrennt durch einen
+// Zeilenumbruch, wie bei Mastodon gepostet) höchstens limit Zeichen lang sind. Dazu entfallen optionale Abschnitte
+// in der Reihenfolge von sectionDropOrder; reicht das nicht, wird der Text an einer Wortgrenze gekürzt.
+// limit <= 0 bedeutet kein Limit.
+func (p dailyPost) fitToLimit(config Config, platform string, limit int) (string, string) {
+	m := markupFor(config, platform)
+	if m.markdown {
+		p.render(config, m)
+	}
+	title, text := p.forPlatformUnlimited(config, platform)
+	if limit <= 0 {
+		return title, text
+	}
+	for _, name := range sectionDropOrder {
+		if postLength(title, text) <= limit {
+			return title, text
+		}
+		if shorter, ok := p.without(config, m, name); ok {
+			p = shorter
+			title, text = p.forPlatformUnlimited(config, platform)
+		}
+	}
+	return title, truncateText(text, limit-postLength(title, ""))
+}
+
+// postLength zählt die Zeichen von Titel und Text wie beim Posten (Titel, Zeilenumbruch, Text)
+func postLength(title, text string) int {
+	return utf8.RuneCountInString(title) + 1 + utf8.RuneCountInString(text)
+}
+
+// fitText kürzt einen fertigen Post (z.B. Rückblick oder Warn-Post) auf das Zeichenlimit der Plattform
+func fitText(config Config, platform, text string) string {
+	if limit := config.CharLimits[platform]; limit > 0 {
+		return truncateText(text, limit)
+	}
+	return text
+}
+
+// truncateText kürzt text auf höchstens limit Zeichen. Gekürzt wird an der letzten Wortgrenze, das Auslassungszeichen
+// "…" zählt mit. Ohne Wortgrenze wird hart gekürzt.
+func truncateText(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	if limit <= 0 {
+		return ""
+	}
+	cut := limit - 1
+	for i := cut; i > 0; i-- {
+		if unicode.IsSpace(runes[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(runes[:cut]), unicode.IsSpace) + "…"
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+// shieldsBadge ist das JSON-Format des shields.io-Endpoint-Badges (https://shields.io/badges/endpoint-badge)
+type shieldsBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// serveHTTP startet den HTTP-Server für öffentliche Endpunkte (blockiert)
+func serveHTTP(addr, dbPath string, config Config) error {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("timezone: %v", err)
+	}
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return fmt.Errorf("open DB: %v", err)
+	}
+	defer db.Close()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/badge/dry-streak.json", func(w http.ResponseWriter, r *http.Request) {
+		days := countDaysSinceRain(db, loc, config, time.Now().In(loc))
+		badge := shieldsBadge{SchemaVersion: 1, Label: "Trockenperiode", Message: fmt.Sprintf("%d Tage", days)}
+		switch {
+		case days == 0:
+			badge.Color = "blue"
+		case days < config.DrySpellThreshold:
+			badge.Color = "green"
+		case days < 14:
+			badge.Color = "yellow"
+		default:
+			badge.Color = "orange"
+		}
+		writeBadge(w, badge)
+	})
+	mux.HandleFunc("/badge/rain-yesterday.json", func(w http.ResponseWriter, r *http.Request) {
+		now := time.Now().In(loc)
+		yesterday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+		badge := shieldsBadge{SchemaVersion: 1, Label: "Regen gestern", Message: "keine Daten", Color: "lightgrey"}
+		if rain, ok := rainOnDay(db, loc, config, yesterday); ok {
+			// ein gemessener trockener Tag soll sich von fehlenden Daten (grau) unterscheiden
+			badge.Message, badge.Color = fmt.Sprintf("%.1f mm", rain), "yellow"
+			if rain > 0 {
+				badge.Color = "blue"
+			}
+		}
+		writeBadge(w, badge)
+	})
+
+	log.Printf("HTTP-Server lauscht auf %s", addr)
+	return http.ListenAndServe(addr, mux)
+}
+
+// writeBadge schreibt ein Badge als JSON; kurze Cache-Dauer, da shields.io selbst cacht
+func writeBadge(w http.ResponseWriter, badge shieldsBadge) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "max-age=300")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	if err := json.NewEncoder(w).Encode(badge); err != nil {
+		log.Printf("Badge konnte nicht geschrieben werden: %v", err)
+	}
+}
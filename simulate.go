@@ -0,0 +1,67 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+)
+
+// runSimulation spielt die letzten days Tage durch die komplette Post-Erzeugung und zeigt, was an jedem Tag
+// gepostet worden wäre. So lassen sich neue Regeln und Schwellen an echten Daten prüfen, bevor sie aktiv werden.
+// Es wird nichts veröffentlicht und nichts im Zustandsspeicher fortgeschrieben; Jahrestage beruhen auf den
+// heutigen Rekorden.
+func runSimulation(dbPath string, config Config, days int) error {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("timezone: %v", err)
+	}
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return fmt.Errorf("open DB: %v", err)
+	}
+	defer db.Close()
+
+	store, err := openStore(config.StorePath)
+	if err != nil {
+		log.Printf("Warnung: %v – Jahrestage und externe Daten nicht verfügbar", err)
+	} else {
+		defer store.Close()
+	}
+
+	today := time.Now().In(loc)
+	for i := days; i >= 1; i-- {
+		// Lauf zur üblichen Uhrzeit am Folgetag des simulierten Tages
+		now := time.Date(today.Year(), today.Month(), today.Day()-i+1, 0, dailyRunMinute(config), 0, 0, loc)
+		startYesterday := time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, loc)
+		startDayBefore := startYesterday.AddDate(0, 0, -1)
+
+		fmt.Printf("\n=== SIMULATION %s ===\n", startYesterday.Format("02.01.2006"))
+		statsY, err := getStats(db, loc, config, startYesterday.Unix(), startYesterday.AddDate(0, 0, 1).Unix())
+		if err != nil {
+			return fmt.Errorf("Tageswerte %s: %v", startYesterday.Format("02.01.2006"), err)
+		}
+		statsV, err := getStats(db, loc, config, startDayBefore.Unix(), startYesterday.Unix())
+		if err != nil {
+			return fmt.Errorf("Tageswerte %s: %v", startDayBefore.Format("02.01.2006"), err)
+		}
+		if problems := plausibilityProblems(statsY); len(problems) > 0 {
+			fmt.Printf("Kein Post: unplausible Wetterdaten (%s)\n", strings.Join(problems, "; "))
+			continue
+		}
+		if math.IsNaN(statsV.tMax) || math.IsNaN(statsV.tMin) {
+			fmt.Printf("Kein Post: keine Daten für den Vortag\n")
+			continue
+		}
+
+		post := composeDailyPost(db, store, loc, config, now, startYesterday, statsY, statsV)
+		fmt.Printf("Titel: %s\n%s\n", post.title, post.text)
+		for _, alert := range post.alerts {
+			fmt.Printf("--- Warn-Post an Mastodon ---\n%s\n", alert)
+		}
+	}
+	fmt.Printf("=== ENDE SIMULATION ===\n")
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SkinImage ist ein vom weewx-Report-Generator erzeugtes Bild, das statt der eigenen Diagramme am Tagespost hängt,
+// z.B. {"file": "daytempdew.png", "description": "Temperatur und Taupunkt der letzten 24 Stunden"}
+type SkinImage struct {
+	File        string `json:"file"`        // Dateiname, relativ zu skin_image_dir oder absolut
+	Description string `json:"description"` // Alt-Text; leer = allgemeine Beschreibung mit Dateinamen
+}
+
+// skinImageTypes sind die unterstützten Bildformate nach Dateiendung
+var skinImageTypes = map[string]string{
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".gif":  "image/gif",
+}
+
+// validateSkinImages prüft skin_images und skin_image_max_age
+func validateSkinImages(config Config) error {
+	for _, img := range config.SkinImages {
+		if img.File == "" {
+			return fmt.Errorf("skin_images: Dateiname fehlt")
+		}
+		if _, ok := skinImageTypes[strings.ToLower(filepath.Ext(img.File))]; !ok {
+			return fmt.Errorf("skin_images: nicht unterstütztes Bildformat %q (png, jpg, gif)", img.File)
+		}
+	}
+	if config.SkinImageMaxAge <= 0 {
+		return fmt.Errorf("ungültiges Höchstalter der weewx-Bilder (skin_image_max_age > 0)")
+	}
+	return nil
+}
+
+// skinImagePath liefert den Pfad eines weewx-Bildes
+func skinImagePath(config Config, file string) string {
+	if filepath.IsAbs(file) || config.SkinImageDir == "" {
+		return file
+	}
+	return filepath.Join(config.SkinImageDir, file)
+}
+
+// checkSkinImageFresh prüft, ob ein weewx-Bild mit der Änderungszeit modified den Tag day vollständig zeigt: Es muss
+// nach Ende des Tages, aber noch am Folgetag erzeugt worden sein (die Tagesbilder von weewx zeigen die letzten
+// 24 bis 27 Stunden), und darf höchstens maxAge alt sein, sonst läuft der Report-Generator vermutlich nicht mehr.
+func checkSkinImageFresh(day time.Time, modified, now time.Time, maxAge time.Duration) error {
+	end := day.AddDate(0, 0, 1)
+	if modified.Before(end) {
+		return fmt.Errorf("vor Ende des Tages erzeugt (%s)", modified.Format("02.01.2006 15:04"))
+	}
+	if !modified.Before(end.AddDate(0, 0, 1)) {
+		return fmt.Errorf("zeigt nicht mehr den %s (erzeugt %s)", day.Format("02.01.2006"), modified.Format("02.01.2006 15:04"))
+	}
+	if age := now.Sub(modified); age > maxAge {
+		return fmt.Errorf("veraltet (erzeugt vor %.0f Minuten)", age.Minutes())
+	}
+	return nil
+}
+
+// skinImageMedia kopiert ein weewx-Bild als temporäre Datei, damit weewx es währenddessen ersetzen kann und
+// removeMedia nach dem Posten nicht das Original löscht
+func skinImageMedia(config Config, img SkinImage, day time.Time) (mediaAttachment, error) {
+	path := skinImagePath(config, img.File)
+	info, err := os.Stat(path)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	maxAge := time.Duration(config.SkinImageMaxAge) * time.Minute
+	if err := checkSkinImageFresh(day, info.ModTime().In(day.Location()), time.Now(), maxAge); err != nil {
+		return mediaAttachment{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mediaAttachment{}, err
+	}
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	description := img.Description
+	if description == "" {
+		description = tr(config, "Wetterdiagramm der Station (%s)", base)
+	}
+	return writeMediaFile(strings.TrimSuffix(base, ext)+"-*"+ext, data, skinImageTypes[strings.ToLower(ext)], description)
+}
+
+// skinMedia liefert die aktuellen weewx-Bilder (skin_images) zum Tagespost; veraltete oder fehlende Bilder entfallen
+// mit einer Warnung
+func skinMedia(config Config, day time.Time) []mediaAttachment {
+	var media []mediaAttachment
+	for _, img := range config.SkinImages {
+		m, err := skinImageMedia(config, img, day)
+		if err != nil {
+			log.Printf("Warnung: weewx-Bild %s nicht verwendet: %v", img.File, err)
+			continue
+		}
+		media = append(media, m)
+	}
+	return media
+}
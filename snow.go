@@ -0,0 +1,113 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+)
+
+// getSnowStats ermittelt Neuschnee und Schneehöhe am Tagesende (cm). Ohne Neuschneespalte wird der
+// Neuschnee aus dem Anstieg der Schneehöhe gegenüber dem Tagesbeginn abgeschätzt.
+// Fehlen die Spalten, sind beide Werte NaN.
+func getSnowStats(db *sql.DB, config Config, start, end int64) (float64, float64, error) {
+	snowFall, snowDepth := math.NaN(), math.NaN()
+	depthColumn := optionalColumn(db, config.SnowDepthColumn)
+	fallColumn := optionalColumn(db, config.SnowfallColumn)
+
+	if depthColumn != "" {
+		q := `SELECT ` + depthColumn + ` FROM archive WHERE dateTime >= ? AND dateTime < ? AND ` + depthColumn + ` IS NOT NULL ORDER BY dateTime;`
+		rows, err := db.Query(q, start, end)
+		if err != nil {
+			return snowFall, snowDepth, err
+		}
+		first, maxDepth := math.NaN(), math.NaN()
+		for rows.Next() {
+			var depth float64
+			if err := rows.Scan(&depth); err != nil {
+				rows.Close()
+				return snowFall, snowDepth, err
+			}
+			if math.IsNaN(first) {
+				first = depth
+			}
+			if math.IsNaN(maxDepth) || depth > maxDepth {
+				maxDepth = depth
+			}
+			snowDepth = depth
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return snowFall, snowDepth, err
+		}
+		if fallColumn == "" && !math.IsNaN(first) {
+			snowFall = math.Max(0, maxDepth-first)
+		}
+	}
+
+	if fallColumn != "" {
+		var sum sql.NullFloat64
+		if err := db.QueryRow(`SELECT SUM(`+fallColumn+`) FROM archive WHERE dateTime >= ? AND dateTime < ?;`, start, end).Scan(&sum); err != nil {
+			return snowFall, snowDepth, err
+		}
+		if sum.Valid {
+			snowFall = sum.Float64
+		}
+	}
+	return snowFall, snowDepth, nil
+}
+
+// isFirstSnowOfSeason prüft, ob es in der laufenden Schneesaison (ab snow_season_start) vor dem Tag
+// day weder Neuschnee noch eine Schneedecke gab
+func isFirstSnowOfSeason(db *sql.DB, config Config, day time.Time) (bool, error) {
+	from, ok := seasonStart(day, config.SnowSeasonStart, previousMonthDay(config.SnowSeasonStart))
+	if !ok {
+		return false, nil
+	}
+	var conditions []string
+	if c := optionalColumn(db, config.SnowDepthColumn); c != "" {
+		conditions = append(conditions, c+" > 0")
+	}
+	if c := optionalColumn(db, config.SnowfallColumn); c != "" {
+		conditions = append(conditions, c+" > 0")
+	}
+	if len(conditions) == 0 {
+		return false, nil
+	}
+	q := `SELECT COUNT(*) FROM archive WHERE dateTime >= ? AND dateTime < ? AND (` + strings.Join(conditions, " OR ") + `);`
+	var count int
+	if err := db.QueryRow(q, from.Unix(), day.Unix()).Scan(&count); err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
+// previousMonthDay liefert den Vortag einer MM-DD-Angabe, damit eine Saison genau ein Jahr umfasst
+func previousMonthDay(md string) string {
+	t, err := time.Parse("01-02", md)
+	if err != nil {
+		return md
+	}
+	return t.AddDate(0, 0, -1).Format("01-02")
+}
+
+// formatSnow erzeugt die Schneezeile (❄️ Neuschnee, ☃️ Schneedecke) inkl. Hinweis auf den ersten Schnee der Saison
+func formatSnow(db *sql.DB, config Config, day time.Time, s dayStats) string {
+	fresh := !math.IsNaN(s.snowFall) && s.snowFall > 0
+	cover := !math.IsNaN(s.snowDepth) && s.snowDepth > 0
+	if !fresh && !cover {
+		return ""
+	}
+	var parts []string
+	if fresh {
+		parts = append(parts, tr(config, "❄️ Neuschnee: %s", unitOf(config, quantitySnow).format(s.snowFall, 0)))
+	}
+	if cover {
+		parts = append(parts, tr(config, "☃️ Schneehöhe: %s", unitOf(config, quantitySnow).format(s.snowDepth, 0)))
+	}
+	line := strings.Join(parts, ", ")
+	if first, err := isFirstSnowOfSeason(db, config, day); err == nil && first {
+		line += "\n" + tr(config, "Das war der erste Schnee der Saison!")
+	}
+	return line
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+)
+
+// getSoilStats ermittelt Minimum und Maximum von Bodentemperatur (°C) und Bodenfeuchte (cb) des Zeitraums.
+// Fehlt eine Spalte oder enthält sie nur NULL, sind die zugehörigen Werte NaN.
+func getSoilStats(db *sql.DB, config Config, start, end int64) (tempMin, tempMax, moistMin, moistMax float64, err error) {
+	tempMin, tempMax, err = columnExtremes(db, config.SoilTempColumn, start, end)
+	if err != nil {
+		return
+	}
+	moistMin, moistMax, err = columnExtremes(db, config.SoilMoistColumn, start, end)
+	return
+}
+
+// columnExtremes liefert MIN und MAX einer optionalen archive-Spalte, NaN wenn sie fehlt oder leer ist
+func columnExtremes(db *sql.DB, name string, start, end int64) (float64, float64, error) {
+	column := optionalColumn(db, name)
+	if column == "" {
+		return math.NaN(), math.NaN(), nil
+	}
+	var min, max sql.NullFloat64
+	q := `SELECT MIN(` + column + `), MAX(` + column + `) FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	if err := db.QueryRow(q, start, end).Scan(&min, &max); err != nil {
+		return math.NaN(), math.NaN(), err
+	}
+	if !min.Valid || !max.Valid {
+		return math.NaN(), math.NaN(), nil
+	}
+	return min.Float64, max.Float64, nil
+}
+
+// formatSoil erzeugt die Bodenzeile; ohne Bodensensor bleibt sie leer
+func formatSoil(s dayStats, config Config) string {
+	var parts []string
+	if !math.IsNaN(s.soilTempMin) {
+		unit := unitOf(config, quantityTemperature)
+		parts = append(parts, tr(config, "%s bis %s %s", unit.number(s.soilTempMin, 1), unit.number(s.soilTempMax, 1), unit.symbol))
+	}
+	if !math.IsNaN(s.soilMoistMin) {
+		parts = append(parts, tr(config, "Feuchte %.0f bis %.0f cb", s.soilMoistMin, s.soilMoistMax))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	line := tr(config, "🌱 Boden: ") + parts[0]
+	if len(parts) > 1 {
+		line += ", " + parts[1]
+	}
+	return line
+}
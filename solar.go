@@ -0,0 +1,78 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+)
+
+// getSolarEnergy integriert die Globalstrahlung (W/m²) über die Archivintervalle und liefert
+// die eingestrahlte Energie in kWh/m². NaN, wenn keine Strahlungswerte vorliegen.
+func getSolarEnergy(db *sql.DB, start, end int64) (float64, error) {
+	column := optionalColumn(db, "radiation")
+	if column == "" {
+		return math.NaN(), nil
+	}
+	q := `SELECT SUM(` + column + ` * interval / 60.0), COUNT(` + column + `) FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	var wh sql.NullFloat64
+	var count int
+	if err := db.QueryRow(q, start, end).Scan(&wh, &count); err != nil {
+		return math.NaN(), err
+	}
+	if !wh.Valid || count == 0 {
+		return math.NaN(), nil
+	}
+	return wh.Float64 / 1000.0, nil
+}
+
+// formatSolarEnergy erzeugt die Zeile zur Sonnenenergie mit Vergleich zum Vortag
+func formatSolarEnergy(statsY, statsV dayStats, config Config) string {
+	if math.IsNaN(statsY.solarEnergy) {
+		return ""
+	}
+	line := tr(config, "Sonnenenergie: %.2f kWh/m²", statsY.solarEnergy)
+	if !math.IsNaN(statsV.solarEnergy) {
+		line += tr(config, " (Vortag: %.2f kWh/m²)", statsV.solarEnergy)
+	}
+	return line
+}
+
+// getSunshineMinutes summiert die Archivintervalle, in denen die Globalstrahlung die Sonnenschein-Schwelle
+// (sun_threshold, nach WMO 120 W/m²) erreicht, zur Sonnenscheindauer in Minuten. NaN, wenn keine Strahlungswerte vorliegen.
+func getSunshineMinutes(db *sql.DB, config Config, start, end int64) (float64, error) {
+	column := optionalColumn(db, "radiation")
+	if column == "" {
+		return math.NaN(), nil
+	}
+	q := `SELECT SUM(CASE WHEN ` + column + ` >= ? THEN interval ELSE 0 END), COUNT(` + column + `) FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	var minutes sql.NullFloat64
+	var count int
+	if err := db.QueryRow(q, config.SunThreshold, start, end).Scan(&minutes, &count); err != nil {
+		return math.NaN(), err
+	}
+	if !minutes.Valid || count == 0 {
+		return math.NaN(), nil
+	}
+	return minutes.Float64, nil
+}
+
+// formatDuration formatiert Minuten als "7 h 25 min"; fehlende Werte (NaN) als "–"
+func formatDuration(minutes float64) string {
+	if math.IsNaN(minutes) || math.IsInf(minutes, 0) {
+		return "–"
+	}
+	m := int(math.Round(minutes))
+	if m < 60 {
+		return fmt.Sprintf("%d min", m)
+	}
+	return fmt.Sprintf("%d h %d min", m/60, m%60)
+}
+
+// formatSunshine erzeugt die Angabe zum Sonnenschein für den Post. Standard ist die Sonnenscheindauer;
+// mit sunshine_legacy_hours (oder ohne Strahlungswerte) die bisherigen Stunden mit Sonnenschein.
+func formatSunshine(statsY, statsV dayStats, config Config) string {
+	if config.SunshineLegacyHours || math.IsNaN(statsY.sunMinutes) || math.IsNaN(statsV.sunMinutes) {
+		return tr(config, "Stunden mit Sonnenschein: %d h (Vortag: %d h)", statsY.sunHours, statsV.sunHours)
+	}
+	return tr(config, "Sonnenscheindauer: %s (Vortag: %s)", formatDuration(statsY.sunMinutes), formatDuration(statsV.sunMinutes))
+}
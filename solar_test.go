@@ -0,0 +1,22 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatDuration(t *testing.T) {
+	for _, tc := range []struct {
+		minutes float64
+		want    string
+	}{
+		{0, "0 min"},
+		{59.6, "1 h 0 min"},
+		{445, "7 h 25 min"},
+		{math.NaN(), "–"},
+	} {
+		if got := formatDuration(tc.minutes); got != tc.want {
+			t.Errorf("formatDuration(%v) = %q, want %q", tc.minutes, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// sparkBlocks sind die Stufen der Sparkline vom niedrigsten zum höchsten Wert
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// getHourlyTemperatures liefert die Stundenmittel von outTemp für die Stunden 0–23 (Ortszeit) des Zeitraums,
+// NaN für Stunden ohne Messwerte
+func getHourlyTemperatures(db *sql.DB, loc *time.Location, start, end int64) ([24]float64, error) {
+	var sums, counts [24]float64
+	var hourly [24]float64
+	rows, err := db.Query(`SELECT dateTime, outTemp FROM archive WHERE dateTime >= ? AND dateTime < ? AND outTemp IS NOT NULL;`, start, end)
+	if err != nil {
+		return hourly, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var ts int64
+		var temp float64
+		if err := rows.Scan(&ts, &temp); err != nil {
+			return hourly, err
+		}
+		h := time.Unix(ts, 0).In(loc).Hour()
+		sums[h] += temp
+		counts[h]++
+	}
+	for h := range hourly {
+		hourly[h] = math.NaN()
+		if counts[h] > 0 {
+			hourly[h] = sums[h] / counts[h]
+		}
+	}
+	return hourly, rows.Err()
+}
+
+// sparkline stellt die Werte als Folge von Blockzeichen dar, skaliert zwischen kleinstem und größtem Wert.
+// Fehlende Werte (NaN) erscheinen als Leerzeichen.
+func sparkline(values []float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	spark := make([]rune, len(values))
+	for i, v := range values {
+		switch {
+		case math.IsNaN(v):
+			spark[i] = ' '
+		case hi == lo:
+			spark[i] = sparkBlocks[len(sparkBlocks)/2]
+		default:
+			spark[i] = sparkBlocks[int(math.Round((v-lo)/(hi-lo)*float64(len(sparkBlocks)-1)))]
+		}
+	}
+	return string(spark)
+}
+
+// formatTemperatureSparkline erzeugt die Zeile mit dem Temperaturverlauf des Tages als Sparkline aus 24 Stundenmitteln,
+// z.B. "Temperaturverlauf: ▁▁▁▂▃▅▆▇██▇▅▃▂ (6.8 bis 18.3 °C)". Leer, wenn deaktiviert oder ohne Messwerte.
+func formatTemperatureSparkline(db *sql.DB, loc *time.Location, config Config, day time.Time) string {
+	if !config.SparklineEnabled {
+		return ""
+	}
+	hourly, err := getHourlyTemperatures(db, loc, day.Unix(), day.AddDate(0, 0, 1).Unix())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Temperaturverlauf nicht verfügbar: %v\n", err)
+		return ""
+	}
+	lo, hi := math.NaN(), math.NaN()
+	for _, v := range hourly {
+		if math.IsNaN(lo) || v < lo {
+			lo = v
+		}
+		if math.IsNaN(hi) || v > hi {
+			hi = v
+		}
+	}
+	if math.IsNaN(lo) {
+		return ""
+	}
+	unit := unitOf(config, quantityTemperature)
+	return tr(config, "Temperaturverlauf: %s (%s bis %s)", sparkline(hourly[:]), unit.number(lo, 1), unit.format(hi, 1))
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Arten von Posts, für die sich in detail_urls ein eigener Link hinterlegen lässt
+const (
+	detailDaily   = "daily"   // Tagespost
+	detailWeekly  = "weekly"  // Zeitraumrückblicke bis zu einer Woche
+	detailMonthly = "monthly" // Bestenlisten am Monatsersten, Zeitraumrückblicke bis zu einem Monat
+	detailYearly  = "yearly"  // Jahresrückblick, längere Zeitraumrückblicke
+)
+
+// validateDetailURLs prüft detail_urls
+func validateDetailURLs(config Config) error {
+	for kind := range config.DetailURLs {
+		switch kind {
+		case detailDaily, detailWeekly, detailMonthly, detailYearly:
+		default:
+			return fmt.Errorf("unbekannte Post-Art %q in detail_urls (daily, weekly, monthly, yearly)", kind)
+		}
+	}
+	return nil
+}
+
+// detailLine liefert den Hinweis auf die Detailseite für eine Post-Art, leer ohne Link
+func detailLine(config Config, kind string) string {
+	if url := config.DetailURLs[kind]; url != "" {
+		return tr(config, "Details: %s", url)
+	}
+	return ""
+}
+
+// rangeDetailKind wählt den Link eines Zeitraumrückblicks [from, to] nach seiner Länge
+func rangeDetailKind(from, to time.Time) string {
+	switch days := int(to.Sub(from).Hours()/24+0.5) + 1; {
+	case days <= 1:
+		return detailDaily
+	case days <= 7:
+		return detailWeekly
+	case days <= 31:
+		return detailMonthly
+	default:
+		return detailYearly
+	}
+}
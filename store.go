@@ -0,0 +1,239 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// storeSchema beschreibt die Tabellen des lokalen Zustandsspeichers.
+// Alle Anweisungen sind idempotent, damit ältere Speicherdateien beim Öffnen erweitert werden.
+var storeSchema = []string{
+	`CREATE TABLE IF NOT EXISTS qc_events (
+		day     TEXT    NOT NULL,
+		kind    TEXT    NOT NULL,
+		source  TEXT    NOT NULL,
+		count   INTEGER NOT NULL DEFAULT 1,
+		message TEXT    NOT NULL DEFAULT '',
+		created INTEGER NOT NULL,
+		UNIQUE(day, kind, source)
+	);`,
+	`CREATE TABLE IF NOT EXISTS admin_reports (
+		period TEXT    PRIMARY KEY,
+		sent   INTEGER NOT NULL
+	);`,
+	`CREATE TABLE IF NOT EXISTS records (
+		metric   TEXT NOT NULL,
+		day      TEXT NOT NULL,
+		value    REAL NOT NULL,
+		previous REAL,
+		PRIMARY KEY (metric, day)
+	);`,
+	`CREATE TABLE IF NOT EXISTS published_posts (
+		day       TEXT    PRIMARY KEY,
+		title     TEXT    NOT NULL,
+		body      TEXT    NOT NULL,
+		published INTEGER NOT NULL
+	);`,
+	`CREATE TABLE IF NOT EXISTS publish_status (
+		day      TEXT    NOT NULL,
+		platform TEXT    NOT NULL,
+		status   TEXT    NOT NULL,
+		message  TEXT    NOT NULL DEFAULT '',
+		attempts INTEGER NOT NULL DEFAULT 0,
+		updated  INTEGER NOT NULL,
+		PRIMARY KEY (day, platform)
+	);`,
+	`CREATE TABLE IF NOT EXISTS api_budget (
+		day      TEXT    NOT NULL,
+		platform TEXT    NOT NULL,
+		calls    INTEGER NOT NULL DEFAULT 0,
+		PRIMARY KEY (day, platform)
+	);`,
+	`CREATE TABLE IF NOT EXISTS external_cache (
+		url     TEXT    PRIMARY KEY,
+		body    TEXT    NOT NULL,
+		fetched INTEGER NOT NULL
+	);`,
+	`CREATE TABLE IF NOT EXISTS daily_stats (
+		day     TEXT    PRIMARY KEY,
+		updated INTEGER NOT NULL
+	);`,
+}
+
+// storeColumn beschreibt eine Spalte einer Speichertabelle und wie ihr Wert aus den Tageswerten entsteht
+type storeColumn struct {
+	name, typ string
+	value     func(s dayStats) interface{}
+}
+
+// dailyStatsColumns sind die Messgrößen in daily_stats. Neue Spalten werden beim Öffnen
+// per ALTER TABLE ergänzt, damit bestehende Speicher nicht neu importiert werden müssen.
+var dailyStatsColumns = []storeColumn{
+	{"t_max", "REAL", func(s dayStats) interface{} { return nullable(s.tMax) }},
+	{"t_min", "REAL", func(s dayStats) interface{} { return nullable(s.tMin) }},
+	{"rain_sum", "REAL", func(s dayStats) interface{} { return nullable(s.rainSum) }},
+	{"sun_hours", "INTEGER", func(s dayStats) interface{} { return s.sunHours }},
+	{"baro_mean", "REAL", func(s dayStats) interface{} { return nullable(s.pressure.mean) }},
+	{"baro_change", "REAL", func(s dayStats) interface{} { return nullable(s.pressure.change) }},
+	{"baro_trend", "REAL", func(s dayStats) interface{} { return nullable(s.pressure.trend) }},
+	{"dew_min", "REAL", func(s dayStats) interface{} { return nullable(s.dewMin) }},
+	{"dew_max", "REAL", func(s dayStats) interface{} { return nullable(s.dewMax) }},
+	{"heat_index_max", "REAL", func(s dayStats) interface{} { return nullable(s.heatIndexMax) }},
+	{"wind_chill_min", "REAL", func(s dayStats) interface{} { return nullable(s.windChillMin) }},
+	{"wind_run", "REAL", func(s dayStats) interface{} { return nullable(s.windRun) }},
+	{"gust_max", "REAL", func(s dayStats) interface{} { return nullable(s.gustMax) }},
+	{"wind_mean", "REAL", func(s dayStats) interface{} { return nullable(s.windMean) }},
+	{"night_min", "REAL", func(s dayStats) interface{} { return nullable(s.nightMin) }},
+	{"snow_fall", "REAL", func(s dayStats) interface{} { return nullable(s.snowFall) }},
+	{"snow_depth", "REAL", func(s dayStats) interface{} { return nullable(s.snowDepth) }},
+	{"et", "REAL", func(s dayStats) interface{} { return nullable(s.et) }},
+	{"lightning_strikes", "REAL", func(s dayStats) interface{} { return nullable(s.lightningStrikes) }},
+	{"lightning_distance", "REAL", func(s dayStats) interface{} { return nullable(s.lightningDistance) }},
+	{"soil_temp_min", "REAL", func(s dayStats) interface{} { return nullable(s.soilTempMin) }},
+	{"soil_temp_max", "REAL", func(s dayStats) interface{} { return nullable(s.soilTempMax) }},
+	{"soil_moist_min", "REAL", func(s dayStats) interface{} { return nullable(s.soilMoistMin) }},
+	{"soil_moist_max", "REAL", func(s dayStats) interface{} { return nullable(s.soilMoistMax) }},
+	{"pm25_mean", "REAL", func(s dayStats) interface{} { return nullable(s.pm25Mean) }},
+	{"pm25_max", "REAL", func(s dayStats) interface{} { return nullable(s.pm25Max) }},
+	{"pm10_mean", "REAL", func(s dayStats) interface{} { return nullable(s.pm10Mean) }},
+	{"pm10_max", "REAL", func(s dayStats) interface{} { return nullable(s.pm10Max) }},
+	{"rain_rate_max", "REAL", func(s dayStats) interface{} { return nullable(s.rainRateMax) }},
+	{"heaviest_hour", "INTEGER", func(s dayStats) interface{} {
+		if s.heaviestHour < 0 {
+			return nil
+		}
+		return s.heaviestHour
+	}},
+	{"heaviest_hour_rain", "REAL", func(s dayStats) interface{} { return nullable(s.heaviestHourRain) }},
+	{"solar_energy", "REAL", func(s dayStats) interface{} { return nullable(s.solarEnergy) }},
+	{"sun_minutes", "REAL", func(s dayStats) interface{} { return nullable(s.sunMinutes) }},
+	{"rain_hours", "INTEGER", func(s dayStats) interface{} { return s.rainHours }},
+	{"rain_minutes", "REAL", func(s dayStats) interface{} { return nullable(s.rainMinutes) }},
+	{"uv_max", "REAL", func(s dayStats) interface{} { return nullable(s.uvMax) }},
+	{"t_max_time", "INTEGER", func(s dayStats) interface{} { return nullableTime(s.tMaxTime) }},
+	{"t_min_time", "INTEGER", func(s dayStats) interface{} { return nullableTime(s.tMinTime) }},
+	{"in_temp_min", "REAL", func(s dayStats) interface{} { return nullable(s.inTempMin) }},
+	{"in_temp_max", "REAL", func(s dayStats) interface{} { return nullable(s.inTempMax) }},
+	{"in_hum_min", "REAL", func(s dayStats) interface{} { return nullable(s.inHumMin) }},
+	{"in_hum_max", "REAL", func(s dayStats) interface{} { return nullable(s.inHumMax) }},
+	{"cloud_base_min", "REAL", func(s dayStats) interface{} { return nullable(s.cloudBaseMin) }},
+	{"cloud_base_max", "REAL", func(s dayStats) interface{} { return nullable(s.cloudBaseMax) }},
+	{"gust_max_time", "INTEGER", func(s dayStats) interface{} { return nullableTime(s.gustTime) }},
+	{"leaf_wet_hours", "REAL", func(s dayStats) interface{} { return nullable(s.leafWetHours) }},
+}
+
+// openStore öffnet (oder erstellt) den lokalen Zustandsspeicher des Programms.
+// Er liegt bewusst getrennt von der weewx-Datenbank, die nur gelesen wird.
+func openStore(path string) (*sql.DB, error) {
+	store, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("Zustandsspeicher %s kann nicht geöffnet werden: %v", path, err)
+	}
+	for _, stmt := range storeSchema {
+		if _, err := store.Exec(stmt); err != nil {
+			store.Close()
+			return nil, fmt.Errorf("Schema des Zustandsspeichers %s: %v", path, err)
+		}
+	}
+	if err := addMissingColumns(store, "daily_stats", dailyStatsColumns); err != nil {
+		store.Close()
+		return nil, fmt.Errorf("Schema des Zustandsspeichers %s: %v", path, err)
+	}
+	return store, nil
+}
+
+// addMissingColumns ergänzt fehlende Spalten einer Tabelle
+func addMissingColumns(store *sql.DB, table string, columns []storeColumn) error {
+	rows, err := store.Query(`PRAGMA table_info(` + table + `);`)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, typ string
+		var dflt sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dflt, &pk); err != nil {
+			rows.Close()
+			return err
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, c := range columns {
+		if existing[c.name] {
+			continue
+		}
+		if _, err := store.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + c.name + ` ` + c.typ + `;`); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nullable wandelt NaN in NULL um, damit fehlende Messwerte nicht als Zahl gespeichert werden
+func nullable(v float64) interface{} {
+	if math.IsNaN(v) {
+		return nil
+	}
+	return v
+}
+
+// nullableTime wandelt einen unbekannten Zeitpunkt (0) in NULL um
+func nullableTime(ts int64) interface{} {
+	if ts == 0 {
+		return nil
+	}
+	return ts
+}
+
+// dayStatsMap liefert die Tageswerte mit den Spaltennamen aus daily_stats, fehlende Werte als nil.
+// Innenraumwerte (in_*) nur mit indoor.
+func dayStatsMap(s dayStats, indoor bool) map[string]interface{} {
+	stats := make(map[string]interface{})
+	for _, c := range dailyStatsColumns {
+		if strings.HasPrefix(c.name, "in_") && !indoor {
+			continue
+		}
+		stats[c.name] = c.value(s)
+	}
+	return stats
+}
+
+// saveDayStats speichert die Tageswerte eines Tages (Format 2006-01-02) im Zustandsspeicher
+func saveDayStats(store *sql.DB, day string, s dayStats) error {
+	names := []string{"day", "updated"}
+	args := []interface{}{day, time.Now().Unix()}
+	for _, c := range dailyStatsColumns {
+		names = append(names, c.name)
+		args = append(args, c.value(s))
+	}
+	q := `INSERT OR REPLACE INTO daily_stats (` + strings.Join(names, ", ") + `) VALUES (?` + strings.Repeat(", ?", len(names)-1) + `);`
+	_, err := store.Exec(q, args...)
+	return err
+}
+
+// storedDays liefert die Menge der bereits im Zustandsspeicher vorhandenen Tage
+func storedDays(store *sql.DB) (map[string]bool, error) {
+	rows, err := store.Query(`SELECT day FROM daily_stats;`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	days := make(map[string]bool)
+	for rows.Next() {
+		var day string
+		if err := rows.Scan(&day); err != nil {
+			return nil, err
+		}
+		days[day] = true
+	}
+	return days, rows.Err()
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// beaufortGust beschreibt eine Windstärke nach Beaufort mit Untergrenze (km/h) und Bezeichnung der Böen
+// nach den Warnstufen des DWD
+type beaufortGust struct {
+	force int
+	min   float64
+	name  string
+}
+
+// beaufortGusts sind die Windstärken ab 7, aufsteigend
+var beaufortGusts = []beaufortGust{
+	{7, 50, "Windböen"},
+	{8, 62, "stürmische Böen"},
+	{9, 75, "Sturmböen"},
+	{10, 89, "schwere Sturmböen"},
+	{11, 103, "orkanartige Böen"},
+	{12, 118, "Orkanböen"},
+}
+
+// stormThreshold liefert die Böengeschwindigkeit (km/h), ab der ein Tag als Sturmtag gilt
+func stormThreshold(config Config) float64 {
+	for _, b := range beaufortGusts {
+		if b.force >= config.StormBeaufort {
+			return b.min
+		}
+	}
+	return beaufortGusts[len(beaufortGusts)-1].min
+}
+
+// gustCategory liefert die Stufe einer Böe; ok ist false unterhalb von Windstärke 7
+func gustCategory(gust float64) (beaufortGust, bool) {
+	var category beaufortGust
+	ok := false
+	for _, b := range beaufortGusts {
+		if gust >= b.min {
+			category, ok = b, true
+		}
+	}
+	return category, ok
+}
+
+// isStormDay prüft, ob die Spitzenböe des Tages die Sturmschwelle erreicht
+func isStormDay(s dayStats, config Config) bool {
+	return !math.IsNaN(s.gustMax) && s.gustMax >= stormThreshold(config)
+}
+
+// getGustTime ermittelt den Zeitpunkt der stärksten Böe (Unix-Zeit, 0 ohne Windwerte)
+func getGustTime(db *sql.DB, start, end int64) (int64, error) {
+	const q = `SELECT dateTime FROM archive WHERE dateTime >= ? AND dateTime < ? AND windGust IS NOT NULL
+		ORDER BY windGust DESC, dateTime LIMIT 1;`
+	var ts int64
+	err := db.QueryRow(q, start, end).Scan(&ts)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return ts, err
+}
+
+// countStormDays zählt die Sturmtage im Zeitraum [from, to] (inklusive) aus den Böen in archive
+func countStormDays(db *sql.DB, config Config, from, to time.Time) (int, error) {
+	threshold := stormThreshold(config)
+	count := 0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		gust, _, err := getWindExtremes(db, day.Unix(), day.AddDate(0, 0, 1).Unix())
+		if err != nil {
+			return count, err
+		}
+		if !math.IsNaN(gust) && gust >= threshold {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// stormEmoji liefert 🌪️ ab schweren Sturmböen (Windstärke 10), sonst 💨
+func stormEmoji(s dayStats) string {
+	if s.gustMax >= 89 {
+		return "🌪️"
+	}
+	return "💨"
+}
+
+// formatStormDay erzeugt den Satz zum Sturmtag mit Spitzenböe, Uhrzeit und Monatszählung,
+// z.B. "💨 Sturmtag: Sturmböen bis 78 km/h um 15:20 Uhr (2. Sturmtag im Oktober)"
+func formatStormDay(db *sql.DB, config Config, day time.Time, s dayStats) string {
+	if !isStormDay(s, config) {
+		return ""
+	}
+	line := stormEmoji(s) + tr(config, " Sturmtag: ")
+	if category, ok := gustCategory(s.gustMax); ok {
+		line += tr(config, "%s bis %s", tr(config, category.name), unitOf(config, quantityWind).format(s.gustMax, 0))
+	} else {
+		line += tr(config, "Böen bis %s", unitOf(config, quantityWind).format(s.gustMax, 0))
+	}
+	if t := formatClock(s.gustTime, day.Location()); t != "" {
+		line += tr(config, " um %s Uhr", t)
+	}
+
+	monthStart := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	count, err := countStormDays(db, config, monthStart, day)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: Sturmtage des Monats nicht verfügbar: %v\n", err)
+		return line
+	}
+	return line + tr(config, " (%s Sturmtag im %s)", ordinal(config, count), monthName(config, day.Month()))
+}
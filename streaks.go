@@ -0,0 +1,184 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"time"
+)
+
+// countDaysSinceRain zählt die Tage ohne Regen vor dem Tag von now, höchstens dry_spell_max_days (0 = unbegrenzt).
+// Tage ohne Daten beenden die Zählung. Mit Tageszusammenfassungen von weewx und Kalendertagen werden die Tage
+// rückwärts aus einer Abfrage über archive_day_rain gelesen, sonst einzeln aus archive berechnet.
+func countDaysSinceRain(db *sql.DB, loc *time.Location, config Config, now time.Time) int {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if config.RainDayStartHour == 0 && config.DailySource != dailySourceArchive {
+		days, err := drySpellFromDaySummaries(db, config, today)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warnung: Trockenperiode nicht ermittelbar: %v\n", err)
+		}
+		return days
+	}
+	daysSinceRain := 0
+	for i := 1; config.DrySpellMaxDays <= 0 || i <= config.DrySpellMaxDays; i++ {
+		rain, ok := rainOnDay(db, loc, config, today.AddDate(0, 0, -i))
+		if !ok {
+			break // Fehler oder kein Eintrag -> abbrechen
+		}
+		if rain > 0 {
+			break // Es hat geregnet
+		}
+		daysSinceRain++
+	}
+	return daysSinceRain
+}
+
+// drySpellFromDaySummaries zählt die Tage ohne Regen vor today rückwärts aus archive_day_rain. Die Zeilen werden
+// nur so weit gelesen, bis Regen fällt oder ein Tag fehlt.
+func drySpellFromDaySummaries(db *sql.DB, config Config, today time.Time) (int, error) {
+	limit := -1 // SQLite: ohne Begrenzung
+	if config.DrySpellMaxDays > 0 {
+		limit = config.DrySpellMaxDays
+	}
+	rows, err := db.Query(`SELECT dateTime, sum FROM archive_day_rain WHERE dateTime < ? ORDER BY dateTime DESC LIMIT ?;`, today.Unix(), limit)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+	days := 0
+	for rows.Next() {
+		var ts int64
+		var rain sql.NullFloat64
+		if err := rows.Scan(&ts, &rain); err != nil {
+			return days, err
+		}
+		expected := today.AddDate(0, 0, -days-1)
+		if time.Unix(ts, 0).In(today.Location()).Format("2006-01-02") != expected.Format("2006-01-02") || rain.Float64 > 0 {
+			return days, nil
+		}
+		days++
+	}
+	return days, rows.Err()
+}
+
+// longestDrySpell liefert die längste Trockenperiode vor dem Tag before (Tage ohne Daten unterbrechen sie)
+// mit ihrem letzten Tag; 0 ohne Daten.
+func longestDrySpell(db *sql.DB, loc *time.Location, config Config, before time.Time) (int, time.Time, error) {
+	var longest, streak int
+	var end time.Time
+	add := func(day time.Time, rain float64, ok bool) {
+		if !ok || rain > 0 {
+			streak = 0
+			return
+		}
+		streak++
+		if streak > longest {
+			longest, end = streak, day
+		}
+	}
+
+	if config.RainDayStartHour == 0 && config.DailySource != dailySourceArchive {
+		rows, err := db.Query(`SELECT dateTime, sum FROM archive_day_rain WHERE dateTime < ? ORDER BY dateTime;`, before.Unix())
+		if err != nil {
+			return 0, end, err
+		}
+		defer rows.Close()
+		var expected time.Time
+		for rows.Next() {
+			var ts int64
+			var rain sql.NullFloat64
+			if err := rows.Scan(&ts, &rain); err != nil {
+				return 0, end, err
+			}
+			day := time.Unix(ts, 0).In(loc)
+			if !expected.IsZero() && day.Format("2006-01-02") != expected.Format("2006-01-02") {
+				streak = 0 // Lücke
+			}
+			add(day, rain.Float64, true)
+			expected = day.AddDate(0, 0, 1)
+		}
+		return longest, end, rows.Err()
+	}
+
+	var first sql.NullInt64
+	if err := db.QueryRow(`SELECT MIN(dateTime) FROM archive;`).Scan(&first); err != nil || !first.Valid {
+		return 0, end, err
+	}
+	start := time.Unix(first.Int64, 0).In(loc)
+	for day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc); day.Before(before); day = day.AddDate(0, 0, 1) {
+		rain, ok := rainOnDay(db, loc, config, day)
+		add(day, rain, ok)
+	}
+	return longest, end, nil
+}
+
+// formatDrySpellRecord ergänzt den Hinweis auf eine anhaltende Trockenperiode von days Tagen vor dem Tag von now
+// um den Vergleich mit der längsten bisherigen, z.B. " Das ist die längste Trockenperiode seit Messbeginn (bisher 24 Tage bis 12.08.2025)."
+// In den ersten record_min_history_days Tagen der Aufzeichnung bleibt der Satz leer.
+func formatDrySpellRecord(db *sql.DB, loc *time.Location, config Config, now time.Time, days int) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	spellStart := today.AddDate(0, 0, -days)
+	var first sql.NullInt64
+	if err := db.QueryRow(`SELECT MIN(dateTime) FROM archive;`).Scan(&first); err != nil || !first.Valid {
+		return ""
+	}
+	if today.Sub(time.Unix(first.Int64, 0)) < time.Duration(config.RecordMinHistoryDays)*24*time.Hour {
+		return ""
+	}
+	longest, end, err := longestDrySpell(db, loc, config, spellStart)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warnung: längste Trockenperiode nicht ermittelbar: %v\n", err)
+		return ""
+	}
+	if longest == 0 || days <= longest {
+		return ""
+	}
+	return tr(config, " Das ist die längste Trockenperiode seit Messbeginn (bisher %d Tage bis %s).", longest, formatDate(config, end))
+}
+
+// countConsecutiveRainDays zählt die Tage mit Regen in Folge vor dem Tag von now (max. 30 Tage zurück)
+func countConsecutiveRainDays(db *sql.DB, loc *time.Location, config Config, now time.Time) int {
+	consecutiveRainDays := 0
+	for i := 1; i < 30; i++ {
+		day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -i)
+		rain, ok := rainOnDay(db, loc, config, day)
+		if !ok {
+			break // Fehler oder kein Eintrag -> abbrechen
+		}
+		if rain > 0 {
+			consecutiveRainDays++
+		} else {
+			break // Kein Regen -> Serie endet
+		}
+	}
+	return consecutiveRainDays
+}
+
+// countHeatStreak zählt die Tage in Folge bis einschließlich day, deren Höchstwert heat_streak_threshold erreicht
+func countHeatStreak(db *sql.DB, config Config, day time.Time) int {
+	return countTempStreak(db, config, day, func(t dailyTemp) bool { return t.tMax >= config.HeatStreakThreshold })
+}
+
+// countFrostStreak zählt die Tage in Folge bis einschließlich day mit Tiefstwert unter 0 °C
+func countFrostStreak(db *sql.DB, config Config, day time.Time) int {
+	return countTempStreak(db, config, day, func(t dailyTemp) bool { return t.tMin < 0 })
+}
+
+// countTempStreak zählt die Tage in Folge bis einschließlich day, deren Tageswerte test erfüllen
+// (max. 60 Tage zurück). Tage ohne Daten beenden die Serie.
+func countTempStreak(db *sql.DB, config Config, day time.Time, test func(t dailyTemp) bool) int {
+	temps, err := dailyTemperatures(db, config, day.AddDate(0, 0, -59), day)
+	if err != nil {
+		return 0
+	}
+	streak := 0
+	expected := day.Format("2006-01-02")
+	for i := len(temps) - 1; i >= 0; i-- {
+		if temps[i].day.Format("2006-01-02") != expected || !test(temps[i]) {
+			break
+		}
+		streak++
+		expected = day.AddDate(0, 0, -streak).Format("2006-01-02")
+	}
+	return streak
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTempStreaks(t *testing.T) {
+	loc := time.UTC
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, loc)
+	db := testArchive(t, nil)
+	if _, err := db.Exec(`CREATE TABLE archive_day_outTemp (dateTime INTEGER PRIMARY KEY, min REAL, max REAL);`); err != nil {
+		t.Fatal(err)
+	}
+	// Tiefst- und Höchstwert je Tag vor day (0 = day); der 4. Tag davor fehlt
+	days := map[int][2]float64{
+		-6: {-3, 2}, -5: {-2, 3}, -3: {1, 31}, -2: {-1, 32}, -1: {-4, 30}, 0: {-2, 31},
+	}
+	for offset, v := range days {
+		if _, err := db.Exec(`INSERT INTO archive_day_outTemp VALUES (?, ?, ?);`, day.AddDate(0, 0, offset).Unix(), v[0], v[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	config := DefaultConfig()
+	config.HeatStreakThreshold = 30
+
+	tests := []struct {
+		name string
+		f    func(day time.Time) int
+		day  time.Time
+		want int
+	}{
+		{"Hitze bis day", func(d time.Time) int { return countHeatStreak(db, config, d) }, day, 4},
+		{"Frost bis day", func(d time.Time) int { return countFrostStreak(db, config, d) }, day, 3},
+		{"Frost, Tag ohne Frost", func(d time.Time) int { return countFrostStreak(db, config, d) }, day.AddDate(0, 0, -3), 0},
+		{"Frost, Lücke beendet die Serie", func(d time.Time) int { return countFrostStreak(db, config, d) }, day.AddDate(0, 0, -5), 2},
+		{"Hitze, Lücke beendet die Serie", func(d time.Time) int { return countHeatStreak(db, config, d) }, day.AddDate(0, 0, -3), 1},
+		{"Hitze, Tag ohne Daten", func(d time.Time) int { return countHeatStreak(db, config, d) }, day.AddDate(0, 0, 1), 0},
+	}
+	for _, tt := range tests {
+		if got := tt.f(tt.day); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRainStreaks(t *testing.T) {
+	loc := time.UTC
+	today := time.Date(2024, 5, 10, 4, 0, 0, 0, loc)
+	midnight := time.Date(2024, 5, 10, 0, 0, 0, 0, loc)
+	// Regenmenge (cm) je Tag vor today; der 6. Tag davor fehlt
+	days := map[int]float64{-1: 0.2, -2: 0.1, -3: 0, -4: 0, -5: 0.3, -7: 0}
+	samples := make(map[time.Time]map[string]float64)
+	for offset, rain := range days {
+		samples[midnight.AddDate(0, 0, offset).Add(12*time.Hour)] = map[string]float64{"rain": rain}
+	}
+	db := testArchive(t, samples)
+	config := DefaultConfig()
+	config.DailySource = dailySourceArchive
+	config.DrySpellMaxDays = 0
+
+	tests := []struct {
+		name string
+		f    func(now time.Time) int
+		now  time.Time
+		want int
+	}{
+		{"Regentage bis gestern", func(now time.Time) int { return countConsecutiveRainDays(db, loc, config, now) }, today, 2},
+		{"Regentage, gestern trocken", func(now time.Time) int { return countConsecutiveRainDays(db, loc, config, now) }, today.AddDate(0, 0, -2), 0},
+		{"trockene Tage, gestern Regen", func(now time.Time) int { return countDaysSinceRain(db, loc, config, now) }, today, 0},
+		{"trockene Tage bis zum Regen", func(now time.Time) int { return countDaysSinceRain(db, loc, config, now) }, today.AddDate(0, 0, -2), 2},
+		{"Regentage, Lücke beendet die Serie", func(now time.Time) int { return countConsecutiveRainDays(db, loc, config, now) }, today.AddDate(0, 0, -4), 1},
+	}
+	for _, tt := range tests {
+		if got := tt.f(tt.now); got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
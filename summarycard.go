@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+)
+
+// Größe der Übersichtskarte wie für Vorschaubilder (Open Graph) üblich, unabhängig von chart_style
+const (
+	summaryCardWidth  = 1200
+	summaryCardHeight = 630
+)
+
+// summaryCardTile ist ein Wert der Übersichtskarte mit Beschriftung
+type summaryCardTile struct {
+	label, value string
+	col          color.RGBA
+}
+
+// summaryCardTiles liefert Höchst- und Tiefsttemperatur, Niederschlag und Sonnenschein des Tages; der Sonnenschein
+// entfällt ohne Strahlungswerte und Sonnenstunden
+func summaryCardTiles(config Config, theme chartTheme, s dayStats) []summaryCardTile {
+	temp, rain := unitOf(config, quantityTemperature), unitOf(config, quantityRain)
+	tiles := []summaryCardTile{
+		{tr(config, "Max"), temp.format(s.tMax, 1), theme.high},
+		{tr(config, "Min"), temp.format(s.tMin, 1), theme.low},
+		{tr(config, "Regen"), rain.format(s.rainSum, 1), theme.bar},
+	}
+	sun := unitOf(config, quantitySunshine)
+	if !config.SunshineLegacyHours && !math.IsNaN(s.sunMinutes) {
+		tiles = append(tiles, summaryCardTile{tr(config, "Sonne"), sun.format(s.sunMinutes/60, 1), theme.solar})
+	} else if s.sunHours > 0 {
+		tiles = append(tiles, summaryCardTile{tr(config, "Sonne"), sun.format(float64(s.sunHours), 0), theme.solar})
+	}
+	return tiles
+}
+
+// renderSummaryCard zeichnet die Übersichtskarte des Tages: Stationsname und Datum, darunter die Tageswerte in zwei
+// Spalten in großen Zahlen. Die Schrift der Werte wird so groß, wie der Platz erlaubt.
+func renderSummaryCard(config Config, theme chartTheme, day time.Time, s dayStats) (*chartDrawing, error) {
+	if math.IsNaN(s.tMax) || math.IsNaN(s.tMin) {
+		return nil, fmt.Errorf("keine Temperaturwerte")
+	}
+	theme.width, theme.height = summaryCardWidth, summaryCardHeight
+	d := newChartDrawing(theme, summaryCardHeight)
+	const margin = 60
+
+	title := strings.TrimSpace(config.StationName)
+	if title == "" {
+		title = tr(config, "Wetter")
+	}
+	// Stationsname links, Datum rechts auf der Grundlinie des Namens
+	date := formatDate(config, day)
+	titleScale := 7
+	for titleScale > 3 && textWidth(title, titleScale) > summaryCardWidth-3*margin-textWidth(date, 4) {
+		titleScale--
+	}
+	d.text(margin, margin, title, theme.text, titleScale)
+	d.text(summaryCardWidth-margin-textWidth(date, 4), margin+(titleScale-4)*glyphHeight, date, theme.axis, 4)
+
+	// Werte in zwei Spalten, Beschriftung über einem Farbbalken
+	tiles := summaryCardTiles(config, theme, s)
+	const labelScale, bar = 3, 8
+	top := margin + glyphHeight*titleScale + margin/2
+	columns := 2
+	rows := (len(tiles) + columns - 1) / columns
+	cellWidth, cellHeight := (summaryCardWidth-2*margin)/columns, (summaryCardHeight-margin-top)/rows
+	valueTop := bar + (glyphHeight+4)*labelScale + 2*labelScale
+	valueScale := (cellHeight - valueTop - margin/3) / glyphHeight
+	for _, t := range tiles {
+		for valueScale > 2 && textWidth(t.value, valueScale) > cellWidth-margin {
+			valueScale--
+		}
+	}
+	// verbleibenden Platz gleichmäßig über und unter den Werten verteilen
+	used := valueTop + glyphHeight*valueScale
+	gap := (summaryCardHeight - margin - top - rows*used) / (rows + 1)
+	top, cellHeight = top+gap, used+gap
+	for i, t := range tiles {
+		x, y := margin+(i%columns)*cellWidth, top+(i/columns)*cellHeight
+		d.rect(image.Rect(x, y, x+cellWidth-margin/2, y+bar), t.col)
+		d.text(x, y+bar+2*labelScale, t.label, theme.text, labelScale)
+		d.text(x, y+valueTop, t.value, t.col, valueScale)
+	}
+	return d, nil
+}
+
+// altTextSummaryCard beschreibt die Übersichtskarte, z.B. "Overath, 08.10.2024: Max 14.2 °C, Min 6.1 °C, Regen
+// 3.4 mm, Sonne 2.5 h"
+func altTextSummaryCard(config Config, day time.Time, s dayStats) string {
+	var parts []string
+	for _, t := range summaryCardTiles(config, chartThemeFor(config), s) {
+		parts = append(parts, t.label+" "+t.value)
+	}
+	title := formatDate(config, day)
+	if name := strings.TrimSpace(config.StationName); name != "" {
+		title = name + ", " + title
+	}
+	return title + ": " + strings.Join(parts, ", ")
+}
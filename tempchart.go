@@ -0,0 +1,106 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"image/color"
+	"math"
+	"time"
+)
+
+// chartGap ist die Lücke zwischen zwei Archivdatensätzen, ab der eine Diagrammlinie unterbrochen wird
+const chartGap = 30 * 60
+
+// getTemperatureSeries liefert die Außentemperatur aller Archivdatensätze des Zeitraums (x = Unix-Zeit, y = °C)
+func getTemperatureSeries(db *sql.DB, start, end int64) ([]chartPoint, error) {
+	return getArchiveSeries(db, "outTemp", start, end)
+}
+
+// getArchiveSeries liefert die Werte der archive-Spalte column (eine feste Spalte, keine Benutzereingabe) aller
+// Archivdatensätze des Zeitraums (x = Unix-Zeit). Bei Lücken ab chartGap wird ein NaN-Punkt eingefügt, damit
+// die Linie dort unterbrochen ist.
+func getArchiveSeries(db *sql.DB, column string, start, end int64) ([]chartPoint, error) {
+	rows, err := db.Query(`SELECT dateTime, `+column+` FROM archive WHERE dateTime >= ? AND dateTime < ? AND `+column+` IS NOT NULL ORDER BY dateTime;`, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var series []chartPoint
+	for rows.Next() {
+		var ts int64
+		var value float64
+		if err := rows.Scan(&ts, &value); err != nil {
+			return nil, err
+		}
+		if n := len(series); n > 0 && float64(ts)-series[n-1].x >= chartGap {
+			series = append(series, chartPoint{x: series[n-1].x, y: math.NaN()})
+		}
+		series = append(series, chartPoint{x: float64(ts), y: value})
+	}
+	return series, rows.Err()
+}
+
+// renderTemperatureChart zeichnet den Temperaturverlauf des Tages day als PNG mit markiertem Höchst- und Tiefstwert,
+// Rekorde und Serien aus dem Post (annotations) sind daran beschriftet. Die Zeitachse zeigt die Stunden 0–24 der
+// Ortszeit von day.
+func renderTemperatureChart(config Config, theme chartTheme, day time.Time, series []chartPoint, annotations []chartAnnotation) (*chartDrawing, error) {
+	c := newChartCanvas(theme)
+	if err := drawTemperaturePanel(c, config, day, series, tr(config, "Temperatur am %s", formatDate(config, day)), annotations); err != nil {
+		return nil, err
+	}
+	return c.d, nil
+}
+
+// drawTemperaturePanel zeichnet den Temperaturverlauf mit Höchst- und Tiefstwert auf die Zeichenfläche c. Ist der
+// Höchst- oder Tiefstwert ein Rekord oder Teil einer Serie, wird er umrandet und beschriftet.
+func drawTemperaturePanel(c *chartCanvas, config Config, day time.Time, series []chartPoint, title string, annotations []chartAnnotation) error {
+	unit := unitOf(config, quantityTemperature)
+	var points []chartPoint
+	hi, lo := -1, -1
+	for _, p := range series {
+		if !math.IsNaN(p.y) {
+			if hi < 0 || p.y > series[hi].y {
+				hi = len(points)
+			}
+			if lo < 0 || p.y < series[lo].y {
+				lo = len(points)
+			}
+		}
+		points = append(points, chartPoint{x: p.x, y: unit.convert(p.y)})
+	}
+	if hi < 0 {
+		return fmt.Errorf("keine Temperaturwerte")
+	}
+
+	yMin, yMax, step := niceRange(points[lo].y, points[hi].y, 6)
+	c.setRange(float64(day.Unix()), float64(day.AddDate(0, 0, 1).Unix()), yMin, yMax)
+	c.title(title, unit.symbol)
+	c.yAxis(step, axisLabel(step))
+	c.xAxis(hourTicks(day))
+	c.polyline(points, c.theme.line)
+	extremes := []struct {
+		i     int
+		value string
+		above bool
+		col   color.RGBA
+	}{{hi, annotateTMax, true, c.theme.high}, {lo, annotateTMin, false, c.theme.low}}
+	for _, e := range extremes {
+		label := unit.number(series[e.i].y, 1)
+		if note := annotationLabel(annotations, e.value); note != "" {
+			label += " " + note
+			c.highlight(points[e.i].x, points[e.i].y, e.col)
+		}
+		c.marker(points[e.i].x, points[e.i].y, label, e.above, e.col)
+	}
+	return nil
+}
+
+// hourTicks liefert die Markierungen der Zeitachse eines Tages alle drei Stunden (Ortszeit von day)
+func hourTicks(day time.Time) []chartTick {
+	var ticks []chartTick
+	for h := 0; h <= 24; h += 3 {
+		t := time.Date(day.Year(), day.Month(), day.Day(), h, 0, 0, 0, day.Location())
+		ticks = append(ticks, chartTick{x: float64(t.Unix()), label: fmt.Sprint(h)})
+	}
+	return ticks
+}
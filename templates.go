@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"log"
+	"math"
+	"text/template"
+	"time"
+)
+
+// templateStats sind die Messgrößen eines Tages, wie sie in Post-Templates verfügbar sind.
+// Fehlende Werte sind NaN; mit {{if ok .WindRun}} lässt sich das im Template prüfen.
+type templateStats struct {
+	TMax, TMin                 float64
+	RainSum                    float64
+	SunHours                   int
+	PressureMean               float64
+	PressureChange             float64
+	PressureTrend              float64
+	DewMin, DewMax             float64
+	HeatIndexMax               float64
+	WindChillMin               float64
+	WindRun                    float64 // km
+	GustMax                    float64 // km/h
+	WindMean                   float64 // km/h
+	NightMin                   float64 // Tiefstwert der Nacht zum Tag
+	SnowFall                   float64 // cm
+	SnowDepth                  float64 // cm
+	ET                         float64 // mm
+	LightningStrikes           float64
+	LightningDistance          float64 // km
+	SoilTempMin, SoilTempMax   float64
+	SoilMoistMin, SoilMoistMax float64 // cb
+	PM25Mean, PM25Max          float64 // µg/m³
+	PM10Mean, PM10Max          float64 // µg/m³
+	RainRateMax                float64 // mm/h
+	HeaviestHour               int     // Beginn der regenreichsten Stunde, -1 ohne Regen
+	HeaviestHourRain           float64 // mm
+	SolarEnergy                float64 // kWh/m²
+	SunMinutes                 float64 // Sonnenscheindauer
+	RainHours                  int     // Stunden mit Regen
+	RainMinutes                float64 // Regendauer
+	UVMax                      float64
+	TMaxTime, TMinTime         string  // Uhrzeit "16:10", leer wenn unbekannt
+	InTempMin, InTempMax       float64 // °C innen
+	InHumMin, InHumMax         float64 // % innen
+	CloudBaseMin, CloudBaseMax float64 // m, geschätzt
+	GustTime                   string  // Uhrzeit der Spitzenböe, leer wenn unbekannt
+	LeafWetHours               float64 // h mit nassen Blättern
+}
+
+// templateData ist der Datenkontext für Post-Templates
+type templateData struct {
+	Date      string // TT.MM.JJJJ
+	Station   string // Name der Station (station_name)
+	Location  string // Lage der Station (station_location)
+	DetailURL string // Link auf die Detailseite des Tages (detail_urls.daily)
+	templateStats
+	Previous   templateStats          // Vortag zum Vergleich
+	Text       string                 // der vom Programm erzeugte Standardtext
+	Title      string                 // der Titel (im title_template der vom Programm erzeugte Standardtitel)
+	Emoji      string                 // Emojis des Standardtitels, z.B. "🌧️ ❄️", leer ohne besondere Wetterlage
+	DaySummary string                 // Satz, der den Tag charakterisiert (day_summary_rules)
+	External   map[string]interface{} // externe JSON-Daten je Quelle (external_data)
+	Markdown   bool                   // true, wenn die Plattform Markdown darstellt (text_formats)
+}
+
+// Bold hebt einen Text auf Plattformen mit Markdown hervor, z.B. {{.Bold "Niederschlag"}}
+func (d templateData) Bold(s string) string {
+	return markup{markdown: d.Markdown}.bold(s)
+}
+
+func newTemplateStats(s dayStats, loc *time.Location) templateStats {
+	return templateStats{
+		TMax:              s.tMax,
+		TMin:              s.tMin,
+		RainSum:           s.rainSum,
+		SunHours:          s.sunHours,
+		PressureMean:      s.pressure.mean,
+		PressureChange:    s.pressure.change,
+		PressureTrend:     s.pressure.trend,
+		DewMin:            s.dewMin,
+		DewMax:            s.dewMax,
+		HeatIndexMax:      s.heatIndexMax,
+		WindChillMin:      s.windChillMin,
+		WindRun:           s.windRun,
+		GustMax:           s.gustMax,
+		WindMean:          s.windMean,
+		NightMin:          s.nightMin,
+		SnowFall:          s.snowFall,
+		SnowDepth:         s.snowDepth,
+		ET:                s.et,
+		LightningStrikes:  s.lightningStrikes,
+		LightningDistance: s.lightningDistance,
+		SoilTempMin:       s.soilTempMin,
+		SoilTempMax:       s.soilTempMax,
+		SoilMoistMin:      s.soilMoistMin,
+		SoilMoistMax:      s.soilMoistMax,
+		PM25Mean:          s.pm25Mean,
+		PM25Max:           s.pm25Max,
+		PM10Mean:          s.pm10Mean,
+		PM10Max:           s.pm10Max,
+		RainRateMax:       s.rainRateMax,
+		HeaviestHour:      s.heaviestHour,
+		HeaviestHourRain:  s.heaviestHourRain,
+		SolarEnergy:       s.solarEnergy,
+		SunMinutes:        s.sunMinutes,
+		RainHours:         s.rainHours,
+		RainMinutes:       s.rainMinutes,
+		UVMax:             s.uvMax,
+		TMaxTime:          formatClock(s.tMaxTime, loc),
+		TMinTime:          formatClock(s.tMinTime, loc),
+		InTempMin:         s.inTempMin,
+		InTempMax:         s.inTempMax,
+		InHumMin:          s.inHumMin,
+		InHumMax:          s.inHumMax,
+		CloudBaseMin:      s.cloudBaseMin,
+		CloudBaseMax:      s.cloudBaseMax,
+		GustTime:          formatClock(s.gustTime, loc),
+		LeafWetHours:      s.leafWetHours,
+	}
+}
+
+func newTemplateData(config Config, day time.Time, statsY, statsV dayStats, text string, external map[string]interface{}) templateData {
+	return templateData{
+		Date:          day.Format("02.01.2006"),
+		Station:       config.StationName,
+		Location:      config.StationLocation,
+		DetailURL:     config.DetailURLs[detailDaily],
+		templateStats: newTemplateStats(statsY, day.Location()),
+		Previous:      newTemplateStats(statsV, day.Location()),
+		Text:          text,
+		External:      external,
+	}
+}
+
+// templateFuncs stehen in allen Post-Templates zur Verfügung
+var templateFuncs = template.FuncMap{
+	"ok": func(v float64) bool { return !math.IsNaN(v) },
+}
+
+// PublisherTemplate sind Titel- und Text-Template einer Plattform (publisher_templates); leere Felder
+// fallen auf den gemeinsamen Titel bzw. den Standardtext zurück
+type PublisherTemplate struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// renderTemplate füllt ein Post-Template. Bei Fehlern im Template wird fallback verwendet,
+// damit ein fehlerhaftes Template nicht den täglichen Post verhindert.
+func renderTemplate(name, text string, data interface{}, fallback string) string {
+	if text == "" {
+		return fallback
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		log.Printf("Fehler im Template %s: %v – verwende Standardtext", name, err)
+		return fallback
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		log.Printf("Fehler beim Ausführen des Templates %s: %v – verwende Standardtext", name, err)
+		return fallback
+	}
+	return buf.String()
+}
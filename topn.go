@@ -0,0 +1,223 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// topListMetric ist eine Messgröße, nach der Tage für Bestenlisten sortiert werden
+type topListMetric struct {
+	adjective string      // z.B. "wärmsten"
+	quantity  string      // Messgröße für die Anzeigeeinheit
+	series    dailyValues // Tageswerte nach daily_source und rain_day_start_hour
+	higher    bool        // true: größte Werte zuerst
+}
+
+// topListMetrics sind die Messgrößen für -top und top_lists
+var topListMetrics = map[string]topListMetric{
+	"warm": {"wärmsten", quantityTemperature, dailyMaxima, true},
+	"cold": {"kältesten", quantityTemperature, dailyMinima, false},
+	"rain": {"regenreichsten", quantityRain, dailyRainSums, true},
+}
+
+// topListSpec beschreibt eine Bestenliste "Messgröße:Zeitraum[:Anzahl]", z.B. "warm:year:5" oder "rain:all:10".
+// Zeiträume: month (laufender Monat), year (laufendes Jahr), all (seit Aufzeichnungsbeginn).
+type topListSpec struct {
+	metric string
+	period string
+	n      int
+}
+
+// parseTopListSpec wertet eine Angabe "Messgröße:Zeitraum[:Anzahl]" aus (Standard: 5 Tage)
+func parseTopListSpec(s string) (topListSpec, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return topListSpec{}, fmt.Errorf("ungültige Bestenliste %q (erwartet Messgröße:Zeitraum[:Anzahl])", s)
+	}
+	spec := topListSpec{metric: parts[0], period: parts[1], n: 5}
+	if _, ok := topListMetrics[spec.metric]; !ok {
+		return spec, fmt.Errorf("unbekannte Messgröße %q (warm, cold, rain)", spec.metric)
+	}
+	switch spec.period {
+	case "month", "year", "all":
+	default:
+		return spec, fmt.Errorf("unbekannter Zeitraum %q (month, year, all)", spec.period)
+	}
+	if len(parts) == 3 {
+		n, err := strconv.Atoi(parts[2])
+		if err != nil || n < 1 {
+			return spec, fmt.Errorf("ungültige Anzahl %q", parts[2])
+		}
+		spec.n = n
+	}
+	return spec, nil
+}
+
+// topListEntry ist ein Platz einer Bestenliste, wie er im Template top_list_template verfügbar ist
+type topListEntry struct {
+	Rank  int
+	Date  string // TT.MM.JJJJ, im Englischen "Oct 17, 2026"
+	Value float64
+	Text  string // formatierter Wert mit Einheit
+}
+
+// topListData ist der Datenkontext für top_list_template
+type topListData struct {
+	Title     string
+	Entries   []topListEntry
+	Text      string // der vom Programm erzeugte Standardtext
+	DetailURL string // Link auf die Detailseite (detail_urls.monthly)
+}
+
+// getTopList liefert die n Tage mit den extremsten Werten von m im Zeitraum [from, to] (inklusive), bei gleichen
+// Werten den früheren Tag zuerst. Die Tageswerte werden blockweise gelesen, im Speicher bleiben nur die besten n.
+func getTopList(db *sql.DB, config Config, m topListMetric, from, to time.Time, n int) ([]topListEntry, error) {
+	better := func(a, b float64) bool {
+		if m.higher {
+			return a > b
+		}
+		return a < b
+	}
+	var top []dailyValue
+	err := eachDailyValue(db, config, m.series, from, to, func(v dailyValue) {
+		// Tage kommen in Tagesreihenfolge, ein gleicher Wert reiht sich hinter den früheren Tag ein
+		i := sort.Search(len(top), func(i int) bool { return better(v.value, top[i].value) })
+		if i >= n {
+			return
+		}
+		top = append(top, dailyValue{})
+		copy(top[i+1:], top[i:])
+		top[i] = v
+		if len(top) > n {
+			top = top[:n]
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]topListEntry, len(top))
+	for i, v := range top {
+		entries[i] = topListEntry{
+			Rank:  i + 1,
+			Date:  formatDate(config, v.day),
+			Value: v.value,
+			Text:  unitOf(config, m.quantity).format(v.value, 1),
+		}
+	}
+	return entries, nil
+}
+
+// composeTopList erzeugt Titel und Text einer Bestenliste bis einschließlich day,
+// z.B. "🏅 Die 5 wärmsten Tage dieses Jahres in Overath" mit einer Zeile je Platz, dazu die Markdown-Fassung
+// als Tabelle (leer bei eigenem top_list_template)
+func composeTopList(db *sql.DB, config Config, spec topListSpec, day time.Time) (string, string, string, error) {
+	m := topListMetrics[spec.metric]
+	loc := day.Location()
+	var from time.Time
+	var period string
+	switch spec.period {
+	case "month":
+		from = time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, loc)
+		period = tr(config, "im %s %d", monthName(config, day.Month()), day.Year())
+	case "year":
+		from = time.Date(day.Year(), 1, 1, 0, 0, 0, 0, loc)
+		period = tr(config, "des Jahres %d", day.Year())
+	default:
+		first, ok := firstArchiveDay(db, loc)
+		if !ok {
+			return "", "", "", fmt.Errorf("keine Daten in archive")
+		}
+		from = first
+		period = tr(config, "seit Aufzeichnungsbeginn %d", from.Year())
+	}
+
+	entries, err := getTopList(db, config, m, from, day, spec.n)
+	if err != nil {
+		return "", "", "", err
+	}
+	if len(entries) == 0 {
+		return "", "", "", fmt.Errorf("keine Daten vom %s bis %s", from.Format("02.01.2006"), day.Format("02.01.2006"))
+	}
+	title := tr(config, "🏅 Die %d %s Tage %s in %s", len(entries), tr(config, m.adjective), period, config.StationName)
+	b := textBlock{columns: []string{tr(config, "Platz"), tr(config, "Datum"), tr(config, "Wert")}}
+	for _, e := range entries {
+		b.lines = append(b.lines, fmt.Sprintf("%d. %s: %s", e.Rank, e.Date, e.Text))
+		b.rows = append(b.rows, []string{strconv.Itoa(e.Rank), e.Date, e.Text})
+	}
+	text := strings.Join(b.lines, "\n")
+	details := detailLine(config, detailMonthly)
+	if details != "" {
+		text += "\n" + details
+	}
+	if config.TopListTemplate != "" {
+		data := topListData{Title: title, Entries: entries, Text: text, DetailURL: config.DetailURLs[detailMonthly]}
+		return title, renderTemplate("top_list", config.TopListTemplate, data, text), "", nil
+	}
+	markdown := markup{markdown: true}.block(b)
+	if details != "" {
+		markdown += "\n\n" + details
+	}
+	return title, text, markdown, nil
+}
+
+// runTopList gibt eine Bestenliste bis gestern aus (-top) oder veröffentlicht sie mit -post
+func runTopList(dbPath string, config Config, specText string, post, testMode bool) error {
+	spec, err := parseTopListSpec(specText)
+	if err != nil {
+		return err
+	}
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		return fmt.Errorf("timezone: %v", err)
+	}
+	db, err := sql.Open("sqlite3", dbPath)
+	if err != nil {
+		return fmt.Errorf("open DB: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Now().In(loc)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, -1)
+	title, text, markdown, err := composeTopList(db, config, spec, day)
+	if err != nil {
+		return err
+	}
+	if !post {
+		fmt.Printf("%s\n%s\n", title, text)
+		return nil
+	}
+	store, err := openStore(config.StorePath)
+	if err != nil {
+		log.Printf("Warnung: %v – Veröffentlichungsstatus wird nicht gespeichert", err)
+	} else {
+		defer store.Close()
+	}
+	publishSummary(store, config, "top-"+specText+"-"+day.Format("2006-01-02"), postTypeTopList, "Bestenliste", title, text, markdown, nil, testMode, false)
+	return nil
+}
+
+// publishTopLists veröffentlicht am Monatsersten die Bestenlisten aus top_lists, berechnet bis zum Vortag
+func publishTopLists(db, store *sql.DB, config Config, now time.Time, testMode, loopMode bool) {
+	if now.Day() != 1 {
+		return
+	}
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+	for _, specText := range config.TopLists {
+		spec, err := parseTopListSpec(specText)
+		if err != nil {
+			log.Printf("Warnung: top_lists: %v", err)
+			continue
+		}
+		title, text, markdown, err := composeTopList(db, config, spec, day)
+		if err != nil {
+			log.Printf("Warnung: Bestenliste %s nicht verfügbar: %v", specText, err)
+			continue
+		}
+		publishSummary(store, config, "top-"+specText+"-"+day.Format("2006-01"), postTypeTopList, "Bestenliste", title, text, markdown, nil, testMode, loopMode)
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetTopList(t *testing.T) {
+	loc := time.UTC
+	day := func(d int) time.Time { return time.Date(2024, 7, d, 0, 0, 0, 0, loc) }
+	db := testArchive(t, nil)
+	if _, err := db.Exec(`CREATE TABLE archive_day_outTemp (dateTime INTEGER PRIMARY KEY, min REAL, max REAL);`); err != nil {
+		t.Fatal(err)
+	}
+	for d, v := range map[int][2]float64{1: {12, 28}, 2: {9, 33}, 3: {14, 31}, 4: {8, 33}, 5: {10, 25}, 6: {11, 30}} {
+		if _, err := db.Exec(`INSERT INTO archive_day_outTemp VALUES (?, ?, ?);`, day(d).Unix(), v[0], v[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name      string
+		metric    string
+		n         int
+		chunkRows int
+		want      []float64
+		wantDays  []int
+	}{
+		{"wärmste, gleiche Werte in Tagesreihenfolge", "warm", 3, 0, []float64{33, 33, 31}, []int{2, 4, 3}},
+		{"wärmste, ein Tag je Block", "warm", 3, 1, []float64{33, 33, 31}, []int{2, 4, 3}},
+		{"kälteste", "cold", 2, 1, []float64{8, 9}, []int{4, 2}},
+		{"mehr Plätze als Tage", "cold", 10, 0, []float64{8, 9, 10, 11, 12, 14}, []int{4, 2, 5, 6, 1, 3}},
+	}
+	for _, tt := range tests {
+		config := DefaultConfig()
+		config.ScanChunkRows = tt.chunkRows
+		entries, err := getTopList(db, config, topListMetrics[tt.metric], day(1), day(6), tt.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != len(tt.want) {
+			t.Fatalf("%s: got %d Plätze, want %d", tt.name, len(entries), len(tt.want))
+		}
+		for i, e := range entries {
+			if e.Rank != i+1 || e.Value != tt.want[i] || e.Date != formatDate(config, day(tt.wantDays[i])) {
+				t.Errorf("%s, Platz %d: got %+v, want %v am %d.", tt.name, i+1, e, tt.want[i], tt.wantDays[i])
+			}
+		}
+	}
+}
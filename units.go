@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Einheitensysteme der Posts (units); die Datenbank bleibt metrisch (weewx METRIC)
+const (
+	unitsMetric   = "metric"
+	unitsImperial = "imperial"
+)
+
+// Messgrößen, deren Anzeigeeinheit sich einstellen lässt (unit_overrides)
+const (
+	quantityTemperature = "temperature"
+	quantityRain        = "rain"
+	quantityWind        = "wind"
+	quantitySnow        = "snow"
+	quantityDistance    = "distance"
+	quantityHeight      = "height"
+	quantitySunshine    = "sunshine" // immer Stunden
+)
+
+// displayUnit ist eine Anzeigeeinheit: Anzeigewert = metrischer Wert · factor + offset
+type displayUnit struct {
+	symbol        string
+	factor        float64
+	offset        float64
+	extraDecimals int // zusätzliche Nachkommastellen gegenüber der metrischen Angabe, z.B. für Zoll
+}
+
+// displayUnits enthält je Messgröße die wählbaren Einheiten, Schlüssel ist das Kürzel in unit_overrides
+var displayUnits = map[string]map[string]displayUnit{
+	quantityTemperature: {
+		"C": {symbol: "°C", factor: 1},
+		"F": {symbol: "°F", factor: 1.8, offset: 32},
+	},
+	quantityRain: {
+		"mm": {symbol: "mm", factor: 1},
+		"in": {symbol: "in", factor: 1 / 25.4, extraDecimals: 1},
+	},
+	quantityWind: {
+		"km/h": {symbol: "km/h", factor: 1},
+		"mph":  {symbol: "mph", factor: 1 / 1.609344},
+		"m/s":  {symbol: "m/s", factor: 1 / 3.6, extraDecimals: 1},
+		"kn":   {symbol: "kn", factor: 1 / 1.852},
+	},
+	quantitySnow: {
+		"cm": {symbol: "cm", factor: 1},
+		"in": {symbol: "in", factor: 1 / 2.54, extraDecimals: 1},
+	},
+	quantityDistance: {
+		"km": {symbol: "km", factor: 1},
+		"mi": {symbol: "mi", factor: 1 / 1.609344},
+	},
+	quantityHeight: {
+		"m":  {symbol: "m", factor: 1},
+		"ft": {symbol: "ft", factor: 1 / 0.3048},
+	},
+	quantitySunshine: {
+		"h": {symbol: "h", factor: 1},
+	},
+}
+
+// unitSystems legt je Einheitensystem die Einheit jeder Messgröße fest
+var unitSystems = map[string]map[string]string{
+	unitsMetric: {
+		quantityTemperature: "C", quantityRain: "mm", quantityWind: "km/h",
+		quantitySnow: "cm", quantityDistance: "km", quantityHeight: "m", quantitySunshine: "h",
+	},
+	unitsImperial: {
+		quantityTemperature: "F", quantityRain: "in", quantityWind: "mph",
+		quantitySnow: "in", quantityDistance: "mi", quantityHeight: "ft", quantitySunshine: "h",
+	},
+}
+
+// validateUnits prüft units und unit_overrides
+func validateUnits(config Config) error {
+	if _, ok := unitSystems[config.Units]; !ok {
+		return fmt.Errorf("unbekanntes Einheitensystem %q (units: metric, imperial)", config.Units)
+	}
+	for quantity, unit := range config.UnitOverrides {
+		units, ok := displayUnits[quantity]
+		if !ok {
+			return fmt.Errorf("unbekannte Messgröße %q in unit_overrides", quantity)
+		}
+		if _, ok := units[unit]; !ok {
+			var names []string
+			for name := range units {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unbekannte Einheit %q für %s (möglich: %s)", unit, quantity, strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// unitOf liefert die Anzeigeeinheit einer Messgröße: unit_overrides vor dem Einheitensystem, sonst metrisch
+func unitOf(config Config, quantity string) displayUnit {
+	name, ok := config.UnitOverrides[quantity]
+	if !ok {
+		name, ok = unitSystems[config.Units][quantity]
+	}
+	if !ok {
+		name = unitSystems[unitsMetric][quantity]
+	}
+	return displayUnits[quantity][name]
+}
+
+// convert rechnet einen metrischen Wert in die Anzeigeeinheit um
+func (u displayUnit) convert(v float64) float64 {
+	return v*u.factor + u.offset
+}
+
+// number formatiert einen metrischen Wert ohne Einheit, decimals gilt für die metrische Angabe
+func (u displayUnit) number(v float64, decimals int) string {
+	return fmt.Sprintf("%.*f", decimals+u.extraDecimals, u.convert(v))
+}
+
+// format formatiert einen metrischen Wert mit Einheit, z.B. "12.3 °C" bzw. "54.1 °F"
+func (u displayUnit) format(v float64, decimals int) string {
+	return u.number(v, decimals) + " " + u.symbol
+}
+
+// deltaNumber formatiert eine Differenz (ohne Nullpunktverschiebung) ohne Einheit, z.B. Gradtage
+func (u displayUnit) deltaNumber(d float64, decimals int) string {
+	return fmt.Sprintf("%.*f", decimals+u.extraDecimals, d*u.factor)
+}
+
+// delta formatiert eine Differenz mit Einheit, mit signed immer mit Vorzeichen, z.B. "+1.2 °C"
+func (u displayUnit) delta(d float64, decimals int, signed bool) string {
+	if signed {
+		return fmt.Sprintf("%+.*f %s", decimals+u.extraDecimals, d*u.factor, u.symbol)
+	}
+	return u.deltaNumber(d, decimals) + " " + u.symbol
+}
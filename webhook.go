@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// webhookSchemaVersion ist die aktuelle Version des Webhook-Payloads.
+// Neue Messgrößen in "stats" sind abwärtskompatibel und erhöhen die Version nicht;
+// Umbenennungen oder entfernte Felder erfordern eine neue Version.
+const webhookSchemaVersion = 2
+
+// webhookV1Stats sind die Messgrößen von Schema-Version 1, die im Kompatibilitätsmodus unverändert bleiben
+var webhookV1Stats = []string{"t_max", "t_min", "rain_sum", "sun_hours"}
+
+// webhookPayload ist der JSON-Inhalt, der an den Webhook geschickt wird
+type webhookPayload struct {
+	SchemaVersion int                    `json:"schema_version"`
+	Date          string                 `json:"date"` // YYYY-MM-DD
+	Title         string                 `json:"title"`
+	Text          string                 `json:"text"`
+	Stats         map[string]interface{} `json:"stats"` // Spaltennamen wie in daily_stats, fehlende Werte null
+}
+
+// buildWebhookPayload erzeugt den Payload in der gewünschten Schema-Version (0 = aktuelle Version).
+// Innenraumwerte (in_*) sind nur enthalten, wenn der Innenraum-Abschnitt für den Webhook freigegeben ist.
+func buildWebhookPayload(version int, day time.Time, title, text string, s dayStats, indoor bool) (webhookPayload, error) {
+	if version == 0 {
+		version = webhookSchemaVersion
+	}
+	stats := make(map[string]interface{})
+	switch version {
+	case 1:
+		for _, c := range dailyStatsColumns {
+			for _, name := range webhookV1Stats {
+				if c.name == name {
+					stats[c.name] = c.value(s)
+				}
+			}
+		}
+	case 2:
+		stats = dayStatsMap(s, indoor)
+	default:
+		return webhookPayload{}, fmt.Errorf("unbekannte Webhook-Schema-Version %d (unterstützt: 1–%d)", version, webhookSchemaVersion)
+	}
+	return webhookPayload{
+		SchemaVersion: version,
+		Date:          day.Format("2006-01-02"),
+		Title:         title,
+		Text:          text,
+		Stats:         stats,
+	}, nil
+}
+
+// webhookPost schickt den Payload per HTTP POST an die konfigurierte URL
+func webhookPost(url string, payload webhookPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	throttle.wait(url)
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("Webhook HTTP %d - Antwort: %s", resp.StatusCode, string(body))
+	}
+	log.Printf("Webhook erfolgreich aufgerufen (Schema-Version %d).", payload.SchemaVersion)
+	return nil
+}
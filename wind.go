@@ -0,0 +1,61 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+)
+
+// getWindRun berechnet den Windweg des Zeitraums in km als Summe aus mittlerer Windgeschwindigkeit
+// (km/h) und Dauer jedes Archivintervalls. NaN, wenn keine Windwerte vorliegen.
+func getWindRun(db *sql.DB, start, end int64) (float64, error) {
+	const q = `SELECT SUM(windSpeed * interval / 60.0), COUNT(windSpeed) FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	var run sql.NullFloat64
+	var count int
+	if err := db.QueryRow(q, start, end).Scan(&run, &count); err != nil {
+		return math.NaN(), err
+	}
+	if !run.Valid || count == 0 {
+		return math.NaN(), nil
+	}
+	return run.Float64, nil
+}
+
+// getWindExtremes ermittelt die stärkste Böe und die mittlere Windgeschwindigkeit (km/h) aus windGust/windSpeed
+func getWindExtremes(db *sql.DB, start, end int64) (float64, float64, error) {
+	const q = `SELECT MAX(windGust), AVG(windSpeed) FROM archive WHERE dateTime >= ? AND dateTime < ?;`
+	var gust, mean sql.NullFloat64
+	if err := db.QueryRow(q, start, end).Scan(&gust, &mean); err != nil {
+		return math.NaN(), math.NaN(), err
+	}
+	gustMax, windMean := math.NaN(), math.NaN()
+	if gust.Valid {
+		gustMax = gust.Float64
+	}
+	if mean.Valid {
+		windMean = mean.Float64
+	}
+	return gustMax, windMean, nil
+}
+
+// gustFactor liefert das Verhältnis von Spitzenböe zu mittlerer Windgeschwindigkeit.
+// Bei (nahezu) Windstille ist der Faktor nicht aussagekräftig und NaN.
+func gustFactor(s dayStats, config Config) float64 {
+	if math.IsNaN(s.gustMax) || math.IsNaN(s.windMean) || s.windMean < config.GustFactorMinWind {
+		return math.NaN()
+	}
+	return s.gustMax / s.windMean
+}
+
+// formatGustFactor erzeugt die Zeile zum Böenfaktor mit Hinweis auf ungewöhnlich böige Tage
+func formatGustFactor(s dayStats, config Config) string {
+	factor := gustFactor(s, config)
+	if math.IsNaN(factor) {
+		return ""
+	}
+	unit := unitOf(config, quantityWind)
+	line := tr(config, "Böenfaktor: %.1f (Spitzenböe %s, Mittel %s)", factor, unit.format(s.gustMax, 0), unit.format(s.windMean, 0))
+	if factor >= config.TurbulentGustFactor {
+		line += tr(config, " – ungewöhnlich böig")
+	}
+	return line
+}
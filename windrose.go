@@ -0,0 +1,183 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"image"
+	"math"
+	"time"
+)
+
+// windSectors ist die Zahl der Richtungssektoren der Windrose (je 22,5°)
+const windSectors = 16
+
+// windCalm ist die Windgeschwindigkeit (km/h), unter der ein Messwert als Windstille zählt
+const windCalm = 2.0
+
+// windClassBounds sind die Obergrenzen der Geschwindigkeitsklassen der Windrose in km/h; die letzte Klasse ist offen
+var windClassBounds = []float64{10, 20, 30, 50}
+
+// windDistribution ist die Häufigkeit der Windrichtungen je Sektor (ab Nord im Uhrzeigersinn) und
+// Geschwindigkeitsklasse in Minuten
+type windDistribution struct {
+	sectors [windSectors][]float64
+	calm    float64
+	total   float64
+}
+
+// windClass liefert die Geschwindigkeitsklasse zu speed (km/h)
+func windClass(speed float64) int {
+	for i, bound := range windClassBounds {
+		if speed < bound {
+			return i
+		}
+	}
+	return len(windClassBounds)
+}
+
+// getWindDistribution zählt windDir/windSpeed der Archivdatensätze des Zeitraums nach Sektor und
+// Geschwindigkeitsklasse, gewichtet mit der Länge des Archivintervalls. Ohne Richtung oder unter windCalm
+// gilt ein Datensatz als Windstille.
+func getWindDistribution(db *sql.DB, start, end int64) (windDistribution, error) {
+	var d windDistribution
+	for i := range d.sectors {
+		d.sectors[i] = make([]float64, len(windClassBounds)+1)
+	}
+	rows, err := db.Query(`SELECT windSpeed, windDir, interval FROM archive WHERE dateTime >= ? AND dateTime < ? AND windSpeed IS NOT NULL;`, start, end)
+	if err != nil {
+		return d, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var speed, minutes float64
+		var dir sql.NullFloat64
+		if err := rows.Scan(&speed, &dir, &minutes); err != nil {
+			return d, err
+		}
+		d.total += minutes
+		if !dir.Valid || speed < windCalm {
+			d.calm += minutes
+			continue
+		}
+		sector := int(math.Mod(dir.Float64+360.0/windSectors/2, 360) / (360.0 / windSectors))
+		d.sectors[sector%windSectors][windClass(speed)] += minutes
+	}
+	return d, rows.Err()
+}
+
+// share liefert den Anteil einer Häufigkeit an der Gesamtdauer in Prozent
+func (d windDistribution) share(minutes float64) float64 {
+	return minutes / d.total * 100
+}
+
+// compassNames sind die acht Himmelsrichtungen ab Nord im Uhrzeigersinn
+var compassNames = []string{"Nord", "Nordost", "Ost", "Südost", "Süd", "Südwest", "West", "Nordwest"}
+
+// prevailing liefert die vorherrschende der acht Himmelsrichtungen (Index in compassNames) und ihren Anteil in Prozent.
+// Jede Richtung umfasst ihren Sektor und je die Hälfte der beiden Nachbarsektoren.
+func (d windDistribution) prevailing() (int, float64) {
+	best, bestMinutes := -1, 0.0
+	for i := range compassNames {
+		var minutes float64
+		for offset := -1; offset <= 1; offset++ {
+			weight := 1.0
+			if offset != 0 {
+				weight = 0.5
+			}
+			for _, m := range d.sectors[(2*i+offset+windSectors)%windSectors] {
+				minutes += weight * m
+			}
+		}
+		if minutes > bestMinutes {
+			best, bestMinutes = i, minutes
+		}
+	}
+	return best, d.share(bestMinutes)
+}
+
+// renderWindRose zeichnet die Windrose des Tages day als PNG: je Sektor gestapelt die Anteile der
+// Geschwindigkeitsklassen, rechts die Legende mit dem Anteil der Windstille
+func renderWindRose(config Config, theme chartTheme, day time.Time, d windDistribution) (*chartDrawing, error) {
+	if d.total <= 0 || d.calm >= d.total {
+		return nil, fmt.Errorf("keine Windwerte")
+	}
+	var cumulative [windSectors][]float64
+	maxShare := 0.0
+	for s, classes := range d.sectors {
+		sum := 0.0
+		for _, m := range classes {
+			sum += d.share(m)
+			cumulative[s] = append(cumulative[s], sum)
+		}
+		maxShare = math.Max(maxShare, sum)
+	}
+	_, rMax, step := niceRange(0, maxShare, 4)
+
+	c := newChartCanvas(theme)
+	scale := theme.fontScale
+	c.title(tr(config, "Windrose am %s", formatDate(config, day)), "")
+	radius := c.plot.Dy() / 2
+	cx, cy := c.plot.Min.X+radius+textWidth("W", scale)+4*scale, c.plot.Min.Y+radius
+
+	// Ringe mit Prozentangaben und Achsenkreuz
+	for v := step; v <= rMax+step/1000; v += step {
+		r := int(math.Round(v / rMax * float64(radius)))
+		c.d.circle(cx, cy, r, theme.grid)
+		label := fmt.Sprintf("%.0f%%", v)
+		// Beschriftung auf der Diagonalen nach Nordost, abseits der Himmelsrichtungen
+		lx, ly := cx+int(float64(r)*math.Sqrt2/2), cy-int(float64(r)*math.Sqrt2/2)
+		c.d.text(lx+scale, ly-glyphHeight*scale, label, theme.text, scale)
+	}
+	c.d.rect(image.Rect(cx-radius, cy, cx+radius+1, cy+1), theme.grid)
+	c.d.rect(image.Rect(cx, cy-radius, cx+1, cy+radius+1), theme.grid)
+
+	// Sektoren als Ringausschnitte je Geschwindigkeitsklasse; zwischen den Sektoren bleibt ein Spalt
+	sectorWidth := 360.0 / windSectors
+	for sector, limits := range cumulative {
+		center := float64(sector) * sectorWidth
+		inner := 0.0
+		for class, limit := range limits {
+			outer := limit / rMax * float64(radius)
+			c.d.sector(cx, cy, inner, outer, center-sectorWidth*0.4, center+sectorWidth*0.4, theme.scale[class%len(theme.scale)])
+			inner = outer
+		}
+	}
+
+	// Himmelsrichtungen
+	for i, name := range []string{"N", "O", "S", "W"} {
+		label := tr(config, name)
+		a := float64(i) * math.Pi / 2
+		lx := cx + int(math.Round(math.Sin(a)*float64(radius+6*scale)))
+		ly := cy - int(math.Round(math.Cos(a)*float64(radius+6*scale)))
+		c.d.text(lx-textWidth(label, scale)/2, ly-glyphHeight*scale/2, label, theme.text, scale)
+	}
+
+	// Legende der Geschwindigkeitsklassen in der Anzeigeeinheit
+	unit := unitOf(config, quantityWind)
+	lx, ly := cx+radius+16*scale, c.plot.Min.Y
+	c.d.text(lx, ly, unit.symbol, theme.text, scale)
+	lower := windCalm
+	for class := 0; class <= len(windClassBounds); class++ {
+		ly += (glyphHeight + 5) * scale
+		label := unit.number(lower, 0) + "+"
+		if class < len(windClassBounds) {
+			label = unit.number(lower, 0) + "-" + unit.number(windClassBounds[class], 0)
+			lower = windClassBounds[class]
+		}
+		c.d.rect(image.Rect(lx, ly, lx+glyphHeight*scale, ly+glyphHeight*scale), theme.scale[class%len(theme.scale)])
+		c.d.text(lx+(glyphHeight+3)*scale, ly, label, theme.text, scale)
+	}
+	ly += 2 * (glyphHeight + 5) * scale
+	c.d.text(lx, ly, tr(config, "Windstille %.0f%%", d.share(d.calm)), theme.text, scale)
+	return c.d, nil
+}
+
+// altTextWindRose beschreibt die Windrose, z.B. "Windrose: vorherrschend aus Südwest (35 %), 8 % Windstille"
+func altTextWindRose(config Config, d windDistribution) string {
+	direction, share := d.prevailing()
+	if direction < 0 {
+		return tr(config, "Windrose: Windstille")
+	}
+	return tr(config, "Windrose: vorherrschend aus %s (%.0f %%), %.0f %% Windstille",
+		tr(config, compassNames[direction]), share, d.share(d.calm))
+}